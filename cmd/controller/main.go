@@ -18,9 +18,18 @@ package main
 import (
 	"flag"
 	"fmt"
+	"log"
 
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations/comments"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations/github"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations/jira"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations/servicenow"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations/teams"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/reconciler/approvalrecord"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/reconciler/approvaltask"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/results"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/utils/clock"
 	filteredinformerfactory "knative.dev/pkg/client/injection/kube/informers/factory/filtered"
@@ -45,7 +54,22 @@ func main() {
 
 	ctx := injection.WithNamespaceScope(signals.NewContext(), *namespace)
 	ctx = filteredinformerfactory.WithSelectors(ctx, v1alpha1.ManagedByLabelKey)
+
+	// Serve the inbound callbacks of the integrations, e.g. Teams card actions.
+	go func() {
+		if err := integrations.ServeCallbacks(ctx); err != nil {
+			log.Fatalf("Failed to serve integration callbacks: %v", err)
+		}
+	}()
+
 	sharedmain.MainWithConfig(ctx, ControllerLogKey, cfg,
 		approvaltask.NewController(clock.RealClock{}),
+		approvalrecord.NewController(clock.RealClock{}),
+		teams.NewController,
+		github.NewController,
+		comments.NewController,
+		jira.NewController,
+		servicenow.NewController,
+		results.NewController,
 	)
 }
@@ -2,9 +2,17 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/audit"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/policy"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/reconciler/webhook"
+	"k8s.io/client-go/kubernetes"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
@@ -14,12 +22,18 @@ import (
 	"knative.dev/pkg/webhook/certificates"
 )
 
-func newValidationAdmissionController(name string) func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+func newValidationAdmissionController(name string, auditLogger *audit.Logger, policyHook *policy.Hook) func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
 	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
-		return webhook.NewAdmissionController(ctx,
+		return webhook.NewAdmissionController(ctx, cmw,
 			name,
 			"/approval-validation",
 			func(ctx context.Context) context.Context {
+				if auditLogger != nil {
+					ctx = audit.WithLogger(ctx, auditLogger)
+				}
+				if policyHook != nil {
+					ctx = policy.WithHook(ctx, policyHook)
+				}
 				return ctx
 			},
 			true,
@@ -27,6 +41,16 @@ func newValidationAdmissionController(name string) func(ctx context.Context, cmw
 	}
 }
 
+func newMutationAdmissionController(name string) func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+		return webhook.NewMutatingAdmissionController(ctx, cmw,
+			name,
+			"/approval-mutation",
+			nil,
+		)
+	}
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -35,14 +59,100 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return value
 }
 
+// newAuditLogger returns the audit Logger configured by AUDIT_SINK, nil when
+// auditing is disabled. The key of the hashes and the head of the chain are
+// kept in namespace, out of reach of the tenants.
+func newAuditLogger(ctx context.Context, kube kubernetes.Interface, namespace string) (*audit.Logger, error) {
+	var sink audit.Sink
+	switch name := os.Getenv("AUDIT_SINK"); name {
+	case "":
+		return nil, nil
+	case "stdout":
+		sink = audit.NewWriterSink(os.Stdout)
+	case "file":
+		path := os.Getenv("AUDIT_FILE")
+		if path == "" {
+			return nil, fmt.Errorf("AUDIT_FILE must be set for the file audit sink")
+		}
+		fileSink, err := audit.NewFileSink(path)
+		if err != nil {
+			return nil, err
+		}
+		sink = fileSink
+	case "http":
+		url := os.Getenv("AUDIT_URL")
+		if url == "" {
+			return nil, fmt.Errorf("AUDIT_URL must be set for the http audit sink")
+		}
+		var token string
+		if tokenFile := os.Getenv("AUDIT_TOKEN_FILE"); tokenFile != "" {
+			b, err := os.ReadFile(tokenFile)
+			if err != nil {
+				return nil, err
+			}
+			token = strings.TrimSpace(string(b))
+		}
+		client := &http.Client{Timeout: 5 * time.Second}
+		sink = audit.NewHTTPSink(client, url, token)
+	default:
+		return nil, fmt.Errorf("unknown audit sink %q, expected stdout, file or http", name)
+	}
+	key, err := audit.LoadKey(ctx, kube, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the key of the audit log: %w", err)
+	}
+	return audit.NewLogger(sink, key, audit.NewConfigMapHeadStore(kube, namespace)), nil
+}
+
+// newPolicyHook returns the external policy Hook configured by POLICY_URL,
+// nil when there is none.
+func newPolicyHook() (*policy.Hook, error) {
+	url := os.Getenv("POLICY_URL")
+	if url == "" {
+		return nil, nil
+	}
+	failurePolicy, err := policy.ParseFailurePolicy(os.Getenv("POLICY_FAILURE_MODE"))
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := time.ParseDuration(getEnvOrDefault("POLICY_TIMEOUT", "3s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid POLICY_TIMEOUT: %w", err)
+	}
+	var token string
+	if tokenFile := os.Getenv("POLICY_TOKEN_FILE"); tokenFile != "" {
+		b, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return nil, err
+		}
+		token = strings.TrimSpace(string(b))
+	}
+	client := &http.Client{Timeout: timeout}
+	return &policy.Hook{
+		Authorizer:    policy.NewHTTPAuthorizer(client, url, token),
+		FailurePolicy: failurePolicy,
+	}, nil
+}
+
 func main() {
 	serviceName := getEnvOrDefault("WEBHOOK_SERVICE_NAME", "manual-approval-webhook")
 	secretName := getEnvOrDefault("WEBHOOK_SECRET_NAME", "manual-approval-gate-webhook-certs")
 	webhookName := getEnvOrDefault("WEBHOOK_ADMISSION_CONTROLLER_NAME", "validation.webhook.manual-approval.openshift-pipelines.org")
+	mutatingWebhookName := getEnvOrDefault("WEBHOOK_MUTATING_ADMISSION_CONTROLLER_NAME", "mutation.webhook.manual-approval.openshift-pipelines.org")
 
 	systemNamespace := os.Getenv("SYSTEM_NAMESPACE")
 	// Scope informers to the webhook's namespace instead of cluster-wide
 	ctx := injection.WithNamespaceScope(signals.NewContext(), systemNamespace)
+	cfg := injection.ParseAndGetRESTConfigOrDie()
+
+	auditLogger, err := newAuditLogger(ctx, kubernetes.NewForConfigOrDie(cfg), systemNamespace)
+	if err != nil {
+		log.Fatalf("Failed to set up the audit log: %v", err)
+	}
+	policyHook, err := newPolicyHook()
+	if err != nil {
+		log.Fatalf("Failed to set up the external policy: %v", err)
+	}
 
 	// Set up a signal context with our webhook options
 	ctx = kwebhook.WithOptions(ctx, kwebhook.Options{
@@ -52,8 +162,9 @@ func main() {
 	})
 
 	sharedmain.WebhookMainWithConfig(ctx, serviceName,
-		injection.ParseAndGetRESTConfigOrDie(),
+		cfg,
 		certificates.NewController,
-		newValidationAdmissionController(webhookName),
+		newMutationAdmissionController(mutatingWebhookName),
+		newValidationAdmissionController(webhookName, auditLogger, policyHook),
 	)
 }
@@ -2,12 +2,16 @@ package actions
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"sync"
 
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/signature"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -88,70 +92,32 @@ func Update(gr schema.GroupVersionResource, c *cli.Clients, opts *cli.Options) e
 		return err
 	}
 
-	if !containsUsername(at.Spec.Approvers, opts) {
+	isMember, err := groupMembership(c, at, opts)
+	if err != nil {
+		return err
+	}
+
+	if !containsUsername(at.Spec.Approvers, opts, isMember) {
 		return fmt.Errorf("approver: %s, is not present in the approvers list", opts.Username)
 	}
 
-	if err := update(gvr, c.Dynamic, at, opts); err != nil {
+	if ok, err := respond(c, at, opts); ok || err != nil {
+		return err
+	}
+
+	if err := update(gvr, c.Dynamic, at, opts, isMember); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func update(gvr *schema.GroupVersionResource, dynamic dynamic.Interface, at *v1alpha1.ApprovalTask, opts *cli.Options) error {
-	// Track if user has been processed as individual User type to avoid duplicate processing
-	userProcessedAsIndividual := false
+func update(gvr *schema.GroupVersionResource, dynamic dynamic.Interface, at *v1alpha1.ApprovalTask, opts *cli.Options, isMember func(v1alpha1.ApproverDetails) bool) error {
+	at.SetApproverInputMatching(opts.Username, opts.Identity.Matcher(opts.Username), isMember, opts.Input, opts.Message)
 
-	// First pass: Process all User type approvers to ensure User type takes precedence
-	for i, approver := range at.Spec.Approvers {
-		if v1alpha1.DefaultedApproverType(approver.Type) == "User" && approver.Name == opts.Username {
-			at.Spec.Approvers[i].Input = opts.Input
-			if opts.Message != "" {
-				at.Spec.Approvers[i].Message = opts.Message
-			}
-			userProcessedAsIndividual = true
-		}
-	}
-
-	// Second pass: Process Group type approvers, but only add user to group if not already processed as individual
-	for i, approver := range at.Spec.Approvers {
-		if v1alpha1.DefaultedApproverType(approver.Type) == "Group" {
-			for _, groupName := range opts.Groups {
-				if approver.Name == groupName {
-					at.Spec.Approvers[i].Input = opts.Input
-					if opts.Message != "" {
-						at.Spec.Approvers[i].Message = opts.Message
-					}
-
-					// Only add user to group members if they haven't been processed as individual User
-					// This prevents duplicate entries when user is both individual approver and group member
-					if !userProcessedAsIndividual {
-						userExists := false
-
-						for j, existing := range at.Spec.Approvers[i].Users {
-							if existing.Name == opts.Username {
-								userExists = true
-								if existing.Input != opts.Input {
-									at.Spec.Approvers[i].Users[j].Input = opts.Input
-								}
-								if existing.Message != opts.Message {
-									at.Spec.Approvers[i].Users[j].Message = opts.Message
-								}
-								break
-							}
-						}
-						if !userExists {
-							newUser := v1alpha1.UserDetails{
-								Name:  opts.Username,
-								Input: opts.Input,
-								Message: opts.Message,
-							}
-							at.Spec.Approvers[i].Users = append(at.Spec.Approvers[i].Users, newUser)
-						}
-					}
-				}
-			}
+	if opts.SigningKey != "" {
+		if err := sign(at, opts, isMember); err != nil {
+			return err
 		}
 	}
 
@@ -170,6 +136,95 @@ func update(gvr *schema.GroupVersionResource, dynamic dynamic.Interface, at *v1a
 	return nil
 }
 
+// respond records the input through the approve or reject subresource,
+// which only needs create on the subresource, when its APIService is
+// installed. It returns false when the subresources are not available.
+func respond(c *cli.Clients, at *v1alpha1.ApprovalTask, opts *cli.Options) (bool, error) {
+	groupVersion := v1alpha1.SubresourceGroupName + "/" + v1alpha1.SchemeGroupVersion.Version
+	if _, err := c.ApprovalTask.Discovery().ServerResourcesForGroupVersion(groupVersion); err != nil {
+		return false, nil
+	}
+
+	body := v1alpha1.SubresourceRequest{Message: opts.Message}
+	if opts.SigningKey != "" {
+		sig, err := signature.Sign(opts.SigningKey, signature.Response{
+			Namespace: at.Namespace,
+			Name:      at.Name,
+			UID:       string(at.UID),
+			User:      opts.Username,
+			Input:     opts.Input,
+			Message:   opts.Message,
+		})
+		if err != nil {
+			return true, err
+		}
+		body.Signature = sig
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return true, err
+	}
+
+	return true, c.ApprovalTask.Discovery().RESTClient().Post().
+		AbsPath("/apis", groupVersion, "namespaces", at.Namespace, "approvaltasks", at.Name, opts.Input).
+		SetHeader("Content-Type", "application/json").
+		Body(data).
+		Do(context.Background()).
+		Error()
+}
+
+// sign signs every approver entry the input of the user was set on, each
+// with the message it ends up with.
+func sign(at *v1alpha1.ApprovalTask, opts *cli.Options, isMember func(v1alpha1.ApproverDetails) bool) error {
+	signed := map[[2]string]string{}
+	signEntry := func(input, message string) (string, error) {
+		if sig, ok := signed[[2]string{input, message}]; ok {
+			return sig, nil
+		}
+		sig, err := signature.Sign(opts.SigningKey, signature.Response{
+			Namespace: at.Namespace,
+			Name:      at.Name,
+			UID:       string(at.UID),
+			User:      opts.Username,
+			Input:     input,
+			Message:   message,
+		})
+		if err != nil {
+			return "", err
+		}
+		signed[[2]string{input, message}] = sig
+		return sig, nil
+	}
+
+	var err error
+	isUser := opts.Identity.Matcher(opts.Username)
+	for i, approver := range at.Spec.Approvers {
+		switch v1alpha1.DefaultedApproverType(approver.Type) {
+		case "User", v1alpha1.ServiceAccountApproverType:
+			if isUser(approver.Name) {
+				if at.Spec.Approvers[i].Signature, err = signEntry(approver.Input, approver.Message); err != nil {
+					return err
+				}
+			}
+		case "Group", v1alpha1.RBACApproverType:
+			if !isMember(approver) {
+				continue
+			}
+			if at.Spec.Approvers[i].Signature, err = signEntry(approver.Input, approver.Message); err != nil {
+				return err
+			}
+			for j, user := range approver.Users {
+				if isUser(user.Name) {
+					if at.Spec.Approvers[i].Users[j].Signature, err = signEntry(user.Input, user.Message); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func GetGroupVersionResource(gr schema.GroupVersionResource, discovery discovery.DiscoveryInterface) (*schema.GroupVersionResource, error) {
 	var err error
 	doOnce.Do(func() {
@@ -198,24 +253,70 @@ func InitializeAPIGroupRes(discovery discovery.DiscoveryInterface) error {
 	return nil
 }
 
-func containsUsername(approvers []v1alpha1.ApproverDetails, user *cli.Options) bool {
+// groupMembership returns a function telling whether the user is a member of
+// a group approver of the ApprovalTask, like the admission webhook does: of a
+// Group approver when they belong to the group or its members resolved by the
+// controller, and of an RBAC approver when a SelfSubjectAccessReview allows
+// them to approve the ApprovalTask.
+func groupMembership(c *cli.Clients, at *v1alpha1.ApprovalTask, opts *cli.Options) (func(v1alpha1.ApproverDetails) bool, error) {
+	isUser := opts.Identity.Matcher(opts.Username)
+	allowed := map[string]bool{}
+	for _, approver := range at.Spec.Approvers {
+		if v1alpha1.DefaultedApproverType(approver.Type) != v1alpha1.RBACApproverType {
+			continue
+		}
+		if _, checked := allowed[approver.Name]; checked {
+			continue
+		}
+		resourceName := approver.Name
+		if resourceName == v1alpha1.AnyApprovalTask {
+			resourceName = ""
+		}
+		review, err := c.Kube.AuthorizationV1().SelfSubjectAccessReviews().Create(context.Background(), &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: at.Namespace,
+					Verb:      v1alpha1.ApproveVerb,
+					Group:     v1alpha1.SchemeGroupVersion.Group,
+					Resource:  "approvaltasks",
+					Name:      resourceName,
+				},
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to check whether %s can approve: %v", opts.Username, err)
+		}
+		allowed[approver.Name] = review.Status.Allowed
+	}
+
+	return func(approver v1alpha1.ApproverDetails) bool {
+		switch v1alpha1.DefaultedApproverType(approver.Type) {
+		case "Group":
+			return slices.Contains(opts.Groups, approver.Name) || slices.ContainsFunc(at.ResolvedMembers(approver.Name), isUser)
+		case v1alpha1.RBACApproverType:
+			return allowed[approver.Name]
+		}
+		return false
+	}, nil
+}
+
+func containsUsername(approvers []v1alpha1.ApproverDetails, user *cli.Options, isMember func(v1alpha1.ApproverDetails) bool) bool {
+	isUser := user.Identity.Matcher(user.Username)
 	for _, approver := range approvers {
-		if approver.Name == user.Username {
+		if isUser(approver.Name) {
 			return true
 		}
 	}
 
 	for _, approval := range approvers {
 		switch approval.Type {
-		case "User":
-			if approval.Name == user.Username {
+		case "User", v1alpha1.ServiceAccountApproverType:
+			if isUser(approval.Name) {
 				return true
 			}
-		case "Group":
-			for _, groupName := range user.Groups {
-				if approval.Name == groupName {
-					return true
-				}
+		case "Group", v1alpha1.RBACApproverType:
+			if isMember(approval) {
+				return true
 			}
 		}
 	}
@@ -0,0 +1,23 @@
+package actions
+
+import (
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var denialGuidance = map[metav1.StatusReason]string{
+	v1alpha1.DenialReasonNotAnApprover:  "You are not an approver of this ApprovalTask, nor a member of one of its groups. Check its approvers with `tkn-approvaltask describe`.",
+	v1alpha1.DenialReasonAlreadyDecided: "You have already responded to this ApprovalTask, a response cannot be changed.",
+	v1alpha1.DenialReasonFinalState:     "This ApprovalTask is already approved or rejected, there is nothing left to decide.",
+	v1alpha1.DenialReasonInvalidInput:   "The response is invalid, e.g. its signature. Fix what the message above reports and try again.",
+	v1alpha1.DenialReasonForeignChange:  "Only your own response can be changed. The ApprovalTask may have changed meanwhile, try again.",
+	v1alpha1.DenialReasonPolicyDenied:   "A policy of the cluster denied the response. Ask its administrators about the policy named above.",
+}
+
+// Guidance returns what the user can do about err, when it is a denial of
+// the admission webhook with one of the v1alpha1 DenialReasons, and an empty
+// string otherwise.
+func Guidance(err error) string {
+	return denialGuidance[apierrors.ReasonForError(err)]
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ApprovalTaskLabelKey labels the ApprovalRecord with the name of the
+	// ApprovalTask it archives.
+	ApprovalTaskLabelKey = "openshift-pipelines.org/approvalTask"
+
+	// ApprovalRecordDecisionLabelKey labels the ApprovalRecord with its
+	// decision.
+	ApprovalRecordDecisionLabelKey = "openshift-pipelines.org/decision"
+
+	// ApprovalRecordAttestationAnnotationKey annotates the ApprovalRecord with
+	// the signed in-toto attestation of its decision, as a DSSE envelope.
+	ApprovalRecordAttestationAnnotationKey = "openshift-pipelines.org/attestation"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// ApprovalRecord is the immutable archive of an ApprovalTask which reached a
+// final state. It outlives the ApprovalTask, which is garbage collected with
+// its CustomRun.
+// +k8s:openapi-gen=true
+type ApprovalRecord struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec ApprovalRecordSpec `json:"spec"`
+}
+
+type ApprovalRecordSpec struct {
+	// ApprovalTask identifies the archived ApprovalTask
+	ApprovalTask ApprovalRecordReference `json:"approvalTask"`
+	// PipelineRun identifies the PipelineRun the ApprovalTask belonged to
+	// +optional
+	PipelineRun *ApprovalRecordPipelineRun `json:"pipelineRun,omitempty"`
+
+	// Approvers, NumberOfApprovalsRequired and Description are copied from
+	// the spec of the ApprovalTask
+	Approvers                 []ApproverDetails `json:"approvers"`
+	NumberOfApprovalsRequired int               `json:"numberOfApprovalsRequired"`
+	Description               string            `json:"description,omitempty"`
+
+	// Decision is approved, rejected, timed_out or cancelled
+	Decision string `json:"decision"`
+	// ApprovalsReceived is the number of approvals received before the decision
+	ApprovalsReceived int `json:"approvalsReceived,omitempty"`
+	// History holds the responses of the approvers, and of the members of
+	// the group approvers
+	History []ApproverState `json:"history,omitempty"`
+
+	// StartTime is the time the ApprovalTask started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time the decision was made
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// ExpirationTime is the time after which the ApprovalRecord is deleted,
+	// it is kept forever when unset
+	// +optional
+	ExpirationTime *metav1.Time `json:"expirationTime,omitempty"`
+}
+
+// ApprovalRecordReference identifies an object which may no longer exist.
+type ApprovalRecordReference struct {
+	Name string `json:"name"`
+	UID  string `json:"uid,omitempty"`
+}
+
+// ApprovalRecordPipelineRun identifies the PipelineRun of an ApprovalTask.
+type ApprovalRecordPipelineRun struct {
+	Name         string `json:"name"`
+	UID          string `json:"uid,omitempty"`
+	Pipeline     string `json:"pipeline,omitempty"`
+	PipelineTask string `json:"pipelineTask,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ApprovalRecordList contains a list of ApprovalRecords
+type ApprovalRecordList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ApprovalRecord `json:"items"`
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// The reasons the admission webhook sets in the Status of the changes of
+// ApprovalTasks and ApprovalRecords it denies, so that clients can react to them without parsing
+// the message. They are stable: new ones may be added, none is renamed.
+const (
+	// DenialReasonNotAnApprover is set when the user is not one of the
+	// approvers, nor a member of one of the group approvers
+	DenialReasonNotAnApprover metav1.StatusReason = "NotAnApprover"
+
+	// DenialReasonAlreadyDecided is set when the user already responded
+	DenialReasonAlreadyDecided metav1.StatusReason = "AlreadyDecided"
+
+	// DenialReasonFinalState is set when the ApprovalTask is already
+	// approved or rejected, or on changes to an ApprovalRecord
+	DenialReasonFinalState metav1.StatusReason = "FinalState"
+
+	// DenialReasonInvalidInput is set when the ApprovalTask, or the response
+	// of the user, its signature or its stamps, is invalid
+	DenialReasonInvalidInput metav1.StatusReason = "InvalidInput"
+
+	// DenialReasonForeignChange is set when the change is not the one of the
+	// user, e.g. to the response of another approver or to the status
+	DenialReasonForeignChange metav1.StatusReason = "ForeignChange"
+
+	// DenialReasonPolicyDenied is set when a policy denies the change, e.g.
+	// the external policy, the impersonation policy, the deletion guard or
+	// the creation of an ApprovalRecord by anyone but the controller
+	DenialReasonPolicyDenied metav1.StatusReason = "PolicyDenied"
+)
@@ -0,0 +1,46 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// ChangeRequestApproverType is the type of an approver answered by a
+	// ServiceNow change request rather than by a user.
+	ChangeRequestApproverType = "ChangeRequest"
+
+	// NewChangeRequest is the name of a ChangeRequest approver for which the
+	// controller creates the change request, instead of the number of an
+	// existing one.
+	NewChangeRequest = "new"
+)
+
+// IsExternalApproverType returns true for the approver types answered by the
+// controller on behalf of an external system. Their input in the spec stays
+// pending: their response is only recorded in status.approversResponse.
+func IsExternalApproverType(approverType string) bool {
+	return approverType == ChangeRequestApproverType
+}
+
+// ExternalResponses returns the responses recorded for external approvers.
+func (at *ApprovalTask) ExternalResponses() []ApproverState {
+	var responses []ApproverState
+	for _, response := range at.Status.ApproversResponse {
+		if IsExternalApproverType(response.Type) {
+			responses = append(responses, response)
+		}
+	}
+	return responses
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// ResolvedMembers returns the members of the Group approver group snapshotted
+// in the status by the controller.
+func (at *ApprovalTask) ResolvedMembers(group string) []string {
+	for _, resolved := range at.Status.ResolvedGroups {
+		if resolved.Name == group {
+			return resolved.Members
+		}
+	}
+	return nil
+}
+
+// PendingApprovers returns the users who may still answer the ApprovalTask:
+// the User approvers and the resolved members of the Group approvers who did
+// not answer yet. ok is false when they are not known, i.e. for RBAC and
+// external approvers, and Group approvers whose members were not resolved.
+// Usernames normalize returns the same username for are the same user.
+func (at *ApprovalTask) PendingApprovers(normalize func(string) string) (approvers []string, ok bool) {
+	answered := make(map[string]bool)
+	for _, approver := range at.Spec.Approvers {
+		if IsUserApproverType(approver.Type) && approver.Input != "pending" {
+			answered[normalize(approver.Name)] = true
+		}
+		for _, user := range approver.Users {
+			answered[normalize(user.Name)] = true
+		}
+	}
+
+	resolved := make(map[string]bool, len(at.Status.ResolvedGroups))
+	for _, group := range at.Status.ResolvedGroups {
+		resolved[group.Name] = true
+	}
+
+	seen := make(map[string]bool)
+	add := func(user string) {
+		if normalized := normalize(user); !answered[normalized] && !seen[normalized] {
+			seen[normalized] = true
+			approvers = append(approvers, user)
+		}
+	}
+	for _, approver := range at.Spec.Approvers {
+		switch DefaultedApproverType(approver.Type) {
+		case "User", ServiceAccountApproverType:
+			add(approver.Name)
+		case "Group":
+			if !resolved[approver.Name] {
+				return nil, false
+			}
+			for _, member := range at.ResolvedMembers(approver.Name) {
+				add(member)
+			}
+		default:
+			return nil, false
+		}
+	}
+	return approvers, true
+}
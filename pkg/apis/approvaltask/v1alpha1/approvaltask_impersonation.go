@@ -0,0 +1,50 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// ImpersonatorExtraKey is the user extra naming who impersonates the
+	// user, set with the Impersonate-Extra- headers, e.g.
+	// kubectl --as=alice --as-user-extra=approvals.openshift-pipelines.org/impersonator=admin
+	// The API server does not tell admission webhooks that a request is
+	// impersonated otherwise.
+	ImpersonatorExtraKey = "approvals.openshift-pipelines.org/impersonator"
+
+	// ControllerImpersonator is the impersonator the controller sets when its
+	// integrations record a decision as the approver
+	ControllerImpersonator = "manual-approval-gate-controller"
+)
+
+// SetImpersonatedBy records impersonator on every approver entry the input of
+// the user was set on, as SetApproverInputMatching sets it.
+func (at *ApprovalTask) SetImpersonatedBy(isUser func(string) bool, isMember func(ApproverDetails) bool, impersonator string) {
+	for i, approver := range at.Spec.Approvers {
+		if IsGroupApproverType(approver.Type) {
+			if !isMember(approver) {
+				continue
+			}
+			at.Spec.Approvers[i].ImpersonatedBy = impersonator
+			for j, user := range approver.Users {
+				if isUser(user.Name) {
+					at.Spec.Approvers[i].Users[j].ImpersonatedBy = impersonator
+				}
+			}
+		} else if IsUserApproverType(approver.Type) && isUser(approver.Name) {
+			at.Spec.Approvers[i].ImpersonatedBy = impersonator
+		}
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// SetApproverInput records input and message for username in the spec of the
+// ApprovalTask, the same way an approver editing the object by hand would.
+// User entries take precedence: a user listed individually is not also added
+// to the members of the groups they belong to.
+func (at *ApprovalTask) SetApproverInput(username string, groups []string, input, message string) {
+	at.SetApproverInputAs(username, MemberOfGroups(groups), input, message)
+}
+
+// MemberOfGroups returns the membership of a user in the Group approvers
+// named after one of groups.
+func MemberOfGroups(groups []string) func(ApproverDetails) bool {
+	return func(approver ApproverDetails) bool {
+		if DefaultedApproverType(approver.Type) != "Group" {
+			return false
+		}
+		for _, group := range groups {
+			if approver.Name == group {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SetApproverInputAs is SetApproverInput for a user who is a member of the
+// group approvers, e.g. Group or RBAC, for which isMember returns true.
+func (at *ApprovalTask) SetApproverInputAs(username string, isMember func(ApproverDetails) bool, input, message string) {
+	at.SetApproverInputMatching(username, func(name string) bool { return name == username }, isMember, input, message)
+}
+
+// SetApproverInputMatching is SetApproverInputAs for a user who is also
+// listed under the other usernames isUser returns true for, e.g. their
+// aliases. The user is added to the members of the groups as username.
+func (at *ApprovalTask) SetApproverInputMatching(username string, isUser func(string) bool, isMember func(ApproverDetails) bool, input, message string) {
+	// Track if user has been processed as individual User type to avoid duplicate processing
+	userProcessedAsIndividual := false
+
+	// First pass: Process all User type approvers to ensure User type takes precedence
+	for i, approver := range at.Spec.Approvers {
+		if IsUserApproverType(approver.Type) && isUser(approver.Name) {
+			at.Spec.Approvers[i].Input = input
+			if message != "" {
+				at.Spec.Approvers[i].Message = message
+			}
+			userProcessedAsIndividual = true
+		}
+	}
+
+	// Second pass: Process group approvers, but only add user to group if not already processed as individual
+	for i, approver := range at.Spec.Approvers {
+		if !IsGroupApproverType(approver.Type) || !isMember(approver) {
+			continue
+		}
+		at.Spec.Approvers[i].Input = input
+		if message != "" {
+			at.Spec.Approvers[i].Message = message
+		}
+
+		// Only add user to group members if they haven't been processed as individual User
+		// This prevents duplicate entries when user is both individual approver and group member
+		if userProcessedAsIndividual {
+			continue
+		}
+		userExists := false
+		for j, existing := range at.Spec.Approvers[i].Users {
+			if isUser(existing.Name) {
+				userExists = true
+				at.Spec.Approvers[i].Users[j].Input = input
+				at.Spec.Approvers[i].Users[j].Message = message
+				break
+			}
+		}
+		if !userExists {
+			at.Spec.Approvers[i].Users = append(at.Spec.Approvers[i].Users, UserDetails{
+				Name:    username,
+				Input:   input,
+				Message: message,
+			})
+		}
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// RBACApproverType is the type of an approver whose members are the users
+	// RBAC allows to approve the ApprovalTask, i.e. to whom a
+	// SubjectAccessReview grants ApproveVerb on approvaltasks.
+	RBACApproverType = "RBAC"
+
+	// ApproveVerb is the custom verb granting approval rights on approvaltasks
+	ApproveVerb = "approve"
+
+	// DeletePendingVerb is the custom verb of the break-glass role, allowing
+	// to delete approvaltasks which are still pending
+	DeletePendingVerb = "delete-pending"
+
+	// AnyApprovalTask is the name of an RBAC approver not scoped to a
+	// resourceName, whose members must be allowed to approve any ApprovalTask
+	// of the namespace.
+	AnyApprovalTask = "*"
+)
+
+// IsGroupApproverType returns true for the approver types standing for a
+// set of users, each of whom answers as a member in users.
+func IsGroupApproverType(approverType string) bool {
+	t := DefaultedApproverType(approverType)
+	return t == "Group" || t == RBACApproverType
+}
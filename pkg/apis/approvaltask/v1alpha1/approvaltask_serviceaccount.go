@@ -0,0 +1,79 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// ServiceAccountApproverType is the type of an approver that is a
+	// ServiceAccount, i.e. automation such as a canary analysis job, named
+	// by its username system:serviceaccount:<namespace>:<name>.
+	ServiceAccountApproverType = "ServiceAccount"
+
+	// ServiceAccountUsernamePrefix prefixes the usernames of ServiceAccounts
+	ServiceAccountUsernamePrefix = "system:serviceaccount:"
+)
+
+// ServiceAccountUsername returns the username of the ServiceAccount.
+func ServiceAccountUsername(namespace, name string) string {
+	return ServiceAccountUsernamePrefix + namespace + ":" + name
+}
+
+// ParseServiceAccountUsername returns the namespace and the name of the
+// ServiceAccount of username, an error when they are not valid names.
+func ParseServiceAccountUsername(username string) (namespace, name string, err error) {
+	if !IsServiceAccount(username) {
+		return "", "", fmt.Errorf("'%s' is not a ServiceAccount username, use '%s<namespace>:<name>'", username, ServiceAccountUsernamePrefix)
+	}
+	parts := strings.Split(strings.TrimPrefix(username, ServiceAccountUsernamePrefix), ":")
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("'%s' must be '%s<namespace>:<name>'", username, ServiceAccountUsernamePrefix)
+	}
+	namespace, name = parts[0], parts[1]
+	if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid ServiceAccount namespace '%s': %s", namespace, strings.Join(errs, ", "))
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid ServiceAccount name '%s': %s", name, strings.Join(errs, ", "))
+	}
+	return namespace, name, nil
+}
+
+// IsServiceAccount returns true when username is the username of a
+// ServiceAccount, whose responses are automated.
+func IsServiceAccount(username string) bool {
+	return strings.HasPrefix(username, ServiceAccountUsernamePrefix)
+}
+
+// IsUserApproverType returns true for the approver types standing for a
+// single user, who answers in the input of the approver.
+func IsUserApproverType(approverType string) bool {
+	t := DefaultedApproverType(approverType)
+	return t == "User" || t == ServiceAccountApproverType
+}
+
+// CountsTowardQuorum returns true when the approval of username counts
+// toward the approvals required, i.e. unless username is a ServiceAccount
+// and the ApprovalTask excludes automated approvals.
+func (at *ApprovalTask) CountsTowardQuorum(username string) bool {
+	return !at.Status.ExcludeAutomatedApprovals || !IsServiceAccount(username)
+}
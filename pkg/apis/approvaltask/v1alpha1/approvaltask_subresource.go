@@ -0,0 +1,34 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+const (
+	// SubresourceGroupName is the API group of the approve and reject
+	// subresources of the ApprovalTasks, served by the webhook through an
+	// APIService
+	SubresourceGroupName = "subresources.openshift-pipelines.org"
+)
+
+// SubresourceRequest is the body of a request to the approve or reject
+// subresource of an ApprovalTask. The approver is the user making the
+// request.
+type SubresourceRequest struct {
+	// Message is the message of the approver
+	Message string `json:"message,omitempty"`
+	// Signature is the signature of the response, see pkg/signature
+	Signature string `json:"signature,omitempty"`
+}
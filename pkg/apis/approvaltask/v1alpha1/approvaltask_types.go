@@ -50,6 +50,14 @@ type UserDetails struct {
 	Name  string `json:"name"`
 	Input string `json:"input"`
 	Message string `json:"message,omitempty"`
+	// Signature of the input by the user, see the signature package
+	Signature string `json:"signature,omitempty"`
+	// ImpersonatedBy is who gave the input on behalf of the user through
+	// impersonation, see ImpersonatorExtraKey
+	ImpersonatedBy string `json:"impersonatedBy,omitempty"`
+	// Responder is the identity the API server authenticated for the input,
+	// stamped by the mutating admission webhook
+	Responder *Responder `json:"responder,omitempty"`
 }
 
 type ApproverDetails struct {
@@ -58,8 +66,32 @@ type ApproverDetails struct {
 	Message string        `json:"message,omitempty"`
 	Type    string        `json:"type"`
 	Users   []UserDetails `json:"users,omitempty"`
+	// Signature of the input by the user who gave it, see the signature
+	// package
+	Signature string `json:"signature,omitempty"`
+	// ImpersonatedBy is who gave the input on behalf of the user through
+	// impersonation, see ImpersonatorExtraKey
+	ImpersonatedBy string `json:"impersonatedBy,omitempty"`
+	// Responder is the identity the API server authenticated for the input,
+	// stamped by the mutating admission webhook
+	Responder *Responder `json:"responder,omitempty"`
 }
 
+// Responder is the identity of the user who gave the input of an approver,
+// as authenticated by the API server, and when it was admitted. Clients
+// cannot set it: the mutating admission webhook overwrites it.
+type Responder struct {
+	Username string   `json:"username"`
+	UID      string   `json:"uid,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+	// Time is when the input was admitted
+	Time metav1.Time `json:"time"`
+}
+
+// ResultAnnotationPrefix marks the status annotations which are published as
+// results of the CustomRun, the rest of the key being the name of the result.
+const ResultAnnotationPrefix = "results.openshift-pipelines.org/"
+
 type ApprovalTaskStatus struct {
 	duckv1.Status     `json:",inline"`
 	State             string          `json:"state"`
@@ -71,12 +103,31 @@ type ApprovalTaskStatus struct {
 	ApprovalsRequired int `json:"approvalsRequired,omitempty"`
 	// ApprovalsReceived is the number of approvals received so far
 	ApprovalsReceived int `json:"approvalsReceived,omitempty"`
+	// ResolvedGroups are the members of the Group approvers, resolved from
+	// the Group objects of the cluster when the ApprovalTask started
+	ResolvedGroups []ResolvedGroup `json:"resolvedGroups,omitempty"`
+	// ExcludeAutomatedApprovals is true when the approvals of ServiceAccounts
+	// do not count toward the approvals required, set from the policy of the
+	// controller when the ApprovalTask is created
+	ExcludeAutomatedApprovals bool `json:"excludeAutomatedApprovals,omitempty"`
+}
+
+// ResolvedGroup is the snapshot of the members of a Group approver
+type ResolvedGroup struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members,omitempty"`
 }
 
 type GroupMemberState struct {
 	Name     string `json:"name"`
 	Response string `json:"response"`
 	Message  string `json:"message,omitempty"`
+	// Automated is true for the responses of ServiceAccounts
+	Automated bool `json:"automated,omitempty"`
+	// ImpersonatedBy is who responded on behalf of the approver
+	ImpersonatedBy string `json:"impersonatedBy,omitempty"`
+	// Responder is the identity of the response, see Responder
+	Responder *Responder `json:"responder,omitempty"`
 }
 
 type ApproverState struct {
@@ -85,6 +136,12 @@ type ApproverState struct {
 	Message      string             `json:"message,omitempty"`
 	Type         string             `json:"type"`
 	GroupMembers []GroupMemberState `json:"groupMembers,omitempty"`
+	// Automated is true for the responses of ServiceAccounts
+	Automated bool `json:"automated,omitempty"`
+	// ImpersonatedBy is who responded on behalf of the approver
+	ImpersonatedBy string `json:"impersonatedBy,omitempty"`
+	// Responder is the identity of the response, see Responder
+	Responder *Responder `json:"responder,omitempty"`
 }
 
 // DefaultedApproverType returns "User" if the type field is empty (for v0.6.0 compatibility),
@@ -143,6 +200,9 @@ const (
 
 	// ApprovalTaskRunReasonInternalError indicates that the ApprovalTask failed due to an internal error in the reconciler
 	ApprovalTaskRunReasonInternalError ApprovalTaskRunReason = "ApprovalTaskInternalError"
+
+	// ApprovalTaskRunReasonApprovalTaskDeleted indicates that the ApprovalTask was deleted before a decision
+	ApprovalTaskRunReasonApprovalTaskDeleted ApprovalTaskRunReason = "ApprovalTaskDeleted"
 )
 
 func (t ApprovalTaskRunReason) String() string {
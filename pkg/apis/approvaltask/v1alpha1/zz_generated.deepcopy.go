@@ -25,6 +25,146 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalRecord) DeepCopyInto(out *ApprovalRecord) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalRecord.
+func (in *ApprovalRecord) DeepCopy() *ApprovalRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApprovalRecord) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalRecordList) DeepCopyInto(out *ApprovalRecordList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ApprovalRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalRecordList.
+func (in *ApprovalRecordList) DeepCopy() *ApprovalRecordList {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalRecordList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ApprovalRecordList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalRecordPipelineRun) DeepCopyInto(out *ApprovalRecordPipelineRun) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalRecordPipelineRun.
+func (in *ApprovalRecordPipelineRun) DeepCopy() *ApprovalRecordPipelineRun {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalRecordPipelineRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalRecordReference) DeepCopyInto(out *ApprovalRecordReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalRecordReference.
+func (in *ApprovalRecordReference) DeepCopy() *ApprovalRecordReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalRecordReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalRecordSpec) DeepCopyInto(out *ApprovalRecordSpec) {
+	*out = *in
+	out.ApprovalTask = in.ApprovalTask
+	if in.PipelineRun != nil {
+		in, out := &in.PipelineRun, &out.PipelineRun
+		*out = new(ApprovalRecordPipelineRun)
+		**out = **in
+	}
+	if in.Approvers != nil {
+		in, out := &in.Approvers, &out.Approvers
+		*out = make([]ApproverDetails, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ApproverState, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpirationTime != nil {
+		in, out := &in.ExpirationTime, &out.ExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalRecordSpec.
+func (in *ApprovalRecordSpec) DeepCopy() *ApprovalRecordSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalRecordSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApprovalTask) DeepCopyInto(out *ApprovalTask) {
 	*out = *in
@@ -150,6 +290,13 @@ func (in *ApprovalTaskStatus) DeepCopyInto(out *ApprovalTaskStatus) {
 		in, out := &in.StartTime, &out.StartTime
 		*out = (*in).DeepCopy()
 	}
+	if in.ResolvedGroups != nil {
+		in, out := &in.ResolvedGroups, &out.ResolvedGroups
+		*out = make([]ResolvedGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -169,7 +316,14 @@ func (in *ApproverDetails) DeepCopyInto(out *ApproverDetails) {
 	if in.Users != nil {
 		in, out := &in.Users, &out.Users
 		*out = make([]UserDetails, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Responder != nil {
+		in, out := &in.Responder, &out.Responder
+		*out = new(Responder)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -190,7 +344,14 @@ func (in *ApproverState) DeepCopyInto(out *ApproverState) {
 	if in.GroupMembers != nil {
 		in, out := &in.GroupMembers, &out.GroupMembers
 		*out = make([]GroupMemberState, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Responder != nil {
+		in, out := &in.Responder, &out.Responder
+		*out = new(Responder)
+		(*in).DeepCopyInto(*out)
 	}
 	return
 }
@@ -208,6 +369,11 @@ func (in *ApproverState) DeepCopy() *ApproverState {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GroupMemberState) DeepCopyInto(out *GroupMemberState) {
 	*out = *in
+	if in.Responder != nil {
+		in, out := &in.Responder, &out.Responder
+		*out = new(Responder)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -221,9 +387,73 @@ func (in *GroupMemberState) DeepCopy() *GroupMemberState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResolvedGroup) DeepCopyInto(out *ResolvedGroup) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedGroup.
+func (in *ResolvedGroup) DeepCopy() *ResolvedGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(ResolvedGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Responder) DeepCopyInto(out *Responder) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Responder.
+func (in *Responder) DeepCopy() *Responder {
+	if in == nil {
+		return nil
+	}
+	out := new(Responder)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubresourceRequest) DeepCopyInto(out *SubresourceRequest) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubresourceRequest.
+func (in *SubresourceRequest) DeepCopy() *SubresourceRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(SubresourceRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UserDetails) DeepCopyInto(out *UserDetails) {
 	*out = *in
+	if in.Responder != nil {
+		in, out := &in.Responder, &out.Responder
+		*out = new(Responder)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -0,0 +1,153 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package attestation produces in-toto attestations of the decisions of the
+// ApprovalTasks, signed in DSSE envelopes that cosign can verify, so that
+// they can be kept with the provenance Tekton Chains produces.
+package attestation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// StatementType is the type of in-toto v1 statements
+	StatementType = "https://in-toto.io/Statement/v1"
+	// PredicateType is the type of the predicate of approval attestations
+	PredicateType = "https://openshift-pipelines.org/attestations/approval/v1"
+	// PayloadType is the DSSE payload type of in-toto statements
+	PayloadType = "application/vnd.in-toto+json"
+)
+
+// Statement is an in-toto v1 statement.
+type Statement struct {
+	Type          string    `json:"_type"`
+	Subject       []Subject `json:"subject"`
+	PredicateType string    `json:"predicateType"`
+	Predicate     Predicate `json:"predicate"`
+}
+
+// Subject is an artifact the statement is about.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Predicate is the decision of an ApprovalTask.
+type Predicate struct {
+	ApprovalTask              Reference                  `json:"approvalTask"`
+	ApprovalRecord            string                     `json:"approvalRecord"`
+	PipelineRun               *PipelineRun               `json:"pipelineRun,omitempty"`
+	Decision                  string                     `json:"decision"`
+	NumberOfApprovalsRequired int                        `json:"numberOfApprovalsRequired"`
+	ApprovalsReceived         int                        `json:"approvalsReceived"`
+	Approvers                 []v1alpha1.ApproverDetails `json:"approvers"`
+	Responses                 []v1alpha1.ApproverState   `json:"responses,omitempty"`
+	StartTime                 *metav1.Time               `json:"startTime,omitempty"`
+	CompletionTime            *metav1.Time               `json:"completionTime,omitempty"`
+}
+
+// Reference identifies the ApprovalTask.
+type Reference struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// PipelineRun identifies the PipelineRun the ApprovalTask belonged to.
+type PipelineRun struct {
+	Name         string `json:"name"`
+	UID          string `json:"uid,omitempty"`
+	Pipeline     string `json:"pipeline,omitempty"`
+	PipelineTask string `json:"pipelineTask,omitempty"`
+}
+
+// NewStatement returns the statement of the decision an ApprovalRecord
+// archives. Its subject is the PipelineRun, or the ApprovalTask when it did
+// not belong to one, digested by UID so that a verifier holding the UID can
+// check the statement is about it.
+func NewStatement(record *v1alpha1.ApprovalRecord) *Statement {
+	predicate := Predicate{
+		ApprovalTask: Reference{
+			Namespace: record.Namespace,
+			Name:      record.Spec.ApprovalTask.Name,
+			UID:       record.Spec.ApprovalTask.UID,
+		},
+		ApprovalRecord:            record.Name,
+		Decision:                  record.Spec.Decision,
+		NumberOfApprovalsRequired: record.Spec.NumberOfApprovalsRequired,
+		ApprovalsReceived:         record.Spec.ApprovalsReceived,
+		Approvers:                 record.Spec.Approvers,
+		Responses:                 record.Spec.History,
+		StartTime:                 record.Spec.StartTime,
+		CompletionTime:            record.Spec.CompletionTime,
+	}
+
+	subject := Subject{
+		Name:   "approvaltasks/" + record.Namespace + "/" + record.Spec.ApprovalTask.Name,
+		Digest: map[string]string{"sha256": Digest(record.Spec.ApprovalTask.UID)},
+	}
+	if pr := record.Spec.PipelineRun; pr != nil {
+		predicate.PipelineRun = &PipelineRun{
+			Name:         pr.Name,
+			UID:          pr.UID,
+			Pipeline:     pr.Pipeline,
+			PipelineTask: pr.PipelineTask,
+		}
+		if pr.UID != "" {
+			subject = Subject{
+				Name:   "pipelineruns/" + record.Namespace + "/" + pr.Name,
+				Digest: map[string]string{"sha256": Digest(pr.UID)},
+			}
+		}
+	}
+
+	return &Statement{
+		Type:          StatementType,
+		Subject:       []Subject{subject},
+		PredicateType: PredicateType,
+		Predicate:     predicate,
+	}
+}
+
+// Digest returns the hex encoded sha256 of a UID, as used in the subjects.
+func Digest(uid string) string {
+	sum := sha256.Sum256([]byte(uid))
+	return hex.EncodeToString(sum[:])
+}
+
+// Attest signs the statement of the ApprovalRecord and returns its DSSE
+// envelope, encoded as JSON.
+func Attest(signer *Signer, record *v1alpha1.ApprovalRecord) (string, error) {
+	payload, err := json.Marshal(NewStatement(record))
+	if err != nil {
+		return "", err
+	}
+	envelope, err := signer.Sign(PayloadType, payload)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(envelope)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
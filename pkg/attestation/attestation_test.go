@@ -0,0 +1,158 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attestation
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func keyPair(t *testing.T, key crypto.Signer) ([]byte, []byte) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})
+}
+
+func TestSignAndVerify(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPub := keyPair(t, otherKey)
+
+	for name, key := range map[string]crypto.Signer{"ecdsa": ecKey, "ed25519": edKey, "rsa": rsaKey} {
+		t.Run(name, func(t *testing.T) {
+			priv, pub := keyPair(t, key)
+			signer, err := NewSigner(priv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			envelope, err := signer.Sign(PayloadType, []byte(`{"_type":"x"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			payload, err := Verify(envelope, pub)
+			assert.NoError(t, err)
+			assert.Equal(t, `{"_type":"x"}`, string(payload))
+
+			_, err = Verify(envelope, otherPub)
+			assert.EqualError(t, err, "no valid signature found")
+
+			tampered := *envelope
+			tampered.Payload = base64.StdEncoding.EncodeToString([]byte(`{"_type":"y"}`))
+			_, err = Verify(&tampered, pub)
+			assert.EqualError(t, err, "no valid signature found")
+		})
+	}
+}
+
+func TestNewSignerErrors(t *testing.T) {
+	_, err := NewSigner([]byte("not a key"))
+	assert.EqualError(t, err, "no PEM encoded private key found")
+
+	_, err = NewSigner(pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED SIGSTORE PRIVATE KEY", Bytes: []byte("{}")}))
+	assert.EqualError(t, err, "ENCRYPTED SIGSTORE PRIVATE KEY is not supported, use an unencrypted PKCS #8 key")
+}
+
+func TestNewStatement(t *testing.T) {
+	completion := metav1.Now()
+	record := &v1alpha1.ApprovalRecord{
+		ObjectMeta: metav1.ObjectMeta{Name: "bar-01234567", Namespace: "foo"},
+		Spec: v1alpha1.ApprovalRecordSpec{
+			ApprovalTask:              v1alpha1.ApprovalRecordReference{Name: "bar", UID: "approvaltask-uid"},
+			Approvers:                 []v1alpha1.ApproverDetails{{Name: "alice", Type: "User", Input: "approve"}},
+			NumberOfApprovalsRequired: 1,
+			ApprovalsReceived:         1,
+			Decision:                  "approved",
+			History:                   []v1alpha1.ApproverState{{Name: "alice", Type: "User", Response: "approved", Message: "LGTM"}},
+			CompletionTime:            &completion,
+		},
+	}
+
+	statement := NewStatement(record)
+	assert.Equal(t, StatementType, statement.Type)
+	assert.Equal(t, PredicateType, statement.PredicateType)
+	assert.Equal(t, []Subject{{Name: "approvaltasks/foo/bar", Digest: map[string]string{"sha256": Digest("approvaltask-uid")}}}, statement.Subject)
+	assert.Nil(t, statement.Predicate.PipelineRun)
+
+	record.Spec.PipelineRun = &v1alpha1.ApprovalRecordPipelineRun{Name: "release", UID: "pipelinerun-uid", Pipeline: "deploy"}
+	statement = NewStatement(record)
+	assert.Equal(t, []Subject{{Name: "pipelineruns/foo/release", Digest: map[string]string{"sha256": Digest("pipelinerun-uid")}}}, statement.Subject)
+	assert.Equal(t, &PipelineRun{Name: "release", UID: "pipelinerun-uid", Pipeline: "deploy"}, statement.Predicate.PipelineRun)
+	assert.Equal(t, "approved", statement.Predicate.Decision)
+	assert.Equal(t, record.Spec.History, statement.Predicate.Responses)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv, pub := keyPair(t, key)
+	signer, err := NewSigner(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := Attest(signer, record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope Envelope
+	if err := json.Unmarshal([]byte(raw), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, PayloadType, envelope.PayloadType)
+	payload, err := Verify(&envelope, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Statement
+	if err := json.Unmarshal(payload, &got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, statement.Subject, got.Subject)
+}
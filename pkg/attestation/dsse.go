@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attestation
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Envelope is a DSSE envelope, as written by cosign attest-blob.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is a signature of a DSSE envelope.
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// pae is the DSSE pre-authentication encoding of a payload, which is what
+// gets signed.
+func pae(payloadType string, payload []byte) []byte {
+	return []byte("DSSEv1 " + strconv.Itoa(len(payloadType)) + " " + payloadType + " " + strconv.Itoa(len(payload)) + " " + string(payload))
+}
+
+// Signer signs DSSE envelopes with a private key.
+type Signer struct {
+	key crypto.Signer
+}
+
+// NewSigner returns a Signer of the PEM encoded private key, either PKCS #8,
+// SEC 1 (EC) or PKCS #1 (RSA). Keys encrypted by cosign generate-key-pair are
+// not supported.
+func NewSigner(pemKey []byte) (*Signer, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, errors.New("no PEM encoded private key found")
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "ENCRYPTED SIGSTORE PRIVATE KEY", "ENCRYPTED COSIGN PRIVATE KEY", "ENCRYPTED PRIVATE KEY":
+		return nil, fmt.Errorf("%s is not supported, use an unencrypted PKCS #8 key", block.Type)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key %T", key)
+	}
+	return &Signer{key: signer}, nil
+}
+
+// Public returns the public key of the Signer.
+func (s *Signer) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+// Sign returns the DSSE envelope of the payload.
+func (s *Signer) Sign(payloadType string, payload []byte) (*Envelope, error) {
+	sig, err := s.SignMessage(pae(payloadType, payload))
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		PayloadType: payloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{{Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// SignMessage returns the raw signature of a message, over its sha256 but for
+// ed25519 keys, like cosign sign-blob.
+func (s *Signer) SignMessage(message []byte) ([]byte, error) {
+	if _, ok := s.key.(ed25519.PrivateKey); ok {
+		return s.key.Sign(rand.Reader, message, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(message)
+	return s.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+// Verify checks that an envelope is signed by the PEM encoded public key and
+// returns its payload.
+func Verify(envelope *Envelope, pemKey []byte) ([]byte, error) {
+	key, err := parsePublicKey(pemKey)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+	message := pae(envelope.PayloadType, payload)
+	for _, signature := range envelope.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err != nil {
+			continue
+		}
+		valid, err := verify(key, message, sig)
+		if err != nil {
+			return nil, err
+		}
+		if valid {
+			return payload, nil
+		}
+	}
+	return nil, errors.New("no valid signature found")
+}
+
+// VerifyMessage checks the raw signature of a message, as returned by
+// SignMessage, against the PEM encoded public key.
+func VerifyMessage(pemKey, message, sig []byte) error {
+	key, err := parsePublicKey(pemKey)
+	if err != nil {
+		return err
+	}
+	valid, err := verify(key, message, sig)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func parsePublicKey(pemKey []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, errors.New("no PEM encoded public key found")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func verify(key crypto.PublicKey, message, sig []byte) (bool, error) {
+	digest := sha256.Sum256(message)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, digest[:], sig), nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, message, sig), nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil, nil
+	default:
+		return false, fmt.Errorf("unsupported public key %T", key)
+	}
+}
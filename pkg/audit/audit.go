@@ -0,0 +1,305 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit writes a tamper-evident log of approval actions. Every record
+// is a line of JSON holding the hash of the previous record, so that removing
+// or altering a record breaks the chain. The hashes are keyed, so that only
+// the holders of the key can forge a chain.
+package audit
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Reference identifies an object of the cluster.
+type Reference struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// Record is an approval action: an authenticated user answering an
+// ApprovalTask.
+type Record struct {
+	Time time.Time `json:"time"`
+
+	// User, UID and Groups are the authenticated identity of the request
+	User   string   `json:"user"`
+	UID    string   `json:"uid,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+	// Impersonator is who impersonated the user, if anyone
+	Impersonator string `json:"impersonator,omitempty"`
+
+	// Input is approve or reject, Message the message of the approver
+	Input   string `json:"input"`
+	Message string `json:"message,omitempty"`
+	// Group is the group approver the user answered for, if any
+	Group string `json:"group,omitempty"`
+	// Signature is the signature of the input by the user, if any
+	Signature string `json:"signature,omitempty"`
+
+	ApprovalTask Reference `json:"approvalTask"`
+	PipelineRun  string    `json:"pipelineRun,omitempty"`
+
+	// Allowed tells whether the admission webhook admitted the action, and
+	// Reason why it did not
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+
+	// PreviousHash is the Hash of the previous record, empty for the first
+	// record of the chain only
+	PreviousHash string `json:"previousHash"`
+	// Hash is the HMAC-SHA256 of the record without its Hash
+	Hash string `json:"hash,omitempty"`
+}
+
+// computeHash returns the hash of the record keyed with key, which covers
+// every field but Hash itself.
+func (r Record) computeHash(key []byte) (string, error) {
+	r.Hash = ""
+	b, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(b)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// Sink receives the records, one line of JSON at a time.
+type Sink interface {
+	Write(ctx context.Context, line []byte) error
+}
+
+// ErrHeadChanged is returned by HeadStore.Save when another writer moved the
+// head of the chain since it was loaded.
+var ErrHeadChanged = errors.New("the head of the audit chain changed")
+
+// HeadStore keeps the head of the chain, the hash of its last record, so
+// that the chain goes on across restarts of the writer and is shared by its
+// replicas.
+type HeadStore interface {
+	// Load returns the head, empty when the chain has no record yet
+	Load(ctx context.Context) (string, error)
+	// Save replaces the head previous with head, and fails with
+	// ErrHeadChanged when the head is not previous anymore
+	Save(ctx context.Context, previous, head string) error
+}
+
+// saveAttempts bounds how many times a record is chained again to the head
+// another writer saved.
+const saveAttempts = 5
+
+// Logger chains records and writes them to a Sink.
+type Logger struct {
+	mu    sync.Mutex
+	sink  Sink
+	key   []byte
+	heads HeadStore
+
+	// last is the head of the chain, loaded from heads by the first Log and
+	// again whenever another writer moved it
+	last   string
+	loaded bool
+}
+
+// NewLogger returns a Logger writing to sink, keying the hashes with key and
+// keeping the head of the chain in heads.
+func NewLogger(sink Sink, key []byte, heads HeadStore) *Logger {
+	return &Logger{sink: sink, key: key, heads: heads}
+}
+
+// Log chains the record to the head of the chain and writes it. The head is
+// saved before the record is written, so that two writers never chain to the
+// same record: when another writer moved it, the record is chained again to
+// the new head. The head is set back when the record cannot be written, and
+// the chain only advances once the record is both chained and written.
+func (l *Logger) Log(ctx context.Context, record Record) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var hash string
+	for attempt := 1; ; attempt++ {
+		if !l.loaded {
+			last, err := l.heads.Load(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to load the head of the audit chain: %w", err)
+			}
+			l.last, l.loaded = last, true
+		}
+		record.PreviousHash = l.last
+		var err error
+		if hash, err = record.computeHash(l.key); err != nil {
+			return err
+		}
+		err = l.heads.Save(ctx, l.last, hash)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, ErrHeadChanged) {
+			// Chain the record to the record of the other writer
+			l.loaded = false
+			if attempt < saveAttempts {
+				continue
+			}
+		}
+		return fmt.Errorf("failed to save the head of the audit chain: %w", err)
+	}
+	record.Hash = hash
+
+	line, err := json.Marshal(record)
+	if err == nil {
+		err = l.sink.Write(ctx, append(line, '\n'))
+	}
+	if err != nil {
+		if restoreErr := l.heads.Save(ctx, hash, l.last); restoreErr != nil {
+			// The chain goes on from a record missing from the sink, which
+			// Verify reports
+			l.loaded = false
+			return errors.Join(err, fmt.Errorf("failed to restore the head of the audit chain: %w", restoreErr))
+		}
+		return err
+	}
+	l.last = hash
+	return nil
+}
+
+// MemoryHeadStore keeps the head in memory, for writers whose chain starts
+// over when they restart, e.g. in tests.
+type MemoryHeadStore struct {
+	mu   sync.Mutex
+	head string
+}
+
+func (s *MemoryHeadStore) Load(context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.head, nil
+}
+
+func (s *MemoryHeadStore) Save(_ context.Context, previous, head string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.head != previous {
+		return ErrHeadChanged
+	}
+	s.head = head
+	return nil
+}
+
+// ErrUnanchored is returned by Verify for a record starting a new chain in
+// the middle of the log, which is what removing the records before it looks
+// like.
+var ErrUnanchored = errors.New("chain restarts without an anchor")
+
+// Result is the outcome of the verification of an audit log.
+type Result struct {
+	// Records is the number of records verified
+	Records int
+	// Head is the hash of the last record verified, the anchor of the log
+	// which follows, e.g. after a rotation
+	Head string
+	// Skipped is the number of lines which are not records, e.g. the logs
+	// of the webhook when the records are written to its standard output
+	Skipped int
+}
+
+// Verify checks that every record of the log read from r is intact, keyed
+// with key, and that the records form a single chain starting at anchor, the
+// head of the log it follows, empty when the log holds the start of the
+// chain. The records are followed through their hashes rather than in the
+// order of the lines, so that the logs of several writers sharing the chain
+// can be verified together. Lines which are not JSON objects with a hash are
+// skipped, so that the log may be interleaved with other logs.
+func Verify(r io.Reader, key []byte, anchor string) (Result, error) {
+	result := Result{Head: anchor}
+	type entry struct {
+		line   int
+		record Record
+	}
+	var entries []entry
+	// next are the records chained to each hash, in the order of the lines
+	next := map[string][]int{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(scanner.Bytes(), &fields); err != nil || fields["hash"] == nil {
+			result.Skipped++
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return result, fmt.Errorf("line %d: invalid record: %w", line, err)
+		}
+		hash, err := record.computeHash(key)
+		if err != nil {
+			return result, fmt.Errorf("line %d: %w", line, err)
+		}
+		if !hmac.Equal([]byte(hash), []byte(record.Hash)) {
+			return result, fmt.Errorf("line %d: record was altered or keyed with another key, its hash is %s but %s was recorded", line, hash, record.Hash)
+		}
+		next[record.PreviousHash] = append(next[record.PreviousHash], len(entries))
+		entries = append(entries, entry{line: line, record: record})
+	}
+	if err := scanner.Err(); err != nil {
+		return result, err
+	}
+
+	chained := make([]bool, len(entries))
+	followedBy := map[string]int{}
+	for {
+		candidates := next[result.Head]
+		if len(candidates) == 0 {
+			break
+		}
+		i := candidates[0]
+		if chained[i] {
+			// A record chained to itself
+			break
+		}
+		chained[i] = true
+		followedBy[result.Head] = entries[i].line
+		result.Head = entries[i].record.Hash
+		result.Records++
+	}
+	for i, e := range entries {
+		if chained[i] {
+			continue
+		}
+		previous := e.record.PreviousHash
+		if line, ok := followedBy[previous]; ok && previous != "" {
+			return result, fmt.Errorf("line %d: chain is broken, line %d follows the same record %q", e.line, line, previous)
+		} else if previous == "" {
+			return result, fmt.Errorf("line %d: %w", e.line, ErrUnanchored)
+		}
+		return result, fmt.Errorf("line %d: chain is broken, the previous record %q is missing", e.line, previous)
+	}
+	return result, nil
+}
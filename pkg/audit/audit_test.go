@@ -0,0 +1,319 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func record(user, input string) Record {
+	return Record{
+		Time:         time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		User:         user,
+		Input:        input,
+		ApprovalTask: Reference{Namespace: "foo", Name: "at-1"},
+		PipelineRun:  "pr-1",
+		Allowed:      true,
+	}
+}
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+// newHeadClientset returns a clientset holding the HeadConfigMap, whose
+// updates fail with a conflict when their resourceVersion is not the stored
+// one, as they do on the API server.
+func newHeadClientset() *fakekube.Clientset {
+	kube := fakekube.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: HeadConfigMap, Namespace: "manual-approval-gate", ResourceVersion: "1"},
+	})
+	version := 1
+	kube.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		cm := action.(k8stesting.UpdateAction).GetObject().(*corev1.ConfigMap).DeepCopy()
+		if cm.ResourceVersion != fmt.Sprint(version) {
+			return true, nil, apierrors.NewConflict(corev1.Resource("configmaps"), cm.Name, fmt.Errorf("resourceVersion %s is not %d", cm.ResourceVersion, version))
+		}
+		version++
+		cm.ResourceVersion = fmt.Sprint(version)
+		return true, cm, kube.Tracker().Update(corev1.SchemeGroupVersion.WithResource("configmaps"), cm, cm.Namespace)
+	})
+	return kube
+}
+
+func TestLoggerChainsRecords(t *testing.T) {
+	var buf bytes.Buffer
+	heads := &MemoryHeadStore{}
+	logger := NewLogger(NewWriterSink(&buf), testKey, heads)
+	ctx := context.Background()
+	for _, user := range []string{"alice", "bob"} {
+		if err := logger.Log(ctx, record(user, "approve")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := Verify(bytes.NewReader(buf.Bytes()), testKey, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Records)
+	assert.Equal(t, heads.head, result.Head)
+
+	// a restarted writer goes on with the saved head
+	restarted := NewLogger(NewWriterSink(&buf), testKey, heads)
+	if err := restarted.Log(ctx, record("carol", "reject")); err != nil {
+		t.Fatal(err)
+	}
+	result, err = Verify(bytes.NewReader(buf.Bytes()), testKey, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Records)
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+	_, err = Verify(strings.NewReader(lines[0]+lines[1]+lines[1]), testKey, "")
+	assert.ErrorContains(t, err, "line 3: chain is broken")
+
+	_, err = Verify(strings.NewReader(strings.Replace(lines[1], "bob", "eve", 1)), testKey, "")
+	assert.ErrorContains(t, err, "line 1: record was altered")
+
+	// the rest of the log is verified from the head of its start
+	first, err := Verify(strings.NewReader(lines[0]), testKey, "")
+	assert.NoError(t, err)
+	_, err = Verify(strings.NewReader(lines[1]+lines[2]), testKey, first.Head)
+	assert.NoError(t, err)
+	_, err = Verify(strings.NewReader(lines[1]+lines[2]), testKey, "")
+	assert.ErrorContains(t, err, "line 1: chain is broken")
+}
+
+func TestVerifyRejectsUnanchoredChains(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := context.Background()
+	for _, user := range []string{"alice", "bob"} {
+		// a writer which lost the head starts the chain over
+		if err := NewLogger(NewWriterSink(&buf), testKey, &MemoryHeadStore{}).Log(ctx, record(user, "approve")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, err := Verify(bytes.NewReader(buf.Bytes()), testKey, "")
+	assert.ErrorIs(t, err, ErrUnanchored)
+	assert.Equal(t, 1, result.Records)
+}
+
+func TestVerifySkipsOtherLogs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(NewWriterSink(&buf), testKey, &MemoryHeadStore{})
+	ctx := context.Background()
+	buf.WriteString(`{"level":"info","ts":"2024-01-01T00:00:00Z","logger":"webhook","msg":"Starting"}` + "\n")
+	assert.NoError(t, logger.Log(ctx, record("alice", "approve")))
+	buf.WriteString("a line which is not JSON\n")
+	assert.NoError(t, logger.Log(ctx, record("bob", "approve")))
+
+	result, err := Verify(bytes.NewReader(buf.Bytes()), testKey, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Records)
+	assert.Equal(t, 2, result.Skipped)
+
+	// a record whose hash was removed no longer chains the next one
+	lines := strings.SplitAfter(buf.String(), "\n")
+	stripped := strings.Replace(lines[1], `"hash"`, `"removed"`, 1)
+	_, err = Verify(strings.NewReader(lines[0]+stripped+lines[2]+lines[3]), testKey, "")
+	assert.ErrorContains(t, err, "line 4: chain is broken")
+}
+
+func TestVerifyRequiresTheKey(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewLogger(NewWriterSink(&buf), testKey, &MemoryHeadStore{}).Log(context.Background(), record("alice", "approve")); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Verify(bytes.NewReader(buf.Bytes()), []byte("another key"), "")
+	assert.ErrorContains(t, err, "line 1: record was altered or keyed with another key")
+}
+
+type failingSink struct{}
+
+func (failingSink) Write(context.Context, []byte) error { return io.ErrShortWrite }
+
+func TestLoggerDoesNotAdvanceOnFailure(t *testing.T) {
+	heads := &MemoryHeadStore{head: "abc"}
+	logger := NewLogger(failingSink{}, testKey, heads)
+	assert.Error(t, logger.Log(context.Background(), record("alice", "approve")))
+	assert.Equal(t, "abc", logger.last)
+	assert.Equal(t, "abc", heads.head)
+}
+
+// failingHeadStore fails to save any head.
+type failingHeadStore struct{ MemoryHeadStore }
+
+func (*failingHeadStore) Save(context.Context, string, string) error { return io.ErrUnexpectedEOF }
+
+func TestLoggerDoesNotWriteUnsavedRecords(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(NewWriterSink(&buf), testKey, &failingHeadStore{})
+	assert.ErrorIs(t, logger.Log(context.Background(), record("alice", "approve")), io.ErrUnexpectedEOF)
+	assert.Empty(t, buf.String())
+	assert.Empty(t, logger.last)
+}
+
+func TestLoggersShareTheChain(t *testing.T) {
+	ctx := context.Background()
+	kube := newHeadClientset()
+	var first, second bytes.Buffer
+	// two replicas of the webhook, each writing to its own log
+	loggers := []*Logger{
+		NewLogger(NewWriterSink(&first), testKey, NewConfigMapHeadStore(kube, "manual-approval-gate")),
+		NewLogger(NewWriterSink(&second), testKey, NewConfigMapHeadStore(kube, "manual-approval-gate")),
+	}
+	for i, user := range []string{"alice", "bob", "carol", "dave", "erin"} {
+		assert.NoError(t, loggers[i%2].Log(ctx, record(user, "approve")), user)
+	}
+
+	head, err := NewConfigMapHeadStore(kube, "manual-approval-gate").Load(ctx)
+	assert.NoError(t, err)
+	// the logs of the replicas form a single chain, in any order
+	result, err := Verify(io.MultiReader(&second, &first), testKey, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, result.Records)
+	assert.Equal(t, head, result.Head)
+}
+
+func TestLoggerRestoresTheHeadOnFailure(t *testing.T) {
+	ctx := context.Background()
+	kube := newHeadClientset()
+	var buf bytes.Buffer
+	heads := NewConfigMapHeadStore(kube, "manual-approval-gate")
+	assert.NoError(t, NewLogger(NewWriterSink(&buf), testKey, heads).Log(ctx, record("alice", "approve")))
+	saved, err := heads.Load(ctx)
+	assert.NoError(t, err)
+
+	assert.Error(t, NewLogger(failingSink{}, testKey, heads).Log(ctx, record("bob", "approve")))
+	head, err := NewConfigMapHeadStore(kube, "manual-approval-gate").Load(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, saved, head)
+
+	assert.NoError(t, NewLogger(NewWriterSink(&buf), testKey, heads).Log(ctx, record("carol", "approve")))
+	result, err := Verify(&buf, testKey, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Records)
+}
+
+func TestFileSinkResumesChain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	ctx := context.Background()
+	heads := NewConfigMapHeadStore(newHeadClientset(), "manual-approval-gate")
+
+	for _, user := range []string{"alice", "bob"} {
+		sink, err := NewFileSink(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := NewLogger(sink, testKey, heads).Log(ctx, record(user, "approve")); err != nil {
+			t.Fatal(err)
+		}
+		assert.NoError(t, sink.Close())
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	result, err := Verify(f, testKey, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Records)
+	head, err := heads.Load(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, result.Head, head)
+}
+
+func TestConfigMapHeadStore(t *testing.T) {
+	ctx := context.Background()
+	kube := newHeadClientset()
+	first := NewConfigMapHeadStore(kube, "manual-approval-gate")
+	second := NewConfigMapHeadStore(kube, "manual-approval-gate")
+
+	head, err := first.Load(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, head)
+	_, err = second.Load(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, first.Save(ctx, "", "abc"))
+	assert.NoError(t, first.Save(ctx, "abc", "def"))
+	assert.ErrorIs(t, first.Save(ctx, "abc", "ghi"), ErrHeadChanged)
+
+	// second loaded the head before first saved its heads
+	assert.ErrorIs(t, second.Save(ctx, "", "xyz"), ErrHeadChanged)
+	head, err = second.Load(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "def", head)
+	assert.NoError(t, second.Save(ctx, "def", "xyz"))
+
+	_, err = NewConfigMapHeadStore(fakekube.NewSimpleClientset(), "manual-approval-gate").Load(ctx)
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestLoadKey(t *testing.T) {
+	ctx := context.Background()
+	kube := fakekube.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: KeySecret, Namespace: "manual-approval-gate"},
+	})
+	key, err := LoadKey(ctx, kube, "manual-approval-gate")
+	assert.NoError(t, err)
+	assert.Len(t, key, 32)
+	again, err := LoadKey(ctx, kube, "manual-approval-gate")
+	assert.NoError(t, err)
+	assert.Equal(t, key, again)
+
+	_, err = LoadKey(ctx, fakekube.NewSimpleClientset(), "manual-approval-gate")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestHTTPSink(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cr3t" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		received = append(received, string(b))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	logger := NewLogger(NewHTTPSink(server.Client(), server.URL, "s3cr3t"), testKey, &MemoryHeadStore{})
+	assert.NoError(t, logger.Log(ctx, record("alice", "approve")))
+	assert.Len(t, received, 1)
+	result, err := Verify(strings.NewReader(received[0]), testKey, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Records)
+
+	unauthorized := NewLogger(NewHTTPSink(server.Client(), server.URL, ""), testKey, &MemoryHeadStore{})
+	assert.ErrorContains(t, unauthorized.Log(ctx, record("alice", "approve")), "401 Unauthorized")
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// KeySecret is the Secret, in the namespace of the webhook, holding the
+	// key of the hashes under KeySecretKey. The installer creates it empty
+	KeySecret    = "manual-approval-gate-audit-key"
+	KeySecretKey = "key"
+
+	// HeadConfigMap is the ConfigMap, in the namespace of the webhook,
+	// holding the head of the chain under HeadConfigMapKey. The installer
+	// creates it empty
+	HeadConfigMap    = "manual-approval-gate-audit-chain"
+	HeadConfigMapKey = "head"
+)
+
+// LoadKey returns the key of the KeySecret Secret in namespace, which the
+// installer creates empty: the first call sets a random key.
+func LoadKey(ctx context.Context, kube kubernetes.Interface, namespace string) ([]byte, error) {
+	secrets := kube.CoreV1().Secrets(namespace)
+	secret, err := secrets.Get(ctx, KeySecret, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s/%s: %w", namespace, KeySecret, err)
+	}
+	if len(secret.Data[KeySecretKey]) == 0 {
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[KeySecretKey] = key
+		secret, err = secrets.Update(ctx, secret, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) {
+			// Set by another replica
+			secret, err = secrets.Get(ctx, KeySecret, metav1.GetOptions{})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to set the key of secret %s/%s: %w", namespace, KeySecret, err)
+		}
+	}
+	key := secret.Data[KeySecretKey]
+	if len(key) == 0 {
+		return nil, fmt.Errorf("secret %s/%s has no %q", namespace, KeySecret, KeySecretKey)
+	}
+	return key, nil
+}
+
+// ConfigMapHeadStore keeps the head of the chain in the HeadConfigMap
+// ConfigMap of a namespace, which the installer creates. Saving a head
+// another writer changed since it was loaded fails with ErrHeadChanged,
+// rather than forking the chain silently.
+type ConfigMapHeadStore struct {
+	kube      kubernetes.Interface
+	namespace string
+
+	// head and resourceVersion are the ones last read or written
+	head            string
+	resourceVersion string
+}
+
+// NewConfigMapHeadStore returns a HeadStore keeping the head in namespace.
+func NewConfigMapHeadStore(kube kubernetes.Interface, namespace string) *ConfigMapHeadStore {
+	return &ConfigMapHeadStore{kube: kube, namespace: namespace}
+}
+
+func (s *ConfigMapHeadStore) Load(ctx context.Context) (string, error) {
+	cm, err := s.kube.CoreV1().ConfigMaps(s.namespace).Get(ctx, HeadConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to read configmap %s/%s: %w", s.namespace, HeadConfigMap, err)
+	}
+	s.head, s.resourceVersion = cm.Data[HeadConfigMapKey], cm.ResourceVersion
+	return s.head, nil
+}
+
+func (s *ConfigMapHeadStore) Save(ctx context.Context, previous, head string) error {
+	if s.resourceVersion == "" || previous != s.head {
+		return ErrHeadChanged
+	}
+	cm, err := s.kube.CoreV1().ConfigMaps(s.namespace).Update(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: HeadConfigMap, Namespace: s.namespace, ResourceVersion: s.resourceVersion},
+		Data:       map[string]string{HeadConfigMapKey: head},
+	}, metav1.UpdateOptions{})
+	if apierrors.IsConflict(err) {
+		// Another writer saved its head, which the next Load reads
+		s.resourceVersion = ""
+		return fmt.Errorf("%w: %v", ErrHeadChanged, err)
+	} else if err != nil {
+		return err
+	}
+	s.head, s.resourceVersion = head, cm.ResourceVersion
+	return nil
+}
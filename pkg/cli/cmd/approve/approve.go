@@ -1,12 +1,14 @@
 package approve
 
 import (
+	"context"
 	"fmt"
 	"io"
 
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/actions"
 	cli "github.com/openshift-pipelines/manual-approval-gate/pkg/cli"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli/flags"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -42,18 +44,29 @@ func Command(p cli.Params) *cobra.Command {
 				return err
 			}
 
+			config, err := identity.Find(context.Background(), cs.Kube)
+			if err != nil {
+				return fmt.Errorf("failed to read the identity configuration: %v", err)
+			}
+
 			message := opts.Message
+			signingKey := opts.SigningKey
 
 			opts = &cli.Options{
-				Name:      args[0],
-				Namespace: ns,
-				Input:     "approve",
-				Username:  username,
-				Message:   message,
-				Groups:    groups,
+				Name:       args[0],
+				Namespace:  ns,
+				Input:      "approve",
+				Username:   username,
+				Message:    message,
+				Groups:     groups,
+				SigningKey: signingKey,
+				Identity:   config,
 			}
 
 			if err := actions.Update(taskGroupResource, cs, opts); err != nil {
+				if guidance := actions.Guidance(err); guidance != "" {
+					return fmt.Errorf("failed to approve approvalTask from namespace %s: %v\n%s", ns, err, guidance)
+				}
 				return fmt.Errorf("failed to approve approvalTask from namespace %s: %v", ns, err)
 			}
 
@@ -66,6 +79,7 @@ func Command(p cli.Params) *cobra.Command {
 	}
 
 	c.Flags().StringVarP(&opts.Message, "message", "m", "", "message while approving the approvalTask")
+	c.Flags().StringVar(&opts.SigningKey, "sign", "", "private key signing the response, an OpenSSH, cosign or PEM key")
 
 	flags.AddOptions(c)
 
@@ -1,17 +1,39 @@
 package approve
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/signature"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/test"
 	cb "github.com/openshift-pipelines/manual-approval-gate/pkg/test/builder"
 	testDynamic "github.com/openshift-pipelines/manual-approval-gate/pkg/test/dynamic"
 	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestApproveApprovalTask(t *testing.T) {
@@ -269,3 +291,354 @@ func command(t *testing.T, approvaltasks []*v1alpha1.ApprovalTask, ns []*corev1.
 
 	return Command(p)
 }
+
+func TestApproveSigned(t *testing.T) {
+	approvaltasks := []*v1alpha1.ApprovalTask{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "at-signed",
+			Namespace: "foo",
+			UID:       "at-signed-uid",
+		},
+		Spec: v1alpha1.ApprovalTaskSpec{
+			Approvers: []v1alpha1.ApproverDetails{
+				{Name: "tekton", Input: "pending", Type: "User"},
+				{Name: "admin-group", Input: "pending", Type: "Group"},
+			},
+			NumberOfApprovalsRequired: 2,
+		},
+		Status: v1alpha1.ApprovalTaskStatus{State: "pending"},
+	}}
+	dc, err := testDynamic.Client(cb.UnstructuredV1alpha1(approvaltasks[0], "v1alpha1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "cosign.key")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []string{string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub}))}
+
+	c := command(t, approvaltasks, nil, dc, "tekton", []string{"admin-group"})
+	if _, err := test.ExecuteCommand(c, "at-signed", "-n", "foo", "-m", "LGTM", "--sign", keyPath); err != nil {
+		t.Fatal(err)
+	}
+
+	gvr := schema.GroupVersionResource{Group: "openshift-pipelines.org", Version: "v1alpha1", Resource: "approvaltasks"}
+	obj, err := dc.Resource(gvr).Namespace("foo").Get(context.Background(), "at-signed", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var at v1alpha1.ApprovalTask
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &at); err != nil {
+		t.Fatal(err)
+	}
+
+	response := signature.Response{Namespace: "foo", Name: "at-signed", UID: "at-signed-uid", User: "tekton", Input: "approve", Message: "LGTM"}
+	for _, approver := range at.Spec.Approvers {
+		if err := signature.Verify(response, approver.Signature, keys); err != nil {
+			t.Errorf("signature of approver %s: %v", approver.Name, err)
+		}
+	}
+}
+
+func TestApproveRBAC(t *testing.T) {
+	approvaltasks := []*v1alpha1.ApprovalTask{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "at-rbac",
+			Namespace: "foo",
+		},
+		Spec: v1alpha1.ApprovalTaskSpec{
+			Approvers: []v1alpha1.ApproverDetails{
+				{Name: v1alpha1.AnyApprovalTask, Input: "pending", Type: v1alpha1.RBACApproverType},
+				{Name: "release-gate", Input: "pending", Type: v1alpha1.RBACApproverType},
+			},
+			NumberOfApprovalsRequired: 1,
+		},
+		Status: v1alpha1.ApprovalTaskStatus{State: "pending"},
+	}}
+
+	for _, td := range []struct {
+		name    string
+		allowed string
+		want    []string
+		wantErr bool
+	}{
+		{name: "allowed on the resourceName", allowed: "release-gate", want: []string{"pending", "approve"}},
+		{name: "allowed on any approval task", allowed: "", want: []string{"approve", "pending"}},
+		{name: "not allowed", allowed: "other", wantErr: true},
+	} {
+		t.Run(td.name, func(t *testing.T) {
+			dc, err := testDynamic.Client(cb.UnstructuredV1alpha1(approvaltasks[0], "v1alpha1"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			cs, _ := test.SeedTestData(t, test.Data{Approvaltasks: approvaltasks})
+			cs.Kube.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+				attributes := review.Spec.ResourceAttributes
+				review.Status.Allowed = attributes.Verb == v1alpha1.ApproveVerb && attributes.Namespace == "foo" && attributes.Name == td.allowed
+				return true, review, nil
+			})
+			cs.ApprovalTask.Resources = cb.APIResourceList("v1alpha1", []string{"approvaltask"})
+			p := &test.Params{ApprovalTask: cs.ApprovalTask, Kube: cs.Kube, Dynamic: dc, Username: "alice"}
+
+			_, err = test.ExecuteCommand(Command(p), "at-rbac", "-n", "foo")
+			if td.wantErr {
+				if err == nil {
+					t.Fatal("expected an error for a user RBAC does not allow to approve")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gvr := schema.GroupVersionResource{Group: "openshift-pipelines.org", Version: "v1alpha1", Resource: "approvaltasks"}
+			obj, err := dc.Resource(gvr).Namespace("foo").Get(context.Background(), "at-rbac", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var at v1alpha1.ApprovalTask
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &at); err != nil {
+				t.Fatal(err)
+			}
+			for i, approver := range at.Spec.Approvers {
+				if approver.Input != td.want[i] {
+					t.Errorf("input of approver %s = %q, want %q", approver.Name, approver.Input, td.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestApproveAlias(t *testing.T) {
+	approvaltasks := []*v1alpha1.ApprovalTask{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "at-alias",
+			Namespace: "foo",
+		},
+		Spec: v1alpha1.ApprovalTaskSpec{
+			Approvers: []v1alpha1.ApproverDetails{
+				{Name: "Alice@corp.com", Input: "pending", Type: "User"},
+				{Name: "bob", Input: "pending", Type: "User"},
+			},
+			NumberOfApprovalsRequired: 1,
+		},
+		Status: v1alpha1.ApprovalTaskStatus{State: "pending"},
+	}}
+
+	for _, td := range []struct {
+		name     string
+		identity map[string]string
+		want     []string
+		wantErr  bool
+	}{
+		{name: "usernames compared as they are", wantErr: true},
+		{name: "case insensitive", identity: map[string]string{"caseInsensitive": "true", "stripEmailDomains": "corp.com"}, want: []string{"approve", "pending"}},
+		{name: "alias", identity: map[string]string{"aliases": "Alice@corp.com: [asmith]"}, want: []string{"approve", "pending"}},
+	} {
+		t.Run(td.name, func(t *testing.T) {
+			dc, err := testDynamic.Client(cb.UnstructuredV1alpha1(approvaltasks[0], "v1alpha1"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			cs, _ := test.SeedTestData(t, test.Data{Approvaltasks: approvaltasks})
+			if td.identity != nil {
+				if _, err := cs.Kube.CoreV1().ConfigMaps("openshift-pipelines").Create(context.Background(), &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: identity.ConfigMap, Namespace: "openshift-pipelines"},
+					Data:       td.identity,
+				}, metav1.CreateOptions{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			cs.ApprovalTask.Resources = cb.APIResourceList("v1alpha1", []string{"approvaltask"})
+			username := "alice"
+			if td.identity["aliases"] != "" {
+				username = "asmith"
+			}
+			p := &test.Params{ApprovalTask: cs.ApprovalTask, Kube: cs.Kube, Dynamic: dc, Username: username}
+
+			_, err = test.ExecuteCommand(Command(p), "at-alias", "-n", "foo")
+			if td.wantErr {
+				if err == nil {
+					t.Fatal("expected an error for a user who is not an approver")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gvr := schema.GroupVersionResource{Group: "openshift-pipelines.org", Version: "v1alpha1", Resource: "approvaltasks"}
+			obj, err := dc.Resource(gvr).Namespace("foo").Get(context.Background(), "at-alias", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var at v1alpha1.ApprovalTask
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &at); err != nil {
+				t.Fatal(err)
+			}
+			for i, approver := range at.Spec.Approvers {
+				if approver.Input != td.want[i] {
+					t.Errorf("input of approver %s = %q, want %q", approver.Name, approver.Input, td.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestApproveSubresource(t *testing.T) {
+	approvaltasks := []*v1alpha1.ApprovalTask{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "at-subresource",
+			Namespace: "foo",
+		},
+		Spec: v1alpha1.ApprovalTaskSpec{
+			Approvers: []v1alpha1.ApproverDetails{
+				{Name: "tekton", Input: "pending", Type: "User"},
+			},
+			NumberOfApprovalsRequired: 1,
+		},
+		Status: v1alpha1.ApprovalTaskStatus{State: "pending"},
+	}}
+	dc, err := testDynamic.Client(cb.UnstructuredV1alpha1(approvaltasks[0], "v1alpha1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subresourceGroupVersion := v1alpha1.SubresourceGroupName + "/v1alpha1"
+	var got v1alpha1.SubresourceRequest
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		var obj interface{}
+		switch req.URL.Path {
+		case "/api":
+			obj = &metav1.APIVersions{}
+		case "/apis":
+			obj = &metav1.APIGroupList{Groups: []metav1.APIGroup{
+				{Name: "openshift-pipelines.org", Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: "openshift-pipelines.org/v1alpha1", Version: "v1alpha1"}}},
+				{Name: v1alpha1.SubresourceGroupName, Versions: []metav1.GroupVersionForDiscovery{{GroupVersion: subresourceGroupVersion, Version: "v1alpha1"}}},
+			}}
+		case "/apis/openshift-pipelines.org/v1alpha1":
+			obj = cb.APIResourceList("v1alpha1", []string{"approvaltask"})[0]
+		case "/apis/" + subresourceGroupVersion:
+			obj = &metav1.APIResourceList{GroupVersion: subresourceGroupVersion, APIResources: []metav1.APIResource{
+				{Name: "approvaltasks/approve", Namespaced: true, Kind: "ApprovalTask", Verbs: metav1.Verbs{"create"}},
+			}}
+		default:
+			if req.Method != http.MethodPost {
+				http.NotFound(w, req)
+				return
+			}
+			gotPath = req.URL.Path
+			if err := json.NewDecoder(req.Body).Decode(&got); err != nil {
+				t.Error(err)
+			}
+			obj = approvaltasks[0]
+		}
+		if err := json.NewEncoder(w).Encode(obj); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	approvalTaskClient, err := versioned.NewForConfig(&rest.Config{Host: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, _ := test.SeedTestData(t, test.Data{Approvaltasks: approvaltasks})
+	p := &test.Params{ApprovalTask: approvalTaskClient, Kube: cs.Kube, Dynamic: dc, Username: "tekton"}
+
+	if _, err := test.ExecuteCommand(Command(p), "at-subresource", "-n", "foo", "-m", "LGTM"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "/apis/" + subresourceGroupVersion + "/namespaces/foo/approvaltasks/at-subresource/approve"; gotPath != want {
+		t.Errorf("subresource path = %q, want %q", gotPath, want)
+	}
+	if got.Message != "LGTM" {
+		t.Errorf("message = %q, want %q", got.Message, "LGTM")
+	}
+
+	// The ApprovalTask is not updated directly
+	gvr := schema.GroupVersionResource{Group: "openshift-pipelines.org", Version: "v1alpha1", Resource: "approvaltasks"}
+	obj, err := dc.Resource(gvr).Namespace("foo").Get(context.Background(), "at-subresource", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var at v1alpha1.ApprovalTask
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &at); err != nil {
+		t.Fatal(err)
+	}
+	if at.Spec.Approvers[0].Input != "pending" {
+		t.Errorf("input = %q, want the ApprovalTask to be left to the subresource", at.Spec.Approvers[0].Input)
+	}
+}
+
+func TestApproveDenied(t *testing.T) {
+	approvaltasks := []*v1alpha1.ApprovalTask{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "at-denied",
+			Namespace: "foo",
+		},
+		Spec: v1alpha1.ApprovalTaskSpec{
+			Approvers: []v1alpha1.ApproverDetails{
+				{Name: "tekton", Input: "pending", Type: "User"},
+			},
+			NumberOfApprovalsRequired: 1,
+		},
+		Status: v1alpha1.ApprovalTaskStatus{State: "pending"},
+	}}
+	ns := []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}}
+
+	tests := []struct {
+		name   string
+		reason metav1.StatusReason
+		want   string
+	}{
+		{name: "already decided", reason: v1alpha1.DenialReasonAlreadyDecided, want: "You have already responded"},
+		{name: "policy", reason: v1alpha1.DenialReasonPolicyDenied, want: "A policy of the cluster denied the response"},
+		{name: "no reason", reason: metav1.StatusReasonForbidden, want: "denied the request: no"},
+	}
+	for _, td := range tests {
+		t.Run(td.name, func(t *testing.T) {
+			obj := cb.UnstructuredV1alpha1(approvaltasks[0], "v1alpha1")
+			dc := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+				{Group: "openshift-pipelines.org", Version: "v1alpha1", Resource: "approvaltasks"}: "ApprovalTaskList",
+			}, obj)
+			dc.PrependReactor("update", "approvaltasks", func(k8stesting.Action) (bool, runtime.Object, error) {
+				return true, nil, &apierrors.StatusError{ErrStatus: metav1.Status{
+					Status:  metav1.StatusFailure,
+					Message: `admission webhook "validation.webhook.manual-approval.openshift-pipelines.org" denied the request: no`,
+					Reason:  td.reason,
+					Code:    http.StatusForbidden,
+				}}
+			})
+
+			_, err := test.ExecuteCommand(command(t, approvaltasks, ns, dc, "tekton", nil), "at-denied", "-n", "foo")
+			if err == nil {
+				t.Fatal("expected the approval to be denied")
+			}
+			if !strings.Contains(err.Error(), td.want) {
+				t.Errorf("error = %q, want it to contain %q", err, td.want)
+			}
+			if td.reason == metav1.StatusReasonForbidden && strings.Contains(err.Error(), "\n") {
+				t.Errorf("error = %q, want no guidance without a denial reason", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/audit"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli"
+	"github.com/spf13/cobra"
+)
+
+func Command(p cli.Params) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the audit log of approval actions",
+		Long:  `This command inspects the audit log written by the webhook of the approval tasks.`,
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+	}
+
+	c.AddCommand(verifyCommand())
+
+	return c
+}
+
+func verifyCommand() *cobra.Command {
+	var keyFile, anchor string
+	c := &cobra.Command{
+		Use:   "verify FILE",
+		Short: "Verify the hash chain of an audit log",
+		Long: `This command checks that no record of the audit log was altered, removed or
+reordered. Use - to read the audit log from the standard input.
+
+The hashes are keyed with the key of the manual-approval-gate-audit-key Secret
+in the namespace of the webhook, e.g.
+
+  kubectl get secret -n openshift-pipelines manual-approval-gate-audit-key \
+    -o jsonpath='{.data.key}' | base64 -d > audit.key
+
+The records written to the standard output of the webhook are verified from
+its logs, whose other lines are skipped, e.g.
+
+  kubectl logs -n openshift-pipelines -l app=manual-approval-gate-webhook \
+    --tail=-1 | tkn-approvaltask audit verify - --key-file audit.key
+
+The logs of several replicas are verified together, in any order. A log which
+does not hold the start of the chain, e.g. after a rotation, is verified from
+the head printed for the log it follows, with --anchor.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := os.ReadFile(keyFile)
+			if err != nil {
+				return err
+			}
+			var r io.Reader = cmd.InOrStdin()
+			if args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+			result, err := audit.Verify(r, key, anchor)
+			if err != nil {
+				return fmt.Errorf("audit log %s is not intact after %d record(s): %v", args[0], result.Records, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "audit log %s is intact: %d record(s), head %s\n", args[0], result.Records, result.Head)
+			if result.Skipped > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "%d line(s) which are not records skipped\n", result.Skipped)
+			}
+			return nil
+		},
+	}
+	c.Flags().StringVar(&keyFile, "key-file", "", "file holding the key of the hashes")
+	c.Flags().StringVar(&anchor, "anchor", "", "hash of the record preceding the log, empty when the log starts the chain")
+	_ = c.MarkFlagRequired("key-file")
+	return c
+}
@@ -0,0 +1,143 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/audit"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var key = []byte("0123456789abcdef0123456789abcdef")
+
+func writeLog(t *testing.T, n int) []byte {
+	var buf bytes.Buffer
+	logger := audit.NewLogger(audit.NewWriterSink(&buf), key, &audit.MemoryHeadStore{})
+	for i := 0; i < n; i++ {
+		record := audit.Record{
+			Time:         time.Date(2024, 1, 1, 0, i, 0, 0, time.UTC),
+			User:         "tekton",
+			Input:        "approve",
+			ApprovalTask: audit.Reference{Namespace: "foo", Name: "at-1"},
+			Allowed:      true,
+		}
+		if err := logger.Log(context.Background(), record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestVerifyAuditLog(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "audit.key")
+	if err := os.WriteFile(keyFile, key, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	intact := filepath.Join(dir, "intact.jsonl")
+	if err := os.WriteFile(intact, writeLog(t, 3), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.SplitAfter(string(writeLog(t, 3)), "\n")
+	var first struct{ Hash string }
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	rotated := filepath.Join(dir, "rotated.jsonl")
+	if err := os.WriteFile(rotated, []byte(lines[1]+lines[2]), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	restarted := filepath.Join(dir, "restarted.jsonl")
+	if err := os.WriteFile(restarted, append(writeLog(t, 1), writeLog(t, 1)...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	removed := filepath.Join(dir, "removed.jsonl")
+	if err := os.WriteFile(removed, []byte(lines[0]+lines[2]), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := filepath.Join(dir, "logs.jsonl")
+	if err := os.WriteFile(logs, append([]byte(`{"level":"info","msg":"Starting"}`+"\n"), writeLog(t, 2)...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	altered := filepath.Join(dir, "altered.jsonl")
+	if err := os.WriteFile(altered, bytes.Replace(writeLog(t, 2), []byte("approve"), []byte("reject"), 1), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testParams := []struct {
+		name      string
+		args      []string
+		expected  string
+		wantError bool
+	}{
+		{
+			name:     "intact audit log",
+			args:     []string{"verify", intact, "--key-file", keyFile},
+			expected: "audit log " + intact + " is intact: 3 record(s), head ",
+		},
+		{
+			name:     "audit log in the logs of the webhook",
+			args:     []string{"verify", logs, "--key-file", keyFile},
+			expected: "audit log " + logs + " is intact: 2 record(s), head ",
+		},
+		{
+			name:     "rotated audit log",
+			args:     []string{"verify", rotated, "--key-file", keyFile, "--anchor", first.Hash},
+			expected: "audit log " + rotated + " is intact: 2 record(s), head ",
+		},
+		{
+			name:      "rotated audit log without its anchor",
+			args:      []string{"verify", rotated, "--key-file", keyFile},
+			expected:  "Error: audit log " + rotated + " is not intact after 0 record(s): line 1: chain is broken",
+			wantError: true,
+		},
+		{
+			name:      "removed record",
+			args:      []string{"verify", removed, "--key-file", keyFile},
+			expected:  "Error: audit log " + removed + " is not intact after 1 record(s): line 2: chain is broken",
+			wantError: true,
+		},
+		{
+			name:      "unanchored restart",
+			args:      []string{"verify", restarted, "--key-file", keyFile},
+			expected:  "Error: audit log " + restarted + " is not intact after 1 record(s): line 2: chain restarts without an anchor",
+			wantError: true,
+		},
+		{
+			name:      "altered record",
+			args:      []string{"verify", altered, "--key-file", keyFile},
+			expected:  "Error: audit log " + altered + " is not intact after 0 record(s): line 1: record was altered",
+			wantError: true,
+		},
+		{
+			name:      "missing key",
+			args:      []string{"verify", intact},
+			expected:  `Error: required flag(s) "key-file" not set`,
+			wantError: true,
+		},
+	}
+
+	for _, td := range testParams {
+		t.Run(td.name, func(t *testing.T) {
+			output, err := test.ExecuteCommand(Command(&test.Params{}), td.args...)
+			if td.wantError {
+				assert.Error(t, err)
+				assert.True(t, strings.HasPrefix(output, td.expected), output)
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(output, td.expected), output)
+		})
+	}
+}
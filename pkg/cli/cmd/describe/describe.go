@@ -1,6 +1,7 @@
 package describe
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"text/tabwriter"
@@ -11,6 +12,7 @@ import (
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli/flags"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli/formatter"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -24,7 +26,16 @@ var taskTemplate = `📦 Name:            {{ .ApprovalTask.Name }}
 
 👥 Approvers
 {{- range .ApprovalTask.Spec.Approvers }}
-   * {{ .Name }}{{if eq .Type "Group"}} (Group){{end}}
+   * {{ .Name }}{{if eq .Type "Group" "RBAC" "ServiceAccount"}} ({{ .Type }}){{end}}
+{{- end }}
+
+{{- $pendingApprovers := pendingApprovers .ApprovalTask .Identity }}
+{{- if gt (len $pendingApprovers) 0 }}
+
+⏳ PendingApprovers
+{{- range $pendingApprovers }}
+   * {{ . }}
+{{- end }}
 {{- end }}
 
 
@@ -35,11 +46,11 @@ var taskTemplate = `📦 Name:            {{ .ApprovalTask.Name }}
 Name	ApproverResponse	Message
 {{- $userGroups := userGroups .ApprovalTask.Status.ApproversResponse}}
 {{- range $user, $groups := $userGroups}}
-{{$user}}{{if gt (len $groups.Groups) 0}}({{$groups.GroupsStr}}){{end}}	{{response $groups.Response}}	{{message $groups.Message}}
+{{$user}}{{if gt (len $groups.Groups) 0}}({{$groups.GroupsStr}}){{end}}{{if $groups.Automated}} (automated){{end}}{{if $groups.ImpersonatedBy}} (impersonated by {{$groups.ImpersonatedBy}}){{end}}	{{response $groups.Response}}	{{message $groups.Message}}
 {{- end}}
 {{- range .ApprovalTask.Status.ApproversResponse}}
-{{- if eq .Type "User"}}
-{{.Name}}	{{response .Response}}	{{message .Message}}
+{{- if eq .Type "User" "ServiceAccount"}}
+{{.Name}}{{if .Automated}} (automated){{end}}{{if .ImpersonatedBy}} (impersonated by {{.ImpersonatedBy}}){{end}}	{{response .Response}}	{{message .Message}}
 {{- end}}
 {{- end}}
 {{- end}}
@@ -47,31 +58,50 @@ Name	ApproverResponse	Message
 🌡️  Status
 
 NumberOfApprovalsRequired	PendingApprovals	STATUS
-{{.ApprovalTask.Spec.NumberOfApprovalsRequired}}	{{pendingApprovals .ApprovalTask}}	{{state .ApprovalTask}}
+{{.ApprovalTask.Spec.NumberOfApprovalsRequired}}	{{pendingApprovals .ApprovalTask .Identity}}	{{state .ApprovalTask}}
 `
 
 var (
 	taskGroupResource = schema.GroupVersionResource{Group: "openshift-pipelines.org", Resource: "approvaltasks"}
 )
 
-func pendingApprovals(at *v1alpha1.ApprovalTask) int {
+func pendingApprovals(at *v1alpha1.ApprovalTask, config *identity.Config) int {
 	// Count unique users who have responded (approved or rejected)
 	respondedUsers := make(map[string]bool)
 
 	for _, approver := range at.Status.ApproversResponse {
-		if v1alpha1.DefaultedApproverType(approver.Type) == "User" {
-			respondedUsers[approver.Name] = true
-		} else if v1alpha1.DefaultedApproverType(approver.Type) == "Group" {
+		if v1alpha1.IsUserApproverType(approver.Type) {
+			respondedUsers[config.Normalize(approver.Name)] = true
+		} else if v1alpha1.IsGroupApproverType(approver.Type) {
 			// Count individual group members who have responded
 			for _, member := range approver.GroupMembers {
 				if member.Response == "approved" || member.Response == "rejected" {
-					respondedUsers[member.Name] = true
+					respondedUsers[config.Normalize(member.Name)] = true
 				}
 			}
 		}
 	}
 
-	return at.Spec.NumberOfApprovalsRequired - len(respondedUsers)
+	pending := at.Spec.NumberOfApprovalsRequired - len(respondedUsers)
+	// No more approvals than users who may still answer can be received,
+	// known once the controller resolved the members of the groups
+	if len(at.Status.ResolvedGroups) == 0 {
+		return pending
+	}
+	if approvers, ok := at.PendingApprovers(config.Normalize); ok && len(approvers) < pending {
+		return len(approvers)
+	}
+	return pending
+}
+
+// pendingApprovers returns the users who may still answer the pending
+// ApprovalTask, once the controller resolved the members of its groups.
+func pendingApprovers(at *v1alpha1.ApprovalTask, config *identity.Config) []string {
+	if len(at.Status.ResolvedGroups) == 0 || at.Status.State != "pending" {
+		return nil
+	}
+	approvers, _ := at.PendingApprovers(config.Normalize)
+	return approvers
 }
 
 func pipelineRunRef(at *v1alpha1.ApprovalTask) string {
@@ -105,6 +135,9 @@ type UserGroupInfo struct {
 	GroupsStr string
 	Response  string
 	Message   string
+	Automated bool
+	// ImpersonatedBy is who responded on behalf of the user, if anyone
+	ImpersonatedBy string
 }
 
 // userGroups processes ApproversResponse to group users by name across multiple groups
@@ -113,7 +146,7 @@ func userGroups(approversResponse []v1alpha1.ApproverState) map[string]UserGroup
 	
 	// Process group members
 	for _, approver := range approversResponse {
-		if approver.Type == "Group" {
+		if v1alpha1.IsGroupApproverType(approver.Type) {
 			for _, member := range approver.GroupMembers {
 				if existing, exists := userMap[member.Name]; exists {
 					// User already exists, add this group to their list
@@ -122,9 +155,12 @@ func userGroups(approversResponse []v1alpha1.ApproverState) map[string]UserGroup
 				} else {
 					// New user, create entry
 					userMap[member.Name] = UserGroupInfo{
-						Groups:   []string{approver.Name},
-						Response: member.Response,
-						Message:  member.Message,
+						Groups:    []string{approver.Name},
+						Response:  member.Response,
+						Message:   member.Message,
+						Automated: member.Automated,
+
+						ImpersonatedBy: member.ImpersonatedBy,
 					}
 				}
 			}
@@ -153,6 +189,7 @@ func Command(p cli.Params) *cobra.Command {
 	funcMap := template.FuncMap{
 		"pipelineRunRef":   pipelineRunRef,
 		"pendingApprovals": pendingApprovals,
+		"pendingApprovers": pendingApprovers,
 		"message":          message,
 		"response":         response,
 		"state":            formatter.State,
@@ -189,10 +226,17 @@ func Command(p cli.Params) *cobra.Command {
 				return fmt.Errorf("failed to Get ApprovalTasks %s from %s namespace", args[0], ns)
 			}
 
+			config, err := identity.Find(context.Background(), cs.Kube)
+			if err != nil {
+				return fmt.Errorf("failed to read the identity configuration: %v", err)
+			}
+
 			var data = struct {
 				ApprovalTask *v1alpha1.ApprovalTask
+				Identity     *identity.Config
 			}{
 				ApprovalTask: at,
+				Identity:     config,
 			}
 
 			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 5, ' ', tabwriter.TabIndent)
@@ -192,6 +192,82 @@ func TestDescribeApprovalTaskWithGroups(t *testing.T) {
 	golden.Assert(t, output, strings.ReplaceAll(fmt.Sprintf("%s.golden", t.Name()), "/", "-"))
 }
 
+func TestDescribeApprovalTaskWithResolvedGroups(t *testing.T) {
+	approvaltasks := []*v1alpha1.ApprovalTask{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "at-resolved",
+				Namespace: "foo",
+			},
+			Spec: v1alpha1.ApprovalTaskSpec{
+				Approvers: []v1alpha1.ApproverDetails{
+					{
+						Name:  "admin-group",
+						Input: "approve",
+						Type:  "Group",
+						Users: []v1alpha1.UserDetails{
+							{Name: "bob", Input: "approve"},
+						},
+					},
+					{
+						Name:  "alice",
+						Input: "pending",
+						Type:  "User",
+					},
+				},
+				NumberOfApprovalsRequired: 3,
+			},
+			Status: v1alpha1.ApprovalTaskStatus{
+				Approvers: []string{
+					"admin-group",
+					"alice",
+				},
+				ApproversResponse: []v1alpha1.ApproverState{
+					{
+						Name:     "admin-group",
+						Type:     "Group",
+						Response: "approved",
+						GroupMembers: []v1alpha1.GroupMemberState{
+							{
+								Name:     "bob",
+								Response: "approved",
+							},
+						},
+					},
+				},
+				ResolvedGroups: []v1alpha1.ResolvedGroup{
+					{
+						Name:    "admin-group",
+						Members: []string{"alice", "bob"},
+					},
+				},
+				State: "pending",
+			},
+		},
+	}
+
+	ns := []*corev1.Namespace{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "namespace",
+			},
+		},
+	}
+
+	dc, err := testDynamic.Client(
+		cb.UnstructuredV1alpha1(approvaltasks[0], "v1alpha1"),
+	)
+	if err != nil {
+		t.Errorf("unable to create dynamic client: %v", err)
+	}
+
+	c := command(t, approvaltasks, ns, dc)
+	args := []string{"at-resolved", "-n", "foo"}
+
+	output, err := test.ExecuteCommand(c, args...)
+	golden.Assert(t, output, strings.ReplaceAll(fmt.Sprintf("%s.golden", t.Name()), "/", "-"))
+}
+
 // Test individual functions for group functionality
 func TestPendingApprovalsWithGroups(t *testing.T) {
 	tests := []struct {
@@ -258,11 +334,28 @@ func TestPendingApprovalsWithGroups(t *testing.T) {
 			},
 			expected: 2, // 2 required - 0 responded = 2 pending
 		},
+		{
+			name: "fewer resolved members than required",
+			at: &v1alpha1.ApprovalTask{
+				Spec: v1alpha1.ApprovalTaskSpec{
+					Approvers: []v1alpha1.ApproverDetails{
+						{Name: "admin-group", Input: "pending", Type: "Group"},
+					},
+					NumberOfApprovalsRequired: 3,
+				},
+				Status: v1alpha1.ApprovalTaskStatus{
+					ResolvedGroups: []v1alpha1.ResolvedGroup{
+						{Name: "admin-group", Members: []string{"alice", "bob"}},
+					},
+				},
+			},
+			expected: 2, // only alice and bob may still answer
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := pendingApprovals(tt.at)
+			result := pendingApprovals(tt.at, nil)
 			if result != tt.expected {
 				t.Errorf("pendingApprovals() = %d, expected %d", result, tt.expected)
 			}
@@ -0,0 +1,165 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	cli "github.com/openshift-pipelines/manual-approval-gate/pkg/cli"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli/flags"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/results"
+	"github.com/spf13/cobra"
+)
+
+type HistoryOptions struct {
+	Address       string
+	Token         string
+	CAFile        string
+	Insecure      bool
+	PipelineRun   string
+	AllNamespaces bool
+}
+
+const pageSize = 100
+
+func Command(p cli.Params) *cobra.Command {
+	opts := &HistoryOptions{Address: os.Getenv(results.AddressEnv)}
+
+	c := &cobra.Command{
+		Use:   "history",
+		Short: "List past approvals from Tekton Results",
+		Long: `This command lists the approval records published to Tekton Results, including
+those of approval tasks which were already removed from the cluster.`,
+		Annotations: map[string]string{
+			"commandType": "main",
+		},
+		PersistentPreRunE: flags.PersistentPreRunE(p),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Address == "" {
+				return fmt.Errorf("the address of the Results API is required, use --addr or %s", results.AddressEnv)
+			}
+
+			cs, err := p.Clients()
+			if err != nil {
+				return err
+			}
+
+			token := opts.Token
+			if token == "" && cs.Config != nil {
+				token = cs.Config.BearerToken
+			}
+			clientOpts := results.Options{Address: opts.Address, CAFile: opts.CAFile, Insecure: opts.Insecure}
+			if token != "" {
+				clientOpts.Token = func() (string, error) { return token, nil }
+			}
+			client, err := results.NewClient(clientOpts)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+
+			ns := p.Namespace()
+			if opts.AllNamespaces {
+				ns = "-"
+			}
+
+			records, err := list(cmd.Context(), client, ns, opts.PipelineRun)
+			if err != nil {
+				return fmt.Errorf("failed to list approval records from Tekton Results: %v", err)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 5, 3, ' ', tabwriter.TabIndent)
+			if len(records) == 0 {
+				fmt.Fprintln(w, "No approval records found")
+				return w.Flush()
+			}
+			if opts.AllNamespaces {
+				fmt.Fprint(w, "NAMESPACE\t")
+			}
+			fmt.Fprintln(w, "NAME\tPIPELINERUN\tDECISION\tAPPROVALS\tCOMPLETED")
+			for _, record := range records {
+				if opts.AllNamespaces {
+					fmt.Fprintf(w, "%s\t", record.Namespace)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+					record.Spec.ApprovalTask.Name,
+					pipelineRun(record),
+					record.Spec.Decision,
+					approvals(record),
+					completed(record))
+			}
+			return w.Flush()
+		},
+	}
+	flags.AddOptions(c)
+
+	c.Flags().StringVar(&opts.Address, "addr", opts.Address, "address of the Tekton Results API (default: $"+results.AddressEnv+")")
+	c.Flags().StringVar(&opts.Token, "token", "", "bearer token for the Tekton Results API (default: the token of the kubeconfig)")
+	c.Flags().StringVar(&opts.CAFile, "ca-file", "", "CA bundle of the Tekton Results API (default: the system roots)")
+	c.Flags().BoolVar(&opts.Insecure, "insecure", false, "connect to the Tekton Results API without TLS")
+	c.Flags().StringVar(&opts.PipelineRun, "pipelinerun", "", "only list the approvals of this PipelineRun")
+	c.Flags().BoolVarP(&opts.AllNamespaces, "all-namespaces", "A", opts.AllNamespaces, "list approvals from all namespaces")
+
+	return c
+}
+
+// list returns the ApprovalRecords of the namespace, - for any namespace,
+// going through all the pages.
+func list(ctx context.Context, client *results.Client, namespace, pipelineRun string) ([]*v1alpha1.ApprovalRecord, error) {
+	filter := "data_type == " + strconv.Quote(results.RecordType)
+	if pipelineRun != "" {
+		filter += " && data.spec.pipelineRun.name == " + strconv.Quote(pipelineRun)
+	}
+
+	var records []*v1alpha1.ApprovalRecord
+	req := &results.ListRecordsRequest{
+		Parent:   results.ResultName(namespace, "-"),
+		Filter:   filter,
+		PageSize: pageSize,
+		OrderBy:  "create_time desc",
+	}
+	for {
+		resp, err := client.ListRecords(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range resp.Records {
+			record, err := results.DecodeRecord(r)
+			if err != nil {
+				return nil, fmt.Errorf("record %s: %v", r.Name, err)
+			}
+			if record == nil {
+				continue
+			}
+			if pipelineRun != "" && (record.Spec.PipelineRun == nil || record.Spec.PipelineRun.Name != pipelineRun) {
+				continue
+			}
+			records = append(records, record)
+		}
+		if resp.NextPageToken == "" {
+			return records, nil
+		}
+		req.PageToken = resp.NextPageToken
+	}
+}
+
+func pipelineRun(record *v1alpha1.ApprovalRecord) string {
+	if record.Spec.PipelineRun == nil {
+		return "---"
+	}
+	return record.Spec.PipelineRun.Name
+}
+
+func approvals(record *v1alpha1.ApprovalRecord) string {
+	return fmt.Sprintf("%d/%d", record.Spec.ApprovalsReceived, record.Spec.NumberOfApprovalsRequired)
+}
+
+func completed(record *v1alpha1.ApprovalRecord) string {
+	if record.Spec.CompletionTime == nil {
+		return "---"
+	}
+	return record.Spec.CompletionTime.UTC().Format("2006-01-02T15:04:05Z")
+}
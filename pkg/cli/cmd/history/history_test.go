@@ -0,0 +1,116 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	cli "github.com/openshift-pipelines/manual-approval-gate/pkg/cli"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/results"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/results/fake"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+func addRecord(t *testing.T, server *fake.Server, namespace, name, pipelineRun, decision string) {
+	record := &v1alpha1.ApprovalRecord{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-01234567", Namespace: namespace},
+		Spec: v1alpha1.ApprovalRecordSpec{
+			ApprovalTask:              v1alpha1.ApprovalRecordReference{Name: name, UID: name + "-uid"},
+			PipelineRun:               &v1alpha1.ApprovalRecordPipelineRun{Name: pipelineRun, UID: pipelineRun + "-uid"},
+			NumberOfApprovalsRequired: 2,
+			ApprovalsReceived:         1,
+			Decision:                  decision,
+			CompletionTime:            &metav1.Time{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	value, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := results.ResultName(namespace, pipelineRun+"-uid")
+	server.AddRecord(&results.Record{
+		Name: results.RecordName(parent, name+"-uid"),
+		Data: &results.Any{Type: results.RecordType, Value: value},
+	})
+}
+
+func TestHistory(t *testing.T) {
+	server := fake.NewServer()
+	server.Token = "secret"
+	addr := server.Start(t)
+	addRecord(t, server, "foo", "at-1", "release", "approved")
+	addRecord(t, server, "foo", "at-2", "nightly", "rejected")
+	addRecord(t, server, "bar", "at-3", "release", "timed_out")
+	server.AddRecord(&results.Record{
+		Name: "foo/results/release-uid/records/taskrun",
+		Data: &results.Any{Type: "tekton.dev/v1.TaskRun", Value: []byte("{}")},
+	})
+
+	p := &test.Params{Cls: &cli.Clients{Config: &rest.Config{BearerToken: "secret"}}}
+	p.SetNamespace("foo")
+
+	out, err := test.ExecuteCommand(Command(p), "--addr", addr, "--insecure")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, []string{"NAME", "PIPELINERUN", "DECISION", "APPROVALS", "COMPLETED"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{"at-1", "release", "approved", "1/2", "2024-01-01T00:00:00Z"}, strings.Fields(lines[1]))
+		assert.Equal(t, []string{"at-2", "nightly", "rejected", "1/2", "2024-01-01T00:00:00Z"}, strings.Fields(lines[2]))
+	}
+
+	out, err = test.ExecuteCommand(Command(p), "--addr", addr, "--insecure", "-A", "--pipelinerun", "release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines = strings.Split(strings.TrimSpace(out), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.Equal(t, "NAMESPACE", strings.Fields(lines[0])[0])
+		assert.Equal(t, []string{"foo", "at-1"}, strings.Fields(lines[1])[:2])
+		assert.Equal(t, []string{"bar", "at-3"}, strings.Fields(lines[2])[:2])
+	}
+	assert.Contains(t, server.Filters[len(server.Filters)-1], `data.spec.pipelineRun.name == "release"`)
+}
+
+func TestHistoryPaginates(t *testing.T) {
+	server := fake.NewServer()
+	addr := server.Start(t)
+	for i := 0; i < pageSize+5; i++ {
+		addRecord(t, server, "foo", fmt.Sprintf("at-%d", i), "release", "approved")
+	}
+
+	p := &test.Params{Cls: &cli.Clients{}}
+	p.SetNamespace("foo")
+
+	out, err := test.ExecuteCommand(Command(p), "--addr", addr, "--insecure")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, strings.Split(strings.TrimSpace(out), "\n"), pageSize+6)
+}
+
+func TestHistoryWithoutAddress(t *testing.T) {
+	t.Setenv(results.AddressEnv, "")
+	p := &test.Params{Cls: &cli.Clients{}}
+
+	_, err := test.ExecuteCommand(Command(p))
+	assert.ErrorContains(t, err, "the address of the Results API is required")
+}
+
+func TestHistoryUnauthenticated(t *testing.T) {
+	server := fake.NewServer()
+	server.Token = "secret"
+	addr := server.Start(t)
+	p := &test.Params{Cls: &cli.Clients{Config: &rest.Config{BearerToken: "wrong"}}}
+	p.SetNamespace("foo")
+
+	_, err := test.ExecuteCommand(Command(p), "--addr", addr, "--insecure")
+	assert.ErrorContains(t, err, "failed to list approval records from Tekton Results")
+}
@@ -1,6 +1,7 @@
 package list
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"text/tabwriter"
@@ -11,6 +12,7 @@ import (
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
 	cli "github.com/openshift-pipelines/manual-approval-gate/pkg/cli"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli/flags"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -35,47 +37,56 @@ No ApprovalTasks found
 {{else -}}
 NAME	NumberOfApprovalsRequired	PendingApprovals	Rejected	STATUS
 {{range .ApprovalTasks.Items -}}
-{{.Name}}	{{.Spec.NumberOfApprovalsRequired}}	{{pendingApprovals .}}	{{rejected .}}	{{state .}}
+{{.Name}}	{{.Spec.NumberOfApprovalsRequired}}	{{pendingApprovals . $.Identity}}	{{rejected . $.Identity}}	{{state .}}
 {{end}}
 {{- end -}}
 `
 
-func pendingApprovals(at *v1alpha1.ApprovalTask) int {
+func pendingApprovals(at *v1alpha1.ApprovalTask, config *identity.Config) int {
 	// Count unique users who have responded (approved or rejected)
 	respondedUsers := make(map[string]bool)
 
 	for _, approver := range at.Status.ApproversResponse {
-		if v1alpha1.DefaultedApproverType(approver.Type) == "User" {
-			respondedUsers[approver.Name] = true
-		} else if v1alpha1.DefaultedApproverType(approver.Type) == "Group" {
+		if v1alpha1.IsUserApproverType(approver.Type) {
+			respondedUsers[config.Normalize(approver.Name)] = true
+		} else if v1alpha1.IsGroupApproverType(approver.Type) {
 			// Count individual group members who have responded
 			for _, member := range approver.GroupMembers {
 				if member.Response == "approved" || member.Response == "rejected" {
-					respondedUsers[member.Name] = true
+					respondedUsers[config.Normalize(member.Name)] = true
 				}
 			}
 		}
 	}
 
-	return at.Spec.NumberOfApprovalsRequired - len(respondedUsers)
+	pending := at.Spec.NumberOfApprovalsRequired - len(respondedUsers)
+	// No more approvals than users who may still answer can be received,
+	// known once the controller resolved the members of the groups
+	if len(at.Status.ResolvedGroups) == 0 {
+		return pending
+	}
+	if approvers, ok := at.PendingApprovers(config.Normalize); ok && len(approvers) < pending {
+		return len(approvers)
+	}
+	return pending
 }
 
-func rejected(at *v1alpha1.ApprovalTask) int {
+func rejected(at *v1alpha1.ApprovalTask, config *identity.Config) int {
 	count := 0
 	rejectedUsers := make(map[string]bool)
 
 	for _, approver := range at.Status.ApproversResponse {
-		if v1alpha1.DefaultedApproverType(approver.Type) == "User" && approver.Response == "rejected" {
-			if !rejectedUsers[approver.Name] {
-				rejectedUsers[approver.Name] = true
+		if v1alpha1.IsUserApproverType(approver.Type) && approver.Response == "rejected" {
+			if name := config.Normalize(approver.Name); !rejectedUsers[name] {
+				rejectedUsers[name] = true
 				count++
 			}
-		} else if v1alpha1.DefaultedApproverType(approver.Type) == "Group" {
+		} else if v1alpha1.IsGroupApproverType(approver.Type) {
 			// Count individual group members who have rejected
 			for _, member := range approver.GroupMembers {
 				if member.Response == "rejected" {
-					if !rejectedUsers[member.Name] {
-						rejectedUsers[member.Name] = true
+					if name := config.Normalize(member.Name); !rejectedUsers[name] {
+						rejectedUsers[name] = true
 						count++
 					}
 				}
@@ -136,10 +147,17 @@ func Command(p cli.Params) *cobra.Command {
 				return fmt.Errorf("failed to list Tasks from namespace %s: %v", ns, err)
 			}
 
+			config, err := identity.Find(context.Background(), cs.Kube)
+			if err != nil {
+				return fmt.Errorf("failed to read the identity configuration: %v", err)
+			}
+
 			var data = struct {
 				ApprovalTasks *v1alpha1.ApprovalTaskList
+				Identity      *identity.Config
 			}{
 				ApprovalTasks: at,
+				Identity:      config,
 			}
 
 			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 5, 3, ' ', tabwriter.TabIndent)
@@ -368,7 +368,7 @@ func TestPendingApprovalsWithGroups(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := pendingApprovals(tt.at)
+			result := pendingApprovals(tt.at, nil)
 			if result != tt.expected {
 				t.Errorf("pendingApprovals() = %d, expected %d", result, tt.expected)
 			}
@@ -443,7 +443,7 @@ func TestRejectedWithGroups(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := rejected(tt.at)
+			result := rejected(tt.at, nil)
 			if result != tt.expected {
 				t.Errorf("rejected() = %d, expected %d", result, tt.expected)
 			}
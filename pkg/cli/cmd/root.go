@@ -3,7 +3,9 @@ package cmd
 import (
 	cli "github.com/openshift-pipelines/manual-approval-gate/pkg/cli"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli/cmd/approve"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli/cmd/audit"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli/cmd/describe"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli/cmd/history"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli/cmd/list"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli/cmd/reject"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/cli/flags"
@@ -25,6 +27,8 @@ func Root(p cli.Params) *cobra.Command {
 	c.AddCommand(approve.Command(p))
 	c.AddCommand(describe.Command(p))
 	c.AddCommand(reject.Command(p))
+	c.AddCommand(audit.Command(p))
+	c.AddCommand(history.Command(p))
 
 	return c
 }
@@ -2,9 +2,10 @@ package cli
 
 import (
 	"context"
-	"fmt"
 
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
 	userv1typedclient "github.com/openshift/client-go/user/clientset/versioned/typed/user/v1"
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/authentication/v1"
@@ -38,6 +39,10 @@ type Options struct {
 	Message       string
 	AllNamespaces bool
 	Groups        []string
+	// SigningKey is the path of the private key signing the input, if any
+	SigningKey string
+	// Identity normalises the usernames the user is compared with
+	Identity *identity.Config
 }
 
 type Params interface {
@@ -209,15 +214,20 @@ func getUserInfo(authV1Client *authenticationv1client.AuthenticationV1Client, us
 	if err == nil {
 		username = res.Status.UserInfo.Username
 		return username, res.Status.UserInfo.Groups, nil
-	} else {
-		fmt.Errorf("selfsubjectreview request error %v, falling back to user object", err)
 	}
+	// The SelfSubjectReview API may be unavailable, fall back to the OpenShift user object.
 
 	user, err := userInterface.Users().Get(context.TODO(), "~", metav1.GetOptions{})
 	if err != nil {
-		return "", []string{}, nil
+		return "", []string{}, errors.Wrap(err, "unable to identify the current user")
 	}
 	username = user.Name
 
+	// ServiceAccounts, e.g. automation approving from a pod, have no User
+	// object listing their groups
+	if namespace, _, err := v1alpha1.ParseServiceAccountUsername(username); err == nil && len(user.Groups) == 0 {
+		return username, []string{"system:serviceaccounts", "system:serviceaccounts:" + namespace, "system:authenticated"}, nil
+	}
+
 	return username, user.Groups, nil
 }
@@ -28,6 +28,7 @@ import (
 
 type OpenshiftpipelinesV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	ApprovalRecordsGetter
 	ApprovalTasksGetter
 }
 
@@ -36,6 +37,10 @@ type OpenshiftpipelinesV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *OpenshiftpipelinesV1alpha1Client) ApprovalRecords(namespace string) ApprovalRecordInterface {
+	return newApprovalRecords(c, namespace)
+}
+
 func (c *OpenshiftpipelinesV1alpha1Client) ApprovalTasks(namespace string) ApprovalTaskInterface {
 	return newApprovalTasks(c, namespace)
 }
@@ -28,6 +28,10 @@ type FakeOpenshiftpipelinesV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeOpenshiftpipelinesV1alpha1) ApprovalRecords(namespace string) v1alpha1.ApprovalRecordInterface {
+	return newFakeApprovalRecords(c, namespace)
+}
+
 func (c *FakeOpenshiftpipelinesV1alpha1) ApprovalTasks(namespace string) v1alpha1.ApprovalTaskInterface {
 	return newFakeApprovalTasks(c, namespace)
 }
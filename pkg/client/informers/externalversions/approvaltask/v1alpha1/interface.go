@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ApprovalRecords returns a ApprovalRecordInformer.
+	ApprovalRecords() ApprovalRecordInformer
 	// ApprovalTasks returns a ApprovalTaskInformer.
 	ApprovalTasks() ApprovalTaskInformer
 }
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ApprovalRecords returns a ApprovalRecordInformer.
+func (v *version) ApprovalRecords() ApprovalRecordInformer {
+	return &approvalRecordInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // ApprovalTasks returns a ApprovalTaskInformer.
 func (v *version) ApprovalTasks() ApprovalTaskInformer {
 	return &approvalTaskInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
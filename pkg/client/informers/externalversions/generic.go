@@ -53,6 +53,8 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=openshiftpipelines.org, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("approvalrecords"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Openshiftpipelines().V1alpha1().ApprovalRecords().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("approvaltasks"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Openshiftpipelines().V1alpha1().ApprovalTasks().Informer()}, nil
 
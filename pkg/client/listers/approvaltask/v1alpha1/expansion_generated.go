@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+// ApprovalRecordListerExpansion allows custom methods to be added to
+// ApprovalRecordLister.
+type ApprovalRecordListerExpansion interface{}
+
+// ApprovalRecordNamespaceListerExpansion allows custom methods to be added to
+// ApprovalRecordNamespaceLister.
+type ApprovalRecordNamespaceListerExpansion interface{}
+
 // ApprovalTaskListerExpansion allows custom methods to be added to
 // ApprovalTaskLister.
 type ApprovalTaskListerExpansion interface{}
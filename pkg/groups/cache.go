@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groups
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// Cache caches the members of the groups returned by a DirectoryProvider, so
+// that every admission of a group member does not read the directory. Errors
+// are not cached.
+type Cache struct {
+	provider DirectoryProvider
+	ttl      time.Duration
+	clock    clock.PassiveClock
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	members []string
+	expires time.Time
+}
+
+var _ DirectoryProvider = (*Cache)(nil)
+
+// NewCache returns a DirectoryProvider caching the members returned by
+// provider for ttl.
+func NewCache(provider DirectoryProvider, ttl time.Duration, clock clock.PassiveClock) *Cache {
+	return &Cache{
+		provider: provider,
+		ttl:      ttl,
+		clock:    clock,
+		entries:  map[string]cacheEntry{},
+	}
+}
+
+// Members returns the cached members of the group, resolving them when they
+// are not cached or expired.
+func (c *Cache) Members(ctx context.Context, group string) ([]string, error) {
+	now := c.clock.Now()
+	c.mu.Lock()
+	entry, ok := c.entries[group]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.members, nil
+	}
+
+	members, err := c.provider.Members(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.entries[group] = cacheEntry{members: members, expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return members, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package groups resolves the members of the groups of group approvers from
+// directories, e.g. the Group objects of the cluster or LDAP, for users whose
+// identity does not carry their groups.
+package groups
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// CacheTTLEnv is the environment variable setting how long the members
+	// of a group are cached, e.g. "5m"
+	CacheTTLEnv = "GROUP_CACHE_TTL"
+
+	// DefaultCacheTTL is how long the members of a group are cached when
+	// CacheTTLEnv is not set
+	DefaultCacheTTL = time.Minute
+)
+
+// DirectoryProvider returns the members of a group. A group the directory
+// does not know has no members.
+type DirectoryProvider interface {
+	Members(ctx context.Context, group string) ([]string, error)
+}
+
+// IsMember returns true when the directory lists a member of group isUser
+// returns true for, i.e. one of the usernames of the user.
+func IsMember(ctx context.Context, directory DirectoryProvider, group string, isUser func(string) bool) (bool, error) {
+	members, err := directory.Members(ctx, group)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(members, isUser), nil
+}
+
+// NewResolver returns the directory of the webhook and the controller, i.e.
+// the OpenShift Groups and the LDAP directory configured in namespace, cached
+// for the TTL of CacheTTLEnv. An invalid TTL is returned as an error along
+// with a directory using DefaultCacheTTL.
+func NewResolver(client dynamic.Interface, kubeClient kubernetes.Interface, namespace string, clock clock.PassiveClock) (DirectoryProvider, error) {
+	ttl, err := cacheTTL()
+	return NewCache(Union(NewOpenShift(client), NewLDAP(kubeClient, namespace)), ttl, clock), err
+}
+
+// union is a DirectoryProvider of the members of the group in any of its
+// directories.
+type union []DirectoryProvider
+
+// Union returns a DirectoryProvider of the members of the group in any of
+// directories. A directory which fails is skipped, so that the members of
+// the others are still found: the union only fails when all of them do.
+func Union(directories ...DirectoryProvider) DirectoryProvider {
+	return union(directories)
+}
+
+func (u union) Members(ctx context.Context, group string) ([]string, error) {
+	var (
+		members []string
+		errs    []error
+	)
+	for _, directory := range u {
+		found, err := directory.Members(ctx, group)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, member := range found {
+			if !slices.Contains(members, member) {
+				members = append(members, member)
+			}
+		}
+	}
+	if len(u) > 0 && len(errs) == len(u) {
+		return nil, errors.Join(errs...)
+	}
+	return members, nil
+}
+
+func cacheTTL() (time.Duration, error) {
+	raw := os.Getenv(CacheTTLEnv)
+	if raw == "" {
+		return DefaultCacheTTL, nil
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil {
+		return DefaultCacheTTL, err
+	}
+	return ttl, nil
+}
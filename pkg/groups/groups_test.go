@@ -0,0 +1,151 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groups
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func openShiftGroup(name string, users ...string) *unstructured.Unstructured {
+	members := []interface{}{}
+	for _, user := range users {
+		members = append(members, user)
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "user.openshift.io/v1",
+		"kind":       "Group",
+		"metadata":   map[string]interface{}{"name": name},
+		"users":      members,
+	}}
+}
+
+func TestOpenShift(t *testing.T) {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{OpenShiftGroupResource: "GroupList"},
+		openShiftGroup("tekton", "alice", "bob"),
+	)
+	resolver := NewOpenShift(client)
+
+	members, err := resolver.Members(context.Background(), "tekton")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"alice", "bob"}, members)
+
+	members, err = resolver.Members(context.Background(), "unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, members)
+
+	isMember, err := IsMember(context.Background(), resolver, "tekton", func(name string) bool { return name == "bob" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, isMember)
+}
+
+type staticProvider map[string][]string
+
+func (p staticProvider) Members(_ context.Context, group string) ([]string, error) {
+	return p[group], nil
+}
+
+type countingResolver struct {
+	calls   int
+	members []string
+	err     error
+}
+
+func (r *countingResolver) Members(context.Context, string) ([]string, error) {
+	r.calls++
+	return r.members, r.err
+}
+
+func TestUnionSkipsFailingDirectories(t *testing.T) {
+	failing := &countingResolver{err: errors.New("unavailable")}
+
+	members, err := Union(failing, staticProvider{"release": {"alice"}}).Members(context.Background(), "release")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, members)
+
+	_, err = Union(failing, &countingResolver{err: errors.New("timeout")}).Members(context.Background(), "release")
+	assert.ErrorContains(t, err, "unavailable")
+	assert.ErrorContains(t, err, "timeout")
+}
+
+func TestCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakePassiveClock(now)
+	resolver := &countingResolver{members: []string{"alice"}}
+	cache := NewCache(resolver, time.Minute, clock)
+
+	for i := 0; i < 2; i++ {
+		members, err := cache.Members(context.Background(), "tekton")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []string{"alice"}, members)
+	}
+	assert.Equal(t, 1, resolver.calls)
+
+	clock.SetTime(now.Add(time.Minute))
+	resolver.members = []string{"alice", "bob"}
+	members, err := cache.Members(context.Background(), "tekton")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"alice", "bob"}, members)
+	assert.Equal(t, 2, resolver.calls)
+
+	failing := &countingResolver{err: errors.New("unavailable")}
+	cache = NewCache(failing, time.Minute, clock)
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Members(context.Background(), "tekton"); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	assert.Equal(t, 2, failing.calls)
+}
+
+func TestCacheTTL(t *testing.T) {
+	t.Setenv(CacheTTLEnv, "")
+	ttl, err := cacheTTL()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultCacheTTL, ttl)
+
+	t.Setenv(CacheTTLEnv, "5m")
+	ttl, err = cacheTTL()
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, ttl)
+
+	t.Setenv(CacheTTLEnv, "soon")
+	ttl, err = cacheTTL()
+	assert.Error(t, err)
+	assert.Equal(t, DefaultCacheTTL, ttl)
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groups
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DirectoryConfigMap configures the LDAP directory the members of the
+	// groups are resolved from, not used when it does not exist
+	DirectoryConfigMap = "manual-approval-gate-directory"
+
+	// DirectorySecret holds the bindPassword of the bindDN of the LDAP
+	// directory and the ca.crt of its certificate, both optional
+	DirectorySecret = "manual-approval-gate-directory"
+
+	// DefaultGroupFilter finds the group, where %s is the name of the group
+	DefaultGroupFilter = "(&(objectClass=group)(cn=%s))"
+
+	// DefaultMemberAttribute lists the DNs of the members of a group
+	DefaultMemberAttribute = "member"
+
+	// DefaultMemberOfAttribute lists the DNs of the groups of a member
+	DefaultMemberOfAttribute = "memberOf"
+
+	// matchingRuleInChain matches the DNs of the groups a member belongs to
+	// through nested groups too, LDAP_MATCHING_RULE_IN_CHAIN of Active
+	// Directory
+	matchingRuleInChain = "1.2.840.113556.1.4.1941"
+
+	// ldapPageSize is the size of the pages of members read at once
+	ldapPageSize = 500
+
+	// DefaultUserAttribute is the username of a member
+	DefaultUserAttribute = "sAMAccountName"
+
+	ldapTimeout = 10 * time.Second
+)
+
+// LDAPConfig is the configuration of the LDAP directory, read from the
+// DirectoryConfigMap and the DirectorySecret.
+type LDAPConfig struct {
+	// URL of the directory, e.g. ldaps://ad.example.com:636
+	URL string
+	// StartTLS upgrades ldap:// connections to TLS
+	StartTLS bool
+	// CA is the PEM bundle the certificate of the directory is verified
+	// with, the system roots when empty
+	CA []byte
+	// BindDN and BindPassword authenticate to the directory, anonymously
+	// when BindDN is empty
+	BindDN       string
+	BindPassword string
+	// BaseDN is where the groups are searched
+	BaseDN string
+	// UserBaseDN is where their members are searched, BaseDN when empty
+	UserBaseDN string
+	// GroupFilter finds the group, where %s is the name of the group
+	GroupFilter string
+	// MemberAttribute of the groups lists the DNs of their members
+	MemberAttribute string
+	// MemberOfAttribute of the members lists the DNs of their groups
+	MemberOfAttribute string
+	// UserAttribute of the members is their username
+	UserAttribute string
+	// NestedGroups resolves the members of the groups that are members, with
+	// LDAP_MATCHING_RULE_IN_CHAIN
+	NestedGroups bool
+}
+
+// ParseLDAPConfig returns the LDAPConfig of the data of the
+// DirectoryConfigMap and of the DirectorySecret.
+func ParseLDAPConfig(data map[string]string, secret map[string][]byte) (*LDAPConfig, error) {
+	config := &LDAPConfig{
+		URL:               data["url"],
+		BindDN:            data["bindDN"],
+		BindPassword:      string(secret["bindPassword"]),
+		CA:                secret["ca.crt"],
+		BaseDN:            data["baseDN"],
+		UserBaseDN:        data["userBaseDN"],
+		GroupFilter:       data["groupFilter"],
+		MemberAttribute:   data["memberAttribute"],
+		MemberOfAttribute: data["memberOfAttribute"],
+		UserAttribute:     data["userAttribute"],
+	}
+	if config.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	if config.BaseDN == "" {
+		return nil, fmt.Errorf("baseDN is required")
+	}
+	if config.GroupFilter == "" {
+		config.GroupFilter = DefaultGroupFilter
+	} else if strings.Count(config.GroupFilter, "%s") != 1 {
+		return nil, fmt.Errorf("groupFilter %q must contain %%s once, for the name of the group", config.GroupFilter)
+	}
+	if config.UserBaseDN == "" {
+		config.UserBaseDN = config.BaseDN
+	}
+	if config.MemberAttribute == "" {
+		config.MemberAttribute = DefaultMemberAttribute
+	}
+	if config.MemberOfAttribute == "" {
+		config.MemberOfAttribute = DefaultMemberOfAttribute
+	}
+	if config.UserAttribute == "" {
+		config.UserAttribute = DefaultUserAttribute
+	}
+	for key, value := range map[string]*bool{"startTLS": &config.StartTLS, "nestedGroups": &config.NestedGroups} {
+		if data[key] == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(data[key])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", key, data[key], err)
+		}
+		*value = parsed
+	}
+	return config, nil
+}
+
+// LDAP resolves the members of the groups from an LDAP directory, e.g.
+// Active Directory, configured by the DirectoryConfigMap of its namespace.
+// The configuration is read for every resolution, so that it can be changed
+// without restarting: wrap it in a Cache.
+type LDAP struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+var _ DirectoryProvider = (*LDAP)(nil)
+
+// NewLDAP returns a DirectoryProvider of the LDAP directory configured in
+// namespace.
+func NewLDAP(client kubernetes.Interface, namespace string) *LDAP {
+	return &LDAP{client: client, namespace: namespace}
+}
+
+// Members returns the usernames of the members of the group in the
+// directory, none when no directory is configured.
+func (l *LDAP) Members(ctx context.Context, group string) ([]string, error) {
+	config, err := l.config(ctx)
+	if err != nil || config == nil {
+		return nil, err
+	}
+	return config.Members(ctx, group)
+}
+
+// config returns the LDAPConfig, nil when the DirectoryConfigMap does not
+// exist.
+func (l *LDAP) config(ctx context.Context) (*LDAPConfig, error) {
+	cm, err := l.client.CoreV1().ConfigMaps(l.namespace).Get(ctx, DirectoryConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var data map[string][]byte
+	secret, err := l.client.CoreV1().Secrets(l.namespace).Get(ctx, DirectorySecret, metav1.GetOptions{})
+	if err == nil {
+		data = secret.Data
+	} else if !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	config, err := ParseLDAPConfig(cm.Data, data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s ConfigMap: %w", DirectoryConfigMap, err)
+	}
+	return config, nil
+}
+
+// Members connects to the directory and returns the usernames of the
+// members of the group, none when no group matches the GroupFilter. The
+// members are read with a single, paged, search of the entries whose
+// MemberOfAttribute lists the group, or, with NestedGroups, one of its
+// nested groups. Members which have members themselves are groups, and are
+// left out.
+func (c *LDAPConfig) Members(ctx context.Context, group string) ([]string, error) {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	filter := strings.Replace(c.GroupFilter, "%s", ldap.EscapeFilter(group), 1)
+	result, err := conn.Search(ldap.NewSearchRequest(c.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, 0, false, filter, []string{"1.1"}, nil))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search group %q: %w", group, err)
+	}
+	switch len(result.Entries) {
+	case 0:
+		return nil, nil
+	case 1:
+	default:
+		return nil, fmt.Errorf("%d entries match group %q", len(result.Entries), group)
+	}
+
+	memberOf := c.MemberOfAttribute
+	if c.NestedGroups {
+		memberOf += ":" + matchingRuleInChain + ":"
+	}
+	filter = fmt.Sprintf("(&(%s=%s)(!(%s=*)))", memberOf, ldap.EscapeFilter(result.Entries[0].DN), c.MemberAttribute)
+	result, err = conn.SearchWithPaging(ldap.NewSearchRequest(c.UserBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, 0, false, filter, []string{c.UserAttribute}, nil), ldapPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search the members of group %q: %w", group, err)
+	}
+	var members []string
+	for _, entry := range result.Entries {
+		if username := entry.GetEqualFoldAttributeValue(c.UserAttribute); username != "" {
+			members = append(members, username)
+		}
+	}
+	return members, nil
+}
+
+// dial connects and binds to the directory, with the deadline of ctx if
+// earlier than the default timeout.
+func (c *LDAPConfig) dial(ctx context.Context) (*ldap.Conn, error) {
+	timeout := ldapTimeout
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(c.CA) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(c.CA) {
+			return nil, fmt.Errorf("invalid ca.crt in %s Secret", DirectorySecret)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	conn, err := ldap.DialURL(c.URL, ldap.DialWithDialer(&net.Dialer{Timeout: timeout}), ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", c.URL, err)
+	}
+	conn.SetTimeout(timeout)
+	if c.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start TLS with %s: %w", c.URL, err)
+		}
+	}
+	if c.BindDN == "" {
+		err = conn.UnauthenticatedBind("")
+	} else {
+		err = conn.Bind(c.BindDN, c.BindPassword)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to bind to %s: %w", c.URL, err)
+	}
+	return conn, nil
+}
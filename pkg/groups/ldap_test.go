@@ -0,0 +1,369 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groups
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	bindDN       = "cn=reader,dc=example,dc=com"
+	bindPassword = "secret"
+)
+
+// directory is an in-process LDAP server answering simple binds and
+// searches of its entries, by DN and then attribute, for the tests.
+type directory struct {
+	listener net.Listener
+	entries  map[string]map[string][]string
+}
+
+func newDirectory(t *testing.T, entries map[string]map[string][]string) *directory {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &directory{listener: listener, entries: entries}
+	t.Cleanup(func() { listener.Close() })
+	go d.serve()
+	return d
+}
+
+func (d *directory) url() string {
+	return "ldap://" + d.listener.Addr().String()
+}
+
+func (d *directory) serve() {
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			return
+		}
+		go d.handle(conn)
+	}
+}
+
+func (d *directory) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := ber.ReadPacket(conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		id := packet.Children[0].Value
+		request := packet.Children[1]
+		switch request.Tag {
+		case ldap.ApplicationBindRequest:
+			code := ldap.LDAPResultSuccess
+			if request.Children[1].Value != bindDN || request.Children[2].Data.String() != bindPassword {
+				code = ldap.LDAPResultInvalidCredentials
+			}
+			conn.Write(result(id, ldap.ApplicationBindResponse, code).Bytes())
+		case ldap.ApplicationSearchRequest:
+			base := strings.ToLower(request.Children[0].Value.(string))
+			scope := request.Children[1].Value.(int64)
+			found := false
+			for dn, attributes := range d.entries {
+				lower := strings.ToLower(dn)
+				if scope == ldap.ScopeBaseObject && lower != base || !strings.HasSuffix(lower, base) {
+					continue
+				}
+				found = found || lower == base
+				if d.matches(request.Children[6], dn, attributes) {
+					conn.Write(entry(id, dn, attributes).Bytes())
+				}
+			}
+			code := ldap.LDAPResultSuccess
+			if scope == ldap.ScopeBaseObject && !found {
+				code = ldap.LDAPResultNoSuchObject
+			}
+			conn.Write(result(id, ldap.ApplicationSearchResultDone, code).Bytes())
+		default:
+			return
+		}
+	}
+}
+
+// matches evaluates the and, or, not, equality and present filters, and the
+// LDAP_MATCHING_RULE_IN_CHAIN extensible match of the entry dn.
+func (d *directory) matches(filter *ber.Packet, dn string, attributes map[string][]string) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !d.matches(child, dn, attributes) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if d.matches(child, dn, attributes) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !d.matches(filter.Children[0], dn, attributes)
+	case ldap.FilterExtensibleMatch:
+		assertion := map[ber.Tag]string{}
+		for _, child := range filter.Children {
+			assertion[child.Tag] = child.Data.String()
+		}
+		return assertion[1] == matchingRuleInChain && d.inChain(attributes, assertion[2], assertion[3], map[string]bool{})
+	case ldap.FilterEqualityMatch:
+		for _, value := range attribute(attributes, filter.Children[0].Value.(string)) {
+			if strings.EqualFold(value, filter.Children[1].Value.(string)) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterPresent:
+		name := filter.Data.String()
+		return strings.EqualFold(name, "objectClass") || len(attribute(attributes, name)) > 0
+	}
+	return false
+}
+
+// inChain returns true when the groups of the attribute of the entry, or of
+// their own groups, include group.
+func (d *directory) inChain(attributes map[string][]string, name, group string, visited map[string]bool) bool {
+	for _, dn := range attribute(attributes, name) {
+		if strings.EqualFold(dn, group) {
+			return true
+		}
+		if visited[strings.ToLower(dn)] {
+			continue
+		}
+		visited[strings.ToLower(dn)] = true
+		for entryDN, entry := range d.entries {
+			if strings.EqualFold(entryDN, dn) && d.inChain(entry, name, group, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func attribute(attributes map[string][]string, name string) []string {
+	for key, values := range attributes {
+		if strings.EqualFold(key, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func message(id interface{}, op *ber.Packet) *ber.Packet {
+	packet := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	packet.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+	packet.AppendChild(op)
+	return packet
+}
+
+func result(id interface{}, tag ber.Tag, code int) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "resultCode"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "diagnosticMessage"))
+	return message(id, op)
+}
+
+func entry(id interface{}, dn string, attributes map[string][]string) *ber.Packet {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, "objectName"))
+	list := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attributes")
+	for name, values := range attributes {
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "vals")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "value"))
+		}
+		attr.AppendChild(set)
+		list.AppendChild(attr)
+	}
+	op.AppendChild(list)
+	return message(id, op)
+}
+
+func activeDirectory() map[string]map[string][]string {
+	return map[string]map[string][]string{
+		"cn=release,ou=groups,dc=example,dc=com": {
+			"objectClass": {"group"},
+			"cn":          {"release"},
+			"memberOf":    {"cn=platform,ou=groups,dc=example,dc=com"},
+			"member": {
+				"cn=Alice,ou=users,dc=example,dc=com",
+				"cn=platform,ou=groups,dc=example,dc=com",
+				"cn=Gone,ou=users,dc=example,dc=com",
+			},
+		},
+		"cn=platform,ou=groups,dc=example,dc=com": {
+			"objectClass": {"group"},
+			"cn":          {"platform"},
+			"memberOf":    {"cn=release,ou=groups,dc=example,dc=com"},
+			"member": {
+				"cn=Bob,ou=users,dc=example,dc=com",
+				// Cycles are not followed twice
+				"cn=release,ou=groups,dc=example,dc=com",
+			},
+		},
+		"cn=Alice,ou=users,dc=example,dc=com": {
+			"objectClass":    {"user"},
+			"sAMAccountName": {"alice"},
+			"memberOf":       {"cn=release,ou=groups,dc=example,dc=com"},
+		},
+		"cn=Bob,ou=users,dc=example,dc=com": {
+			"objectClass":    {"user"},
+			"sAMAccountName": {"bob"},
+			"memberOf":       {"cn=platform,ou=groups,dc=example,dc=com"},
+		},
+	}
+}
+
+func TestLDAPConfigMembers(t *testing.T) {
+	d := newDirectory(t, activeDirectory())
+
+	tests := []struct {
+		name   string
+		config LDAPConfig
+		group  string
+		want   []string
+		err    bool
+	}{{
+		name:  "direct members",
+		group: "release",
+		want:  []string{"alice"},
+	}, {
+		name:   "nested groups",
+		config: LDAPConfig{NestedGroups: true},
+		group:  "release",
+		want:   []string{"alice", "bob"},
+	}, {
+		name:  "unknown group",
+		group: "unknown",
+	}, {
+		name:  "filter injection",
+		group: "*",
+	}, {
+		name:   "invalid credentials",
+		config: LDAPConfig{BindPassword: "wrong"},
+		group:  "release",
+		err:    true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.URL = d.url()
+			config.BindDN = bindDN
+			if config.BindPassword == "" {
+				config.BindPassword = bindPassword
+			}
+			config.BaseDN = "dc=example,dc=com"
+			config.GroupFilter = DefaultGroupFilter
+			config.UserBaseDN = config.BaseDN
+			config.MemberAttribute = DefaultMemberAttribute
+			config.MemberOfAttribute = DefaultMemberOfAttribute
+			config.UserAttribute = DefaultUserAttribute
+
+			members, err := config.Members(context.Background(), tt.group)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.ElementsMatch(t, tt.want, members)
+		})
+	}
+}
+
+func TestLDAP(t *testing.T) {
+	d := newDirectory(t, activeDirectory())
+
+	// No directory is configured
+	kube := fakekube.NewSimpleClientset()
+	members, err := NewLDAP(kube, "system").Members(context.Background(), "release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, members)
+
+	kube = fakekube.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: DirectoryConfigMap, Namespace: "system"},
+			Data: map[string]string{
+				"url":          d.url(),
+				"bindDN":       bindDN,
+				"baseDN":       "ou=groups,dc=example,dc=com",
+				"userBaseDN":   "ou=users,dc=example,dc=com",
+				"nestedGroups": "true",
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: DirectorySecret, Namespace: "system"},
+			Data:       map[string][]byte{"bindPassword": []byte(bindPassword)},
+		},
+	)
+	members, err = Union(NewLDAP(kube, "system"), staticProvider{"release": {"bob", "carol"}}).Members(context.Background(), "release")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.ElementsMatch(t, []string{"alice", "bob", "carol"}, members)
+}
+
+func TestParseLDAPConfig(t *testing.T) {
+	config, err := ParseLDAPConfig(map[string]string{
+		"url":    "ldaps://ad.example.com",
+		"baseDN": "dc=example,dc=com",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &LDAPConfig{
+		URL:               "ldaps://ad.example.com",
+		BaseDN:            "dc=example,dc=com",
+		UserBaseDN:        "dc=example,dc=com",
+		GroupFilter:       DefaultGroupFilter,
+		MemberAttribute:   DefaultMemberAttribute,
+		MemberOfAttribute: DefaultMemberOfAttribute,
+		UserAttribute:     DefaultUserAttribute,
+	}, config)
+
+	for _, data := range []map[string]string{
+		{"baseDN": "dc=example,dc=com"},
+		{"url": "ldaps://ad.example.com"},
+		{"url": "ldaps://ad.example.com", "baseDN": "dc=example,dc=com", "groupFilter": "(cn=release)"},
+		{"url": "ldaps://ad.example.com", "baseDN": "dc=example,dc=com", "nestedGroups": "maybe"},
+	} {
+		if _, err := ParseLDAPConfig(data, nil); err == nil {
+			t.Errorf("expected an error for %v", data)
+		}
+	}
+}
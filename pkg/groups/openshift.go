@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package groups
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// OpenShiftGroupResource is the resource of the OpenShift Groups
+var OpenShiftGroupResource = schema.GroupVersionResource{Group: "user.openshift.io", Version: "v1", Resource: "groups"}
+
+// OpenShift resolves the members of the groups from the user.openshift.io
+// Groups, which OpenShift does not always put in the identity of the users,
+// e.g. Groups synced from LDAP. Kubernetes has no Group objects: on clusters
+// without the API, the groups have no members.
+type OpenShift struct {
+	client dynamic.Interface
+}
+
+var _ DirectoryProvider = (*OpenShift)(nil)
+
+// NewOpenShift returns a DirectoryProvider of the OpenShift Groups read with
+// client.
+func NewOpenShift(client dynamic.Interface) *OpenShift {
+	return &OpenShift{client: client}
+}
+
+// Members returns the users of the OpenShift Group.
+func (o *OpenShift) Members(ctx context.Context, group string) ([]string, error) {
+	obj, err := o.client.Resource(OpenShiftGroupResource).Get(ctx, group, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	users, _, err := unstructured.NestedStringSlice(obj.Object, "users")
+	return users, err
+}
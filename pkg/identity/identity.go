@@ -0,0 +1,204 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package identity normalises the usernames approvers are listed with and
+// authenticate as, so that the same person is recognised whatever form of
+// their username an identity provider gives, e.g. Alice@corp.com, alice@corp.com
+// or alice.
+package identity
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ConfigMap configures how usernames are normalised, usernames are
+	// compared as they are when it does not exist
+	ConfigMap = "manual-approval-gate-identity"
+
+	// AnyDomain in stripEmailDomains strips the domain of every email
+	AnyDomain = "*"
+)
+
+// InstallNamespaces are the namespaces the CLI looks for the ConfigMap in,
+// the namespaces manual approval gate is installed in on OpenShift and on
+// Kubernetes.
+var InstallNamespaces = []string{"openshift-pipelines", "tekton-pipelines"}
+
+// Config is how usernames are normalised before they are compared. The nil
+// Config compares usernames as they are.
+type Config struct {
+	// CaseInsensitive compares usernames in lower case
+	CaseInsensitive bool
+	// StripEmailDomains are the domains stripped from usernames that are
+	// emails, e.g. alice@corp.com is alice, every domain with AnyDomain
+	StripEmailDomains []string
+	// Aliases maps the normalised aliases of a user to their normalised
+	// username
+	Aliases map[string]string
+}
+
+// ParseConfig parses the data of the ConfigMap:
+//
+//	caseInsensitive: "true"
+//	stripEmailDomains: corp.com, corp.example.org
+//	aliases: |
+//	  alice:
+//	  - asmith
+//	  - alice.smith@partner.com
+//
+// Aliases are normalised like usernames, and a username can be the alias of
+// a single user only.
+func ParseConfig(data map[string]string) (*Config, error) {
+	config := &Config{}
+	if value := data["caseInsensitive"]; value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid caseInsensitive %q: %w", value, err)
+		}
+		config.CaseInsensitive = parsed
+	}
+	for _, domain := range strings.Split(data["stripEmailDomains"], ",") {
+		if domain = strings.TrimPrefix(strings.TrimSpace(domain), "@"); domain != "" {
+			config.StripEmailDomains = append(config.StripEmailDomains, domain)
+		}
+	}
+
+	var aliases map[string][]string
+	if err := yaml.Unmarshal([]byte(data["aliases"]), &aliases); err != nil {
+		return nil, fmt.Errorf("invalid aliases: %w", err)
+	}
+	for username, names := range aliases {
+		username = config.normalize(username)
+		if username == "" {
+			return nil, fmt.Errorf("invalid aliases: empty username")
+		}
+		for _, alias := range names {
+			alias = config.normalize(alias)
+			if alias == "" || alias == username {
+				continue
+			}
+			if other, ok := config.Aliases[alias]; ok && other != username {
+				return nil, fmt.Errorf("invalid aliases: %q is an alias of both %q and %q", alias, other, username)
+			}
+			if config.Aliases == nil {
+				config.Aliases = make(map[string]string)
+			}
+			config.Aliases[alias] = username
+		}
+	}
+	for alias := range config.Aliases {
+		if username, ok := config.Aliases[config.Aliases[alias]]; ok {
+			return nil, fmt.Errorf("invalid aliases: %q is both a username and an alias of %q", config.Aliases[alias], username)
+		}
+	}
+	return config, nil
+}
+
+// Load reads the Config from the ConfigMap in namespace, nil when it does not
+// exist.
+func Load(ctx context.Context, kube kubernetes.Interface, namespace string) (*Config, error) {
+	cm, err := kube.CoreV1().ConfigMaps(namespace).Get(ctx, ConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	config, err := ParseConfig(cm.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ConfigMap %s/%s: %w", namespace, ConfigMap, err)
+	}
+	return config, nil
+}
+
+// Find reads the Config from the first of the InstallNamespaces it is found
+// in, for clients that do not know where manual approval gate is installed.
+// Namespaces the ConfigMap cannot be read in are skipped.
+func Find(ctx context.Context, kube kubernetes.Interface) (*Config, error) {
+	for _, namespace := range InstallNamespaces {
+		config, err := Load(ctx, kube, namespace)
+		if apierrors.IsForbidden(err) {
+			continue
+		} else if err != nil || config != nil {
+			return config, err
+		}
+	}
+	return nil, nil
+}
+
+// Normalize returns the username username is normalised to, the same for all
+// the usernames of a user.
+func (c *Config) Normalize(username string) string {
+	if c == nil {
+		return username
+	}
+	username = c.normalize(username)
+	if alias, ok := c.Aliases[username]; ok {
+		return alias
+	}
+	return username
+}
+
+// Same returns true when both usernames are the usernames of the same user.
+func (c *Config) Same(a, b string) bool {
+	return c.Normalize(a) == c.Normalize(b)
+}
+
+// Matcher returns a function telling whether a name is a username of the user
+// of username.
+func (c *Config) Matcher(username string) func(name string) bool {
+	normalized := c.Normalize(username)
+	return func(name string) bool {
+		return name == username || c.Normalize(name) == normalized
+	}
+}
+
+func (c *Config) normalize(username string) string {
+	username = strings.TrimSpace(username)
+	if c.CaseInsensitive {
+		username = strings.ToLower(username)
+	}
+	if at := strings.LastIndex(username, "@"); at > 0 {
+		domain := username[at+1:]
+		for _, strip := range c.StripEmailDomains {
+			if strip == AnyDomain || strings.EqualFold(strip, domain) {
+				return username[:at]
+			}
+		}
+	}
+	return username
+}
+
+type configKey struct{}
+
+// WithConfig returns a context carrying config.
+func WithConfig(ctx context.Context, config *Config) context.Context {
+	return context.WithValue(ctx, configKey{}, config)
+}
+
+// FromContext returns the Config of the context, nil when it has none.
+func FromContext(ctx context.Context) *Config {
+	config, _ := ctx.Value(configKey{}).(*Config)
+	return config
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/configmap/informer"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestNormalize(t *testing.T) {
+	config, err := ParseConfig(map[string]string{
+		"caseInsensitive":   "true",
+		"stripEmailDomains": "corp.com, @corp.example.org",
+		"aliases": `
+Alice:
+- asmith
+- Alice.Smith@partner.com
+`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for username, expected := range map[string]string{
+		"alice":                             "alice",
+		"Alice@corp.com":                    "alice",
+		"alice@CORP.example.org":            "alice",
+		"asmith@corp.com":                   "alice",
+		"alice.smith@partner.com":           "alice",
+		"alice@partner.com":                 "alice@partner.com",
+		"Bob":                               "bob",
+		"system:serviceaccount:ci:analyzer": "system:serviceaccount:ci:analyzer",
+	} {
+		assert.Equal(t, expected, config.Normalize(username), username)
+	}
+
+	assert.True(t, config.Same("Alice@corp.com", "asmith"))
+	assert.False(t, config.Same("alice", "bob"))
+
+	isAlice := config.Matcher("ALICE@corp.com")
+	assert.True(t, isAlice("asmith"))
+	assert.False(t, isAlice("alice@partner.com"))
+}
+
+func TestNormalizeNil(t *testing.T) {
+	var config *Config
+	assert.Equal(t, "Alice@corp.com", config.Normalize("Alice@corp.com"))
+	assert.False(t, config.Same("Alice@corp.com", "alice@corp.com"))
+	assert.True(t, config.Matcher("alice")("alice"))
+}
+
+func TestStripAnyDomain(t *testing.T) {
+	config, err := ParseConfig(map[string]string{"stripEmailDomains": AnyDomain})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "alice", config.Normalize("alice@partner.com"))
+	assert.Equal(t, "Alice", config.Normalize("Alice@corp.com"))
+	assert.Equal(t, "@corp.com", config.Normalize("@corp.com"))
+}
+
+func TestParseConfig(t *testing.T) {
+	for name, data := range map[string]map[string]string{
+		"invalid caseInsensitive":  {"caseInsensitive": "maybe"},
+		"invalid aliases":          {"aliases": "alice: asmith"},
+		"alias of two users":       {"aliases": "alice: [smith]\nbob: [smith]"},
+		"alias that is a username": {"aliases": "alice: [asmith]\nasmith: [smith]"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseConfig(data); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	client := fakekube.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMap, Namespace: "tekton-pipelines"},
+		Data:       map[string]string{"caseInsensitive": "true"},
+	})
+
+	config, err := Load(context.Background(), client, "openshift-pipelines")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, config)
+
+	config, err = Load(context.Background(), client, "tekton-pipelines")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, config.CaseInsensitive)
+
+	// The ConfigMap of openshift-pipelines cannot be read, the one of
+	// tekton-pipelines is found
+	client.PrependReactor("get", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() == "openshift-pipelines" {
+			return true, nil, apierrors.NewForbidden(corev1.Resource("configmaps"), ConfigMap, nil)
+		}
+		return false, nil, nil
+	})
+	config, err = Find(context.Background(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, config.CaseInsensitive)
+}
+
+func TestStore(t *testing.T) {
+	t.Setenv("SYSTEM_NAMESPACE", "openshift-pipelines")
+	client := fakekube.NewSimpleClientset()
+	watcher := informer.NewInformedWatcher(client, "openshift-pipelines")
+	store := NewStore(logtesting.TestLogger(t))
+	store.WatchConfigs(watcher)
+
+	// Usernames are compared as they are without the ConfigMap
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	if err := watcher.Start(stopCh); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, store.Load())
+
+	// and the ConfigMap is picked up once created
+	if _, err := client.CoreV1().ConfigMaps("openshift-pipelines").Create(context.Background(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMap, Namespace: "openshift-pipelines"},
+		Data:       map[string]string{"caseInsensitive": "true"},
+	}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Eventually(t, func() bool {
+		return store.Load() != nil && store.Load().CaseInsensitive
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A nil Store has no Config
+	var none *Store
+	assert.Nil(t, none.Load())
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/system"
+)
+
+// Store keeps the Config of the ConfigMap up to date from a
+// configmap.Watcher, so that it is not read for every request.
+type Store struct {
+	*configmap.UntypedStore
+}
+
+// NewStore returns a Store, whose Config is nil until the ConfigMap is
+// observed.
+func NewStore(logger configmap.Logger) *Store {
+	return &Store{
+		UntypedStore: configmap.NewUntypedStore("identity", logger, configmap.Constructors{
+			ConfigMap: NewConfigFromConfigMap,
+		}),
+	}
+}
+
+// WatchConfigs watches the ConfigMap in the namespace of the system,
+// usernames being compared as they are while it does not exist.
+func (s *Store) WatchConfigs(w configmap.Watcher) {
+	if dw, ok := w.(configmap.DefaultingWatcher); ok {
+		dw.WatchWithDefault(corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ConfigMap, Namespace: system.Namespace()},
+		}, s.OnConfigChanged)
+		return
+	}
+	s.UntypedStore.WatchConfigs(w)
+}
+
+// Load returns the current Config, nil when usernames are compared as they
+// are.
+func (s *Store) Load() *Config {
+	if s == nil {
+		return nil
+	}
+	config, _ := s.UntypedLoad(ConfigMap).(*Config)
+	return config
+}
+
+// NewConfigFromConfigMap parses the ConfigMap, nil when it has no data.
+func NewConfigFromConfigMap(cm *corev1.ConfigMap) (*Config, error) {
+	if len(cm.Data) == 0 {
+		return nil, nil
+	}
+	config, err := ParseConfig(cm.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ConfigMap %s/%s: %w", cm.Namespace, cm.Name, err)
+	}
+	return config, nil
+}
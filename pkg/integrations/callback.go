@@ -0,0 +1,67 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrations
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"knative.dev/pkg/logging"
+)
+
+// DefaultCallbackPort is the port inbound integration callbacks are served on
+// when CALLBACK_PORT is not set.
+const DefaultCallbackPort = "8080"
+
+var callbackMux = http.NewServeMux()
+
+// HandleCallback registers handler for inbound requests from an external
+// system, e.g. a chat action or a forge webhook, under pattern.
+func HandleCallback(pattern string, handler http.Handler) {
+	callbackMux.Handle(pattern, handler)
+}
+
+// ServeCallbacks serves the registered callback handlers until ctx is done.
+func ServeCallbacks(ctx context.Context) error {
+	port := os.Getenv("CALLBACK_PORT")
+	if port == "" {
+		port = DefaultCallbackPort
+	}
+
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           callbackMux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logging.FromContext(ctx).Errorf("Error shutting down callback server: %v", err)
+		}
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,341 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package comments lets approvers answer ApprovalTasks of PipelineRuns
+// triggered from a pull or merge request by commenting "/approve" or
+// "/reject" on it, on GitHub or GitLab.
+package comments
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	approvaltaskclient "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/client"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations"
+	clientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	pipelineclient "github.com/tektoncd/pipeline/pkg/client/injection/client"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// NotifiedAnnotation records in status.annotations that the pull or
+	// merge request was told how to answer the ApprovalTask.
+	NotifiedAnnotation = "comments.openshift-pipelines.org/notified"
+
+	pacProviderKey    = "pipelinesascode.tekton.dev/git-provider"
+	pacOrgKey         = "pipelinesascode.tekton.dev/url-org"
+	pacRepositoryKey  = "pipelinesascode.tekton.dev/url-repository"
+	pacPullRequestKey = "pipelinesascode.tekton.dev/pull-request"
+
+	// WebhookSecretName is the Secret, in the namespace of the controller,
+	// holding the secret of the comment webhooks of each namespace under
+	// <provider>.<namespace>, e.g. github.my-project. It is owned by the
+	// cluster admins: the Secrets of the tenants cannot authenticate the
+	// comments recorded as the mapped users.
+	WebhookSecretName = "manual-approval-gate-comment-webhooks"
+
+	tokenKey  = "token"
+	apiURLKey = "api-url"
+)
+
+// notifier comments on the pull or merge request when one of its
+// ApprovalTasks starts waiting for approval.
+type notifier struct {
+	forges                map[string]forge
+	kubeClientSet         kubernetes.Interface
+	pipelineClientSet     clientset.Interface
+	approvaltaskClientSet versioned.Interface
+	// systemNamespace holds the WebhookSecretName Secret
+	systemNamespace string
+}
+
+var _ integrations.Interface = (*notifier)(nil)
+
+// NewController instantiates the pull request notifier and registers the
+// endpoints receiving the comment webhooks of each forge.
+func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	kubeclientset := kubeclient.Get(ctx)
+	approvaltaskclientset := approvaltaskclient.Get(ctx)
+	clientFor := integrations.ImpersonatingClientFactory(injection.GetConfig(ctx))
+	logger := logging.FromContext(ctx).Named("comments")
+
+	forges := map[string]forge{}
+	for _, f := range []forge{&githubForge{httpClient: httpClient}, &gitlabForge{httpClient: httpClient}} {
+		forges[f.provider()] = f
+		integrations.HandleCallback("POST /comments/"+f.provider()+"/{namespace}", &commentHandler{
+			forge:                 f,
+			kubeClientSet:         kubeclientset,
+			approvaltaskClientSet: approvaltaskclientset,
+			clientFor:             clientFor,
+			systemNamespace:       system.Namespace(),
+			logger:                logger,
+		})
+	}
+
+	return integrations.NewController(ctx, "CommentsNotifier", &notifier{
+		forges:                forges,
+		kubeClientSet:         kubeclientset,
+		pipelineClientSet:     pipelineclient.Get(ctx),
+		approvaltaskClientSet: approvaltaskclientset,
+		systemNamespace:       system.Namespace(),
+	})
+}
+
+// ReconcileApprovalTask implements integrations.Interface
+func (n *notifier) ReconcileApprovalTask(ctx context.Context, at *v1alpha1.ApprovalTask) error {
+	logger := logging.FromContext(ctx)
+
+	if at.Status.State != "pending" || at.Status.Annotations[NotifiedAnnotation] != "" {
+		return nil
+	}
+	f, ok := n.forges[at.Labels[pacProviderKey]]
+	if !ok || at.Labels[pacPullRequestKey] == "" {
+		return nil
+	}
+	number, err := strconv.Atoi(at.Labels[pacPullRequestKey])
+	if err != nil {
+		return nil
+	}
+
+	// Comments are only enabled where they can be answered
+	secret, err := getWebhookSecret(ctx, n.kubeClientSet, n.systemNamespace, f.provider(), at.Namespace)
+	if err != nil || secret == "" {
+		return err
+	}
+	cfg, err := integrations.GetConfig(ctx, n.kubeClientSet, at.Namespace, f.secretName())
+	if err != nil || cfg == nil {
+		return err
+	}
+
+	// Pipelines-as-Code sanitizes its labels, the annotations of the
+	// PipelineRun keep e.g. GitLab subgroups intact.
+	metadata, err := integrations.PipelineRunMetadata(ctx, n.pipelineClientSet, at)
+	if err != nil {
+		return err
+	}
+	owner, repo := metadata[pacOrgKey], metadata[pacRepositoryKey]
+	if owner == "" || repo == "" {
+		return nil
+	}
+
+	if err := f.comment(ctx, cfg, owner, repo, number, pendingComment(at)); err != nil {
+		return fmt.Errorf("failed to comment on %s/%s#%d for ApprovalTask %s/%s: %w", owner, repo, number, at.Namespace, at.Name, err)
+	}
+	logger.Infof("Commented on %s/%s#%d for ApprovalTask %s/%s", owner, repo, number, at.Namespace, at.Name)
+
+	return integrations.SetStatusAnnotations(ctx, n.approvaltaskClientSet, at, map[string]string{
+		NotifiedAnnotation: "true",
+	})
+}
+
+func pendingComment(at *v1alpha1.ApprovalTask) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ApprovalTask `%s` is waiting for %d approval(s).\n\n", at.Name, at.Status.ApprovalsRequired)
+	if at.Spec.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", at.Spec.Description)
+	}
+	b.WriteString("Approvers:\n")
+	for _, approver := range at.Spec.Approvers {
+		if v1alpha1.IsGroupApproverType(approver.Type) {
+			fmt.Fprintf(&b, "- %s (%s)\n", approver.Name, strings.ToLower(approver.Type))
+		} else {
+			fmt.Fprintf(&b, "- %s\n", approver.Name)
+		}
+	}
+	b.WriteString("\nComment `/approve [message]` or `/reject [message]` to answer it.\n")
+	return b.String()
+}
+
+// getWebhookSecret returns the secret of the comment webhook of provider for
+// namespace, empty when there is none.
+func getWebhookSecret(ctx context.Context, kube kubernetes.Interface, systemNamespace, provider, namespace string) (string, error) {
+	secrets, err := integrations.GetConfig(ctx, kube, systemNamespace, WebhookSecretName)
+	if err != nil {
+		return "", err
+	}
+	return secrets.Get(provider+"."+namespace, ""), nil
+}
+
+// commentHandler serves the comment webhooks of one forge. The namespace in
+// the path selects the webhook secret, the Secret the replies are posted
+// with and the ApprovalTasks the comments can answer.
+type commentHandler struct {
+	forge                 forge
+	kubeClientSet         kubernetes.Interface
+	approvaltaskClientSet versioned.Interface
+	clientFor             integrations.ClientFactory
+	// systemNamespace holds the WebhookSecretName Secret and the
+	// integrations.UserMappingConfigMap, which maps the users of the forge
+	// under the key of its provider
+	systemNamespace string
+	logger          *zap.SugaredLogger
+}
+
+// ServeHTTP authenticates the webhook, records the decision in the comment
+// as the mapped user on every pending ApprovalTask of the pull request and
+// replies with the outcome.
+func (h *commentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	namespace := r.PathValue("namespace")
+
+	secret, err := getWebhookSecret(ctx, h.kubeClientSet, h.systemNamespace, h.forge.provider(), namespace)
+	if err != nil {
+		http.Error(w, "failed to read configuration", http.StatusInternalServerError)
+		return
+	}
+	if secret == "" {
+		http.Error(w, "comments are not enabled for this namespace", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if err := h.forge.verify(r, body, secret); err != nil {
+		h.logger.Warnf("Rejected %s webhook for namespace %s: %v", h.forge.provider(), namespace, err)
+		http.Error(w, "invalid webhook signature", http.StatusUnauthorized)
+		return
+	}
+
+	ev, err := h.forge.parse(r, body)
+	if err != nil {
+		http.Error(w, "malformed event", http.StatusBadRequest)
+		return
+	}
+	if ev == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	input, message, ok := parseCommand(ev.Body)
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	cfg, err := integrations.GetConfig(ctx, h.kubeClientSet, namespace, h.forge.secretName())
+	if err != nil || cfg == nil {
+		http.Error(w, "failed to read configuration", http.StatusInternalServerError)
+		return
+	}
+
+	reply, err := h.decide(ctx, cfg, namespace, ev, input, message)
+	if err != nil {
+		h.logger.Errorf("Failed to handle %s comment on %s/%s#%d: %v", h.forge.provider(), ev.Owner, ev.Repo, ev.Number, err)
+		http.Error(w, "failed to handle comment", http.StatusInternalServerError)
+		return
+	}
+	if err := h.forge.comment(ctx, cfg, ev.Owner, ev.Repo, ev.Number, reply); err != nil {
+		h.logger.Errorf("Failed to reply on %s/%s#%d: %v", ev.Owner, ev.Repo, ev.Number, err)
+		http.Error(w, "failed to reply", http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// decide applies the decision to the pending ApprovalTasks of the pull
+// request and returns the reply describing the outcome of each.
+func (h *commentHandler) decide(ctx context.Context, cfg integrations.Config, namespace string, ev *commentEvent, input, message string) (string, error) {
+	mapping, err := integrations.LoadUserMapping(ctx, h.kubeClientSet, h.systemNamespace, h.forge.provider())
+	if err != nil {
+		return "", err
+	}
+	username, err := mapping.Username(ev.User)
+	if err != nil {
+		h.logger.Warnf("Ignored %s comment of unmapped user %s on %s/%s#%d", h.forge.provider(), ev.User, ev.Owner, ev.Repo, ev.Number)
+		return fmt.Sprintf("@%s your %s user is not mapped to a Kubernetes user, ask the cluster admins to add it to the `%s` ConfigMap.", ev.User, h.forge.provider(), integrations.UserMappingConfigMap), nil
+	}
+	tasks, err := h.pendingApprovalTasks(ctx, namespace, ev)
+	if err != nil {
+		return "", err
+	}
+	if len(tasks) == 0 {
+		return fmt.Sprintf("@%s there is no pending ApprovalTask for this pull request in namespace `%s`.", ev.User, namespace), nil
+	}
+
+	decision := "approval"
+	if input == "reject" {
+		decision = "rejection"
+	}
+	var b strings.Builder
+	for _, at := range tasks {
+		err := integrations.Apply(ctx, h.clientFor, integrations.Decision{
+			Namespace: namespace,
+			Name:      at.Name,
+			Username:  username,
+			Input:     input,
+			Message:   message,
+		})
+		if err != nil {
+			h.logger.Warnf("%s user %s could not %s ApprovalTask %s/%s: %v", h.forge.provider(), ev.User, input, namespace, at.Name, err)
+			fmt.Fprintf(&b, "@%s your %s of ApprovalTask `%s` was not recorded: %v\n", ev.User, decision, at.Name, err)
+			continue
+		}
+		h.logger.Infof("%s user %s sent %s as %s for ApprovalTask %s/%s", h.forge.provider(), ev.User, input, username, namespace, at.Name)
+		fmt.Fprintf(&b, "@%s your %s of ApprovalTask `%s` has been recorded.\n", ev.User, decision, at.Name)
+	}
+	return b.String(), nil
+}
+
+// pendingApprovalTasks returns the pending ApprovalTasks of the PipelineRuns
+// Pipelines-as-Code started for the pull request.
+func (h *commentHandler) pendingApprovalTasks(ctx context.Context, namespace string, ev *commentEvent) ([]v1alpha1.ApprovalTask, error) {
+	selector := labels.SelectorFromSet(labels.Set{
+		pacRepositoryKey:  ev.Repo,
+		pacPullRequestKey: strconv.Itoa(ev.Number),
+	})
+	list, err := h.approvaltaskClientSet.OpenshiftpipelinesV1alpha1().ApprovalTasks(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []v1alpha1.ApprovalTask
+	for _, at := range list.Items {
+		if at.Status.State != "pending" {
+			continue
+		}
+		if provider := at.Labels[pacProviderKey]; provider != "" && provider != h.forge.provider() {
+			continue
+		}
+		// Label values cannot hold the "/" of GitLab subgroups.
+		if org := at.Labels[pacOrgKey]; org != ev.Owner && org != strings.ReplaceAll(ev.Owner, "/", "-") {
+			continue
+		}
+		tasks = append(tasks, at)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Name < tasks[j].Name })
+	return tasks, nil
+}
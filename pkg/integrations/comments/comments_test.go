@@ -0,0 +1,322 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package comments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/fake"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations/github"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+)
+
+const webhookSecret = "hunter2"
+
+func pullRequestApprovalTask(provider, org string) *v1alpha1.ApprovalTask {
+	return &v1alpha1.ApprovalTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "at-1",
+			Namespace: "foo",
+			Labels: map[string]string{
+				pacProviderKey:    provider,
+				pacOrgKey:         org,
+				pacRepositoryKey:  "repo",
+				pacPullRequestKey: "7",
+			},
+		},
+		Spec: v1alpha1.ApprovalTaskSpec{
+			Approvers: []v1alpha1.ApproverDetails{
+				{Name: "alice", Input: "pending", Type: "User"},
+			},
+			NumberOfApprovalsRequired: 1,
+		},
+		Status: v1alpha1.ApprovalTaskStatus{State: "pending", ApprovalsRequired: 1},
+	}
+}
+
+func userMapping(provider, data string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: integrations.UserMappingConfigMap, Namespace: "manual-approval-gate"},
+		Data:       map[string]string{provider: data},
+	}
+}
+
+func webhookSecrets(data map[string]string) *corev1.Secret {
+	s := secret(WebhookSecretName, data)
+	s.Namespace = "manual-approval-gate"
+	return s
+}
+
+func secret(name string, data map[string]string) *corev1.Secret {
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "foo"},
+		Data:       map[string][]byte{},
+	}
+	for k, v := range data {
+		s.Data[k] = []byte(v)
+	}
+	return s
+}
+
+// forgeAPI records the comments posted to it.
+type forgeAPI struct {
+	server   *httptest.Server
+	paths    []string
+	comments []string
+}
+
+func newForgeAPI(t *testing.T) *forgeAPI {
+	api := &forgeAPI{}
+	api.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		api.paths = append(api.paths, r.URL.EscapedPath())
+		api.comments = append(api.comments, body["body"])
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		body        string
+		wantInput   string
+		wantMessage string
+		wantOK      bool
+	}{
+		{body: "/approve", wantInput: "approve", wantOK: true},
+		{body: "  /reject  not today ", wantInput: "reject", wantMessage: "not today", wantOK: true},
+		{body: "/approve LGTM\nsecond line", wantInput: "approve", wantMessage: "LGTM\nsecond line", wantOK: true},
+		{body: "/approved"},
+		{body: "please /approve"},
+		{body: ""},
+	}
+	for _, tc := range tests {
+		input, message, ok := parseCommand(tc.body)
+		assert.Equal(t, tc.wantOK, ok, tc.body)
+		assert.Equal(t, tc.wantInput, input, tc.body)
+		assert.Equal(t, tc.wantMessage, message, tc.body)
+	}
+}
+
+func TestGitHubComment(t *testing.T) {
+	payload := `{
+		"action": "created",
+		"issue": {"number": 7, "pull_request": {}},
+		"comment": {"body": "/approve ship it", "user": {"login": "alice-gh"}},
+		"repository": {"name": "repo", "owner": {"login": "org"}}
+	}`
+	sign := func(key string) string {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(payload))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	tests := []struct {
+		name       string
+		signature  string
+		event      string
+		mapping    string
+		wantStatus int
+		wantInput  string
+		wantReply  string
+	}{{
+		name:       "signed comment approves as the mapped user",
+		signature:  sign(webhookSecret),
+		event:      "issue_comment",
+		mapping:    "alice-gh: alice",
+		wantStatus: http.StatusOK,
+		wantInput:  "approve",
+		wantReply:  "@alice-gh your approval of ApprovalTask `at-1` has been recorded",
+	}, {
+		name:       "unmapped user",
+		signature:  sign(webhookSecret),
+		event:      "issue_comment",
+		mapping:    "bob-gh: bob",
+		wantStatus: http.StatusOK,
+		wantInput:  "pending",
+		wantReply:  "@alice-gh your github user is not mapped to a Kubernetes user",
+	}, {
+		name:       "signed with the secret of the tenant",
+		signature:  sign("tenant"),
+		event:      "issue_comment",
+		wantStatus: http.StatusUnauthorized,
+		wantInput:  "pending",
+	}, {
+		name:       "wrong signature",
+		signature:  sign("wrong"),
+		event:      "issue_comment",
+		wantStatus: http.StatusUnauthorized,
+		wantInput:  "pending",
+	}, {
+		name:       "missing signature",
+		event:      "issue_comment",
+		wantStatus: http.StatusUnauthorized,
+		wantInput:  "pending",
+	}, {
+		name:       "other event",
+		signature:  sign(webhookSecret),
+		event:      "push",
+		wantStatus: http.StatusNoContent,
+		wantInput:  "pending",
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			api := newForgeAPI(t)
+			atClient := fake.NewSimpleClientset(pullRequestApprovalTask("github", "org"))
+			var impersonated string
+			h := &commentHandler{
+				forge: &githubForge{httpClient: api.server.Client()},
+				kubeClientSet: fakekube.NewSimpleClientset(
+					webhookSecrets(map[string]string{"github.foo": webhookSecret}),
+					// The webhook secret of the tenant Secret is ignored
+					secret(github.SecretName, map[string]string{
+						"webhook-secret": "tenant",
+						apiURLKey:        api.server.URL,
+					}),
+					userMapping("github", tc.mapping)),
+				approvaltaskClientSet: atClient,
+				clientFor: func(username string, _ []string) (versioned.Interface, error) {
+					impersonated = username
+					return atClient, nil
+				},
+				systemNamespace: "manual-approval-gate",
+				logger:          zap.NewNop().Sugar(),
+			}
+
+			mux := http.NewServeMux()
+			mux.Handle("POST /comments/github/{namespace}", h)
+			req := httptest.NewRequest(http.MethodPost, "/comments/github/foo", strings.NewReader(payload))
+			req.Header.Set("X-GitHub-Event", tc.event)
+			if tc.signature != "" {
+				req.Header.Set("X-Hub-Signature-256", tc.signature)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			at, err := atClient.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(context.Background(), "at-1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.wantInput, at.Spec.Approvers[0].Input)
+			if tc.wantStatus != http.StatusOK {
+				assert.Empty(t, api.comments)
+				return
+			}
+			if tc.wantInput != "pending" {
+				assert.Equal(t, "alice", impersonated)
+				assert.Equal(t, "ship it", at.Spec.Approvers[0].Message)
+			} else {
+				assert.Empty(t, impersonated)
+			}
+			if assert.Len(t, api.comments, 1) {
+				assert.Equal(t, "/repos/org/repo/issues/7/comments", api.paths[0])
+				assert.Contains(t, api.comments[0], tc.wantReply)
+			}
+		})
+	}
+}
+
+func TestGitLabCommentWithoutPendingApprovalTask(t *testing.T) {
+	api := newForgeAPI(t)
+	at := pullRequestApprovalTask("gitlab", "group-sub")
+	at.Status.State = "approved"
+	h := &commentHandler{
+		forge: &gitlabForge{httpClient: api.server.Client()},
+		kubeClientSet: fakekube.NewSimpleClientset(
+			webhookSecrets(map[string]string{"gitlab.foo": webhookSecret}),
+			secret(GitLabSecretName, map[string]string{apiURLKey: api.server.URL}),
+			userMapping("gitlab", "alice: alice")),
+		approvaltaskClientSet: fake.NewSimpleClientset(at),
+		clientFor: func(string, []string) (versioned.Interface, error) {
+			t.Fatal("no decision should be applied")
+			return nil, nil
+		},
+		systemNamespace: "manual-approval-gate",
+		logger:          zap.NewNop().Sugar(),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /comments/gitlab/{namespace}", h)
+	req := httptest.NewRequest(http.MethodPost, "/comments/gitlab/foo", strings.NewReader(`{
+		"object_kind": "note",
+		"object_attributes": {"note": "/reject", "noteable_type": "MergeRequest"},
+		"merge_request": {"iid": 7},
+		"user": {"username": "alice"},
+		"project": {"path_with_namespace": "group/sub/repo"}
+	}`))
+	req.Header.Set("X-Gitlab-Event", "Note Hook")
+	req.Header.Set("X-Gitlab-Token", webhookSecret)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.Len(t, api.comments, 1) {
+		assert.Equal(t, "/projects/group%2Fsub%2Frepo/merge_requests/7/notes", api.paths[0])
+		assert.Contains(t, api.comments[0], "there is no pending ApprovalTask")
+	}
+}
+
+func TestReconcileApprovalTask(t *testing.T) {
+	api := newForgeAPI(t)
+	at := pullRequestApprovalTask("github", "org")
+	atClient := fake.NewSimpleClientset(at)
+	n := &notifier{
+		forges: map[string]forge{"github": &githubForge{httpClient: api.server.Client()}},
+		kubeClientSet: fakekube.NewSimpleClientset(
+			webhookSecrets(map[string]string{"github.foo": webhookSecret}),
+			secret(github.SecretName, map[string]string{apiURLKey: api.server.URL})),
+		approvaltaskClientSet: atClient,
+		systemNamespace:       "manual-approval-gate",
+	}
+
+	ctx := context.Background()
+	assert.NoError(t, n.ReconcileApprovalTask(ctx, at.DeepCopy()))
+	if !assert.Len(t, api.comments, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "/repos/org/repo/issues/7/comments", api.paths[0])
+	assert.Contains(t, api.comments[0], "`/approve [message]`")
+
+	updated, err := atClient.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(ctx, "at-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "true", updated.Status.Annotations[NotifiedAnnotation])
+
+	// The pull request is only told once.
+	assert.NoError(t, n.ReconcileApprovalTask(ctx, updated.DeepCopy()))
+	assert.Len(t, api.comments, 1)
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package comments
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations"
+)
+
+// commentEvent is a comment on a pull or merge request, whichever forge it
+// comes from.
+type commentEvent struct {
+	// Owner is the organization, user or group owning the repository
+	Owner string
+	Repo  string
+	// Number is the pull request number or merge request iid
+	Number int
+	// User is the login of the author of the comment on the forge
+	User string
+	Body string
+}
+
+// forge receives comment webhooks from, and replies on, one kind of forge.
+type forge interface {
+	// provider is the Pipelines-as-Code git-provider of the forge
+	provider() string
+	// secretName is the Secret configuring the forge in a namespace
+	secretName() string
+	// verify authenticates the webhook request with the shared secret
+	verify(r *http.Request, body []byte, secret string) error
+	// parse returns the comment carried by the webhook, or nil when the
+	// event is not a new comment on a pull or merge request
+	parse(r *http.Request, body []byte) (*commentEvent, error)
+	// comment posts body on the pull or merge request number of owner/repo
+	comment(ctx context.Context, cfg integrations.Config, owner, repo string, number int, body string) error
+}
+
+// parseCommand returns the input and message of a comment whose first line
+// is "/approve [message]" or "/reject [message]". Lines after the first are
+// part of the message.
+func parseCommand(body string) (input, message string, ok bool) {
+	body = strings.TrimSpace(body)
+	firstLine, rest, _ := strings.Cut(body, "\n")
+	fields := strings.Fields(firstLine)
+	if len(fields) == 0 {
+		return "", "", false
+	}
+
+	switch fields[0] {
+	case "/approve":
+		input = "approve"
+	case "/reject":
+		input = "reject"
+	default:
+		return "", "", false
+	}
+
+	message = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(firstLine), fields[0]))
+	if rest = strings.TrimSpace(rest); rest != "" {
+		if message != "" {
+			message += "\n"
+		}
+		message += rest
+	}
+	return input, message, true
+}
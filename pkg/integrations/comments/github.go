@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package comments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations/github"
+)
+
+// githubCommentPayload holds the fields of the issue_comment event we use.
+type githubCommentPayload struct {
+	Action string `json:"action"`
+	Issue  struct {
+		Number      int              `json:"number"`
+		PullRequest *json.RawMessage `json:"pull_request"`
+	} `json:"issue"`
+	Comment struct {
+		Body string `json:"body"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"comment"`
+	Repository struct {
+		Name  string `json:"name"`
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+	} `json:"repository"`
+}
+
+// githubForge handles issue_comment webhooks from GitHub. It shares the
+// Secret of the GitHub check run reporter.
+type githubForge struct {
+	httpClient *http.Client
+}
+
+var _ forge = (*githubForge)(nil)
+
+func (*githubForge) provider() string { return "github" }
+
+func (*githubForge) secretName() string { return github.SecretName }
+
+// verify checks the X-Hub-Signature-256 header, the HMAC of the body keyed
+// with the webhook secret.
+func (*githubForge) verify(r *http.Request, body []byte, secret string) error {
+	signature, ok := strings.CutPrefix(r.Header.Get("X-Hub-Signature-256"), "sha256=")
+	if !ok {
+		return fmt.Errorf("missing X-Hub-Signature-256 header")
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("malformed X-Hub-Signature-256 header")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+func (*githubForge) parse(r *http.Request, body []byte) (*commentEvent, error) {
+	if r.Header.Get("X-GitHub-Event") != "issue_comment" {
+		return nil, nil
+	}
+	var p githubCommentPayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, err
+	}
+	// Pull requests are issues with a pull_request field.
+	if p.Action != "created" || p.Issue.PullRequest == nil {
+		return nil, nil
+	}
+	return &commentEvent{
+		Owner:  p.Repository.Owner.Login,
+		Repo:   p.Repository.Name,
+		Number: p.Issue.Number,
+		User:   p.Comment.User.Login,
+		Body:   p.Comment.Body,
+	}, nil
+}
+
+func (f *githubForge) comment(ctx context.Context, cfg integrations.Config, owner, repo string, number int, body string) error {
+	client := github.NewClient(f.httpClient, cfg.Get(apiURLKey, github.DefaultAPIURL), cfg.Get(tokenKey, ""))
+	return client.CreateIssueComment(ctx, owner, repo, number, body)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package comments
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations"
+)
+
+const (
+	// GitLabSecretName is the Secret, in the namespace of the ApprovalTask,
+	// which enables GitLab merge request comments for that namespace.
+	GitLabSecretName = "manual-approval-gate-gitlab"
+
+	// DefaultGitLabAPIURL is the REST API of gitlab.com.
+	DefaultGitLabAPIURL = "https://gitlab.com/api/v4"
+)
+
+// gitlabNotePayload holds the fields of the Note Hook event we use.
+type gitlabNotePayload struct {
+	ObjectKind       string `json:"object_kind"`
+	ObjectAttributes struct {
+		Note         string `json:"note"`
+		NoteableType string `json:"noteable_type"`
+	} `json:"object_attributes"`
+	MergeRequest struct {
+		IID int `json:"iid"`
+	} `json:"merge_request"`
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+}
+
+// gitlabForge handles Note Hook webhooks from GitLab.
+type gitlabForge struct {
+	httpClient *http.Client
+}
+
+var _ forge = (*gitlabForge)(nil)
+
+func (*gitlabForge) provider() string { return "gitlab" }
+
+func (*gitlabForge) secretName() string { return GitLabSecretName }
+
+// verify checks the X-Gitlab-Token header, which GitLab sets to the secret
+// token of the webhook.
+func (*gitlabForge) verify(r *http.Request, _ []byte, secret string) error {
+	token := r.Header.Get("X-Gitlab-Token")
+	if token == "" {
+		return fmt.Errorf("missing X-Gitlab-Token header")
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return fmt.Errorf("invalid token")
+	}
+	return nil
+}
+
+func (*gitlabForge) parse(r *http.Request, body []byte) (*commentEvent, error) {
+	if r.Header.Get("X-Gitlab-Event") != "Note Hook" {
+		return nil, nil
+	}
+	var p gitlabNotePayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, err
+	}
+	if p.ObjectKind != "note" || p.ObjectAttributes.NoteableType != "MergeRequest" {
+		return nil, nil
+	}
+	i := strings.LastIndex(p.Project.PathWithNamespace, "/")
+	if i < 0 {
+		return nil, fmt.Errorf("malformed project path %q", p.Project.PathWithNamespace)
+	}
+	return &commentEvent{
+		Owner:  p.Project.PathWithNamespace[:i],
+		Repo:   p.Project.PathWithNamespace[i+1:],
+		Number: p.MergeRequest.IID,
+		User:   p.User.Username,
+		Body:   p.ObjectAttributes.Note,
+	}, nil
+}
+
+// comment creates a note on the merge request, addressing the project by its
+// URL-encoded path.
+func (f *gitlabForge) comment(ctx context.Context, cfg integrations.Config, owner, repo string, number int, body string) error {
+	apiURL := strings.TrimSuffix(cfg.Get(apiURLKey, DefaultGitLabAPIURL), "/")
+	path := fmt.Sprintf("/projects/%s/merge_requests/%d/notes", url.PathEscape(owner+"/"+repo), number)
+
+	raw, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL+path, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := cfg.Get(tokenKey, ""); token != "" {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("POST %s: unexpected status %s: %s", path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package integrations holds the plumbing shared by the controllers that
+// connect ApprovalTasks to external systems such as chat tools, forges and
+// ticketing systems.
+package integrations
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	approvaltaskinformer "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/informers/approvaltask/v1alpha1/approvaltask"
+	listersapprovaltask "github.com/openshift-pipelines/manual-approval-gate/pkg/client/listers/approvaltask/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+// Interface is implemented by integrations that react to ApprovalTask changes.
+type Interface interface {
+	// ReconcileApprovalTask is called with a copy of the ApprovalTask every
+	// time it changes. Returned errors cause the key to be retried; return
+	// controller.NewRequeueAfter to poll an external system.
+	ReconcileApprovalTask(ctx context.Context, at *v1alpha1.ApprovalTask) error
+}
+
+// reconciler adapts an Interface to controller.Reconciler.
+type reconciler struct {
+	pkgreconciler.LeaderAwareFuncs
+
+	integration Interface
+	lister      listersapprovaltask.ApprovalTaskLister
+}
+
+var _ controller.Reconciler = (*reconciler)(nil)
+var _ pkgreconciler.LeaderAware = (*reconciler)(nil)
+
+// NewController returns a controller.Impl which calls integration for every
+// ApprovalTask known to the informer. Only the leader for a key calls the
+// integration so that external systems are not notified twice.
+func NewController(ctx context.Context, name string, integration Interface) *controller.Impl {
+	logger := logging.FromContext(ctx)
+	approvaltaskInformer := approvaltaskinformer.Get(ctx)
+	lister := approvaltaskInformer.Lister()
+
+	r := &reconciler{
+		LeaderAwareFuncs: pkgreconciler.LeaderAwareFuncs{
+			// Enqueue every ApprovalTask owned by a bucket when we become its leader.
+			PromoteFunc: func(bkt pkgreconciler.Bucket, enq func(pkgreconciler.Bucket, types.NamespacedName)) error {
+				all, err := lister.List(labels.Everything())
+				if err != nil {
+					return err
+				}
+				for _, at := range all {
+					enq(bkt, types.NamespacedName{Namespace: at.Namespace, Name: at.Name})
+				}
+				return nil
+			},
+		},
+		integration: integration,
+		lister:      lister,
+	}
+
+	impl := controller.NewContext(ctx, r, controller.ControllerOptions{WorkQueueName: name, Logger: logger.Named(name)})
+
+	if _, err := approvaltaskInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    impl.Enqueue,
+		UpdateFunc: controller.PassNew(impl.Enqueue),
+	}); err != nil {
+		logger.Panicf("couldn't register ApprovalTask informer event handler for %s: %v", name, err)
+	}
+
+	return impl
+}
+
+// Reconcile implements controller.Reconciler
+func (r *reconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Errorw("Invalid resource key", zap.String("key", key), zap.Error(err))
+		return nil
+	}
+
+	if !r.IsLeaderFor(types.NamespacedName{Namespace: namespace, Name: name}) {
+		return controller.NewSkipKey(key)
+	}
+
+	at, err := r.lister.ApprovalTasks(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return r.integration.ReconcileApprovalTask(ctx, at.DeepCopy())
+}
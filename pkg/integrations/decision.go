@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrations
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+// Decision is an approve or reject coming from an external system on behalf
+// of an authenticated user.
+type Decision struct {
+	Namespace string
+	Name      string
+	Username  string
+	Groups    []string
+	// Input is either "approve" or "reject"
+	Input   string
+	Message string
+}
+
+// ClientFactory returns an ApprovalTask client which acts as the given user.
+type ClientFactory func(username string, groups []string) (versioned.Interface, error)
+
+// ImpersonatingClientFactory returns a ClientFactory that impersonates the
+// user on top of cfg. Because the update is sent as the approver, the
+// admission webhook applies exactly the checks it applies to the CLI. The
+// controller is named as the impersonator in the extra of the user.
+func ImpersonatingClientFactory(cfg *rest.Config) ClientFactory {
+	return func(username string, groups []string) (versioned.Interface, error) {
+		impersonated := rest.CopyConfig(cfg)
+		impersonated.Impersonate = rest.ImpersonationConfig{
+			UserName: username,
+			Groups:   groups,
+			Extra: map[string][]string{
+				v1alpha1.ImpersonatorExtraKey: {v1alpha1.ControllerImpersonator},
+			},
+		}
+		return versioned.NewForConfig(impersonated)
+	}
+}
+
+// Apply records the decision on the ApprovalTask. Errors returned by the API
+// server, including admission webhook denials, are returned unchanged so that
+// they can be relayed to the user.
+func Apply(ctx context.Context, clientFor ClientFactory, d Decision) error {
+	if d.Input != "approve" && d.Input != "reject" {
+		return fmt.Errorf("invalid input value: '%s'. Supported values are 'approve' or 'reject'", d.Input)
+	}
+	if d.Username == "" {
+		return fmt.Errorf("cannot %s ApprovalTask %s/%s without a user", d.Input, d.Namespace, d.Name)
+	}
+
+	client, err := clientFor(d.Username, d.Groups)
+	if err != nil {
+		return err
+	}
+
+	approvalTasks := client.OpenshiftpipelinesV1alpha1().ApprovalTasks(d.Namespace)
+	at, err := approvalTasks.Get(ctx, d.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	at.SetApproverInput(d.Username, d.Groups, d.Input, d.Message)
+	at.SetImpersonatedBy(func(name string) bool { return name == d.Username }, v1alpha1.MemberOfGroups(d.Groups), v1alpha1.ControllerImpersonator)
+
+	_, err = approvalTasks.Update(ctx, at, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultAPIURL is the REST API of github.com. GitHub Enterprise Server
+// serves it under https://<host>/api/v3.
+const DefaultAPIURL = "https://api.github.com"
+
+// Client is a minimal GitHub REST API client.
+type Client struct {
+	httpClient *http.Client
+	apiURL     string
+	token      string
+}
+
+// NewClient returns a Client for the API at apiURL authenticating with token.
+func NewClient(httpClient *http.Client, apiURL, token string) *Client {
+	return &Client{
+		httpClient: httpClient,
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		token:      token,
+	}
+}
+
+// CheckRunOutput is the title and markdown summary shown on a check run.
+type CheckRunOutput struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+}
+
+// CheckRun is a check run on a commit.
+type CheckRun struct {
+	ID         int64           `json:"id,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	HeadSHA    string          `json:"head_sha,omitempty"`
+	Status     string          `json:"status,omitempty"`
+	Conclusion string          `json:"conclusion,omitempty"`
+	DetailsURL string          `json:"details_url,omitempty"`
+	Output     *CheckRunOutput `json:"output,omitempty"`
+}
+
+// CommitStatus is a commit status, for tokens which cannot create check runs.
+type CommitStatus struct {
+	State       string `json:"state"`
+	Context     string `json:"context"`
+	Description string `json:"description,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+}
+
+// CreateCheckRun creates a check run in owner/repo and returns its id.
+func (c *Client) CreateCheckRun(ctx context.Context, owner, repo string, run CheckRun) (int64, error) {
+	var created CheckRun
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/check-runs", owner, repo), run, &created); err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+// UpdateCheckRun updates the check run id in owner/repo.
+func (c *Client) UpdateCheckRun(ctx context.Context, owner, repo string, id int64, run CheckRun) error {
+	return c.do(ctx, http.MethodPatch, fmt.Sprintf("/repos/%s/%s/check-runs/%d", owner, repo, id), run, nil)
+}
+
+// CreateCommitStatus sets a commit status on sha in owner/repo.
+func (c *Client) CreateCommitStatus(ctx context.Context, owner, repo, sha string, status CommitStatus) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/statuses/%s", owner, repo, sha), status, nil)
+}
+
+// CreateIssueComment comments on the issue or pull request number in owner/repo.
+func (c *Client) CreateIssueComment(ctx context.Context, owner, repo string, number int, body string) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/%s/issues/%d/comments", owner, repo, number), map[string]string{"body": body}, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		raw, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.apiURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: unexpected status %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package github reports ApprovalTasks of PipelineRuns triggered from GitHub
+// as check runs on the commit being built.
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	approvaltaskclient "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/client"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations"
+	clientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	pipelineclient "github.com/tektoncd/pipeline/pkg/client/injection/client"
+	"k8s.io/client-go/kubernetes"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// SecretName is the Secret, in the namespace of the ApprovalTask, which
+	// enables the GitHub integration for that namespace.
+	SecretName = "manual-approval-gate-github"
+
+	// CheckRunAnnotation records in status.annotations the id of the check run.
+	CheckRunAnnotation = "github.openshift-pipelines.org/check-run-id"
+	// ReportedAnnotation records in status.annotations the last state and
+	// number of approvals reported to GitHub.
+	ReportedAnnotation = "github.openshift-pipelines.org/reported"
+
+	// RepositoryKey and RevisionKey let PipelineRuns not created by
+	// Pipelines-as-Code name the "owner/repo" and commit they build.
+	RepositoryKey = "tekton.dev/git-repository"
+	RevisionKey   = "tekton.dev/git-revision"
+
+	pacProviderKey   = "pipelinesascode.tekton.dev/git-provider"
+	pacOrgKey        = "pipelinesascode.tekton.dev/url-org"
+	pacRepositoryKey = "pipelinesascode.tekton.dev/url-repository"
+	pacSHAKey        = "pipelinesascode.tekton.dev/sha"
+
+	tokenKey  = "token"
+	apiURLKey = "api-url"
+	reportKey = "report"
+
+	reportCheckRun = "check-run"
+	reportStatus   = "status"
+)
+
+// commit identifies the commit a PipelineRun builds.
+type commit struct {
+	Owner string
+	Repo  string
+	SHA   string
+}
+
+// reporter creates a check run when an ApprovalTask is created and keeps it
+// up to date until the ApprovalTask is approved or rejected.
+type reporter struct {
+	kubeClientSet         kubernetes.Interface
+	pipelineClientSet     clientset.Interface
+	approvaltaskClientSet versioned.Interface
+	httpClient            *http.Client
+}
+
+var _ integrations.Interface = (*reporter)(nil)
+
+// NewController instantiates the GitHub check run reporter.
+func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	return integrations.NewController(ctx, "GitHubReporter", &reporter{
+		kubeClientSet:         kubeclient.Get(ctx),
+		pipelineClientSet:     pipelineclient.Get(ctx),
+		approvaltaskClientSet: approvaltaskclient.Get(ctx),
+		httpClient:            &http.Client{Timeout: 10 * time.Second},
+	})
+}
+
+// ReconcileApprovalTask implements integrations.Interface
+func (r *reporter) ReconcileApprovalTask(ctx context.Context, at *v1alpha1.ApprovalTask) error {
+	logger := logging.FromContext(ctx)
+
+	if at.Status.State == "" {
+		return nil
+	}
+	progress := fmt.Sprintf("%s/%d", at.Status.State, at.Status.ApprovalsReceived)
+	if at.Status.Annotations[ReportedAnnotation] == progress {
+		return nil
+	}
+
+	cfg, err := integrations.GetConfig(ctx, r.kubeClientSet, at.Namespace, SecretName)
+	if err != nil || cfg == nil {
+		return err
+	}
+
+	metadata, err := integrations.PipelineRunMetadata(ctx, r.pipelineClientSet, at)
+	if err != nil {
+		return err
+	}
+	c, ok := commitFromMetadata(metadata)
+	if !ok {
+		return nil
+	}
+
+	client := NewClient(r.httpClient, cfg.Get(apiURLKey, DefaultAPIURL), cfg.Get(tokenKey, ""))
+	annotations := map[string]string{ReportedAnnotation: progress}
+
+	if cfg.Get(reportKey, reportCheckRun) == reportStatus {
+		if err := client.CreateCommitStatus(ctx, c.Owner, c.Repo, c.SHA, commitStatus(at)); err != nil {
+			return fmt.Errorf("failed to set commit status for ApprovalTask %s/%s: %w", at.Namespace, at.Name, err)
+		}
+	} else {
+		run := checkRun(at, c.SHA)
+		if raw := at.Status.Annotations[CheckRunAnnotation]; raw != "" {
+			id, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: %w", CheckRunAnnotation, raw, err)
+			}
+			if err := client.UpdateCheckRun(ctx, c.Owner, c.Repo, id, run); err != nil {
+				return fmt.Errorf("failed to update check run for ApprovalTask %s/%s: %w", at.Namespace, at.Name, err)
+			}
+		} else {
+			id, err := client.CreateCheckRun(ctx, c.Owner, c.Repo, run)
+			if err != nil {
+				return fmt.Errorf("failed to create check run for ApprovalTask %s/%s: %w", at.Namespace, at.Name, err)
+			}
+			annotations[CheckRunAnnotation] = strconv.FormatInt(id, 10)
+		}
+	}
+	logger.Infof("Reported %s ApprovalTask %s/%s to %s/%s@%s", at.Status.State, at.Namespace, at.Name, c.Owner, c.Repo, c.SHA)
+
+	return integrations.SetStatusAnnotations(ctx, r.approvaltaskClientSet, at, annotations)
+}
+
+// commitFromMetadata finds the commit from the Pipelines-as-Code annotations,
+// or from the tekton.dev/git-repository and tekton.dev/git-revision ones.
+func commitFromMetadata(metadata map[string]string) (commit, bool) {
+	if provider := metadata[pacProviderKey]; provider != "" && provider != "github" {
+		return commit{}, false
+	}
+	if metadata[pacSHAKey] != "" && metadata[pacOrgKey] != "" && metadata[pacRepositoryKey] != "" {
+		return commit{
+			Owner: metadata[pacOrgKey],
+			Repo:  metadata[pacRepositoryKey],
+			SHA:   metadata[pacSHAKey],
+		}, true
+	}
+
+	repository := strings.TrimSuffix(metadata[RepositoryKey], ".git")
+	if i := strings.Index(repository, "://"); i >= 0 {
+		// Accept full clone URLs, keeping only the "owner/repo" path.
+		repository = repository[i+3:]
+		if j := strings.Index(repository, "/"); j >= 0 {
+			repository = repository[j+1:]
+		}
+	}
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || metadata[RevisionKey] == "" {
+		return commit{}, false
+	}
+	return commit{Owner: parts[0], Repo: parts[1], SHA: metadata[RevisionKey]}, true
+}
+
+func checkRunName(at *v1alpha1.ApprovalTask) string {
+	return "Manual approval / " + at.Name
+}
+
+func checkRun(at *v1alpha1.ApprovalTask, sha string) CheckRun {
+	run := CheckRun{
+		Name:    checkRunName(at),
+		HeadSHA: sha,
+		Status:  "in_progress",
+		Output: &CheckRunOutput{
+			Title:   title(at),
+			Summary: summary(at),
+		},
+	}
+	switch at.Status.State {
+	case "approved":
+		run.Status = "completed"
+		run.Conclusion = "success"
+	case "rejected":
+		run.Status = "completed"
+		run.Conclusion = "failure"
+	}
+	return run
+}
+
+func commitStatus(at *v1alpha1.ApprovalTask) CommitStatus {
+	state := "pending"
+	switch at.Status.State {
+	case "approved":
+		state = "success"
+	case "rejected":
+		state = "failure"
+	}
+	return CommitStatus{
+		State:       state,
+		Context:     checkRunName(at),
+		Description: title(at),
+	}
+}
+
+func title(at *v1alpha1.ApprovalTask) string {
+	switch at.Status.State {
+	case "approved":
+		return "Approved"
+	case "rejected":
+		return "Rejected"
+	default:
+		return fmt.Sprintf("Waiting for approval (%d of %d)", at.Status.ApprovalsReceived, approvalsRequired(at))
+	}
+}
+
+func summary(at *v1alpha1.ApprovalTask) string {
+	var b strings.Builder
+	if at.Spec.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", at.Spec.Description)
+	}
+	fmt.Fprintf(&b, "ApprovalTask `%s` in namespace `%s` requires %d approval(s), %d received.\n\n",
+		at.Name, at.Namespace, approvalsRequired(at), at.Status.ApprovalsReceived)
+
+	b.WriteString("| Approver | Type | Response |\n|---|---|---|\n")
+	responses := map[string]v1alpha1.ApproverState{}
+	for _, response := range at.Status.ApproversResponse {
+		responses[response.Name] = response
+	}
+	for _, approver := range at.Spec.Approvers {
+		response := "pending"
+		if r, ok := responses[approver.Name]; ok {
+			response = r.Response
+			if r.Message != "" {
+				response = fmt.Sprintf("%s: %s", response, r.Message)
+			}
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", approver.Name, v1alpha1.DefaultedApproverType(approver.Type), response)
+	}
+	return b.String()
+}
+
+func approvalsRequired(at *v1alpha1.ApprovalTask) int {
+	if at.Status.ApprovalsRequired != 0 {
+		return at.Status.ApprovalsRequired
+	}
+	return at.Spec.NumberOfApprovalsRequired
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCommitFromMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		want     commit
+		wantOK   bool
+	}{{
+		name: "pipelines-as-code",
+		metadata: map[string]string{
+			pacProviderKey:   "github",
+			pacOrgKey:        "openshift-pipelines",
+			pacRepositoryKey: "manual-approval-gate",
+			pacSHAKey:        "abc123",
+		},
+		want:   commit{Owner: "openshift-pipelines", Repo: "manual-approval-gate", SHA: "abc123"},
+		wantOK: true,
+	}, {
+		name: "tekton annotations with clone URL",
+		metadata: map[string]string{
+			RepositoryKey: "https://ghe.example.com/org/repo.git",
+			RevisionKey:   "def456",
+		},
+		want:   commit{Owner: "org", Repo: "repo", SHA: "def456"},
+		wantOK: true,
+	}, {
+		name: "tekton annotations with owner/repo",
+		metadata: map[string]string{
+			RepositoryKey: "org/repo",
+			RevisionKey:   "def456",
+		},
+		want:   commit{Owner: "org", Repo: "repo", SHA: "def456"},
+		wantOK: true,
+	}, {
+		name: "other git provider",
+		metadata: map[string]string{
+			pacProviderKey:   "gitlab",
+			pacOrgKey:        "org",
+			pacRepositoryKey: "repo",
+			pacSHAKey:        "abc123",
+		},
+	}, {
+		name:     "no commit",
+		metadata: map[string]string{RepositoryKey: "org/repo"},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := commitFromMetadata(tc.metadata)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestReconcileApprovalTask(t *testing.T) {
+	type request struct {
+		Method string
+		Path   string
+		Run    CheckRun
+	}
+	var requests []request
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var run CheckRun
+		json.NewDecoder(r.Body).Decode(&run)
+		requests = append(requests, request{Method: r.Method, Path: r.URL.Path, Run: run})
+		assert.Equal(t, "Bearer s3cr3t", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"id": 42}`))
+	}))
+	defer api.Close()
+
+	at := &v1alpha1.ApprovalTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "at-1",
+			Namespace: "foo",
+			Labels: map[string]string{
+				pacOrgKey:        "org",
+				pacRepositoryKey: "repo",
+				pacSHAKey:        "abc123",
+			},
+		},
+		Spec: v1alpha1.ApprovalTaskSpec{
+			Approvers: []v1alpha1.ApproverDetails{
+				{Name: "alice", Input: "pending", Type: "User"},
+			},
+			NumberOfApprovalsRequired: 1,
+		},
+		Status: v1alpha1.ApprovalTaskStatus{State: "pending"},
+	}
+	atClient := fake.NewSimpleClientset(at)
+	r := &reporter{
+		kubeClientSet: fakekube.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: SecretName, Namespace: "foo"},
+			Data: map[string][]byte{
+				tokenKey:  []byte("s3cr3t"),
+				apiURLKey: []byte(api.URL + "/api/v3"),
+			},
+		}),
+		approvaltaskClientSet: atClient,
+		httpClient:            api.Client(),
+	}
+
+	ctx := context.Background()
+	assert.NoError(t, r.ReconcileApprovalTask(ctx, at.DeepCopy()))
+	if !assert.Len(t, requests, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, http.MethodPost, requests[0].Method)
+	assert.Equal(t, "/api/v3/repos/org/repo/check-runs", requests[0].Path)
+	assert.Equal(t, "abc123", requests[0].Run.HeadSHA)
+	assert.Equal(t, "in_progress", requests[0].Run.Status)
+
+	updated, err := atClient.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(ctx, "at-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "42", updated.Status.Annotations[CheckRunAnnotation])
+	assert.Equal(t, "pending/0", updated.Status.Annotations[ReportedAnnotation])
+
+	// Nothing changed, nothing is reported.
+	assert.NoError(t, r.ReconcileApprovalTask(ctx, updated.DeepCopy()))
+	assert.Len(t, requests, 1)
+
+	updated.Status.State = "rejected"
+	assert.NoError(t, r.ReconcileApprovalTask(ctx, updated.DeepCopy()))
+	if !assert.Len(t, requests, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, http.MethodPatch, requests[1].Method)
+	assert.Equal(t, "/api/v3/repos/org/repo/check-runs/42", requests[1].Path)
+	assert.Equal(t, "completed", requests[1].Run.Status)
+	assert.Equal(t, "failure", requests[1].Run.Conclusion)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client is a minimal client of the Jira REST API version 2, which Jira Cloud
+// and Jira Data Center both serve.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	user       string
+	token      string
+}
+
+// NewClient returns a Client for the Jira site at baseURL. With a user it
+// authenticates with basic auth and an API token (Jira Cloud), without one
+// with the token as a bearer personal access token (Jira Data Center).
+func NewClient(httpClient *http.Client, baseURL, user, token string) *Client {
+	return &Client{
+		httpClient: httpClient,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		user:       user,
+		token:      token,
+	}
+}
+
+// User is a Jira user as found in issues, changelogs and webhooks. Jira Cloud
+// identifies users by accountId, Jira Data Center by name.
+type User struct {
+	AccountID    string `json:"accountId,omitempty"`
+	Name         string `json:"name,omitempty"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
+
+// ChangeItem is a field change in a changelog.
+type ChangeItem struct {
+	Field    string `json:"field"`
+	ToString string `json:"toString"`
+}
+
+// History is a changelog entry.
+type History struct {
+	Author User         `json:"author"`
+	Items  []ChangeItem `json:"items"`
+}
+
+// Issue is the part of a Jira issue the integration uses.
+type Issue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Status struct {
+			Name string `json:"name"`
+		} `json:"status"`
+	} `json:"fields"`
+	Changelog struct {
+		Histories []History `json:"histories"`
+	} `json:"changelog"`
+}
+
+// CreateIssue creates an issue in project and returns its key.
+func (c *Client) CreateIssue(ctx context.Context, project, issueType, summary, description string) (string, error) {
+	in := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": project},
+			"issuetype":   map[string]string{"name": issueType},
+			"summary":     summary,
+			"description": description,
+		},
+	}
+	var created Issue
+	if err := c.do(ctx, http.MethodPost, "/rest/api/2/issue", in, &created); err != nil {
+		return "", err
+	}
+	return created.Key, nil
+}
+
+// AddComment comments on the issue key.
+func (c *Client) AddComment(ctx context.Context, key, body string) error {
+	return c.do(ctx, http.MethodPost, fmt.Sprintf("/rest/api/2/issue/%s/comment", url.PathEscape(key)), map[string]string{"body": body}, nil)
+}
+
+// GetIssue returns the status and changelog of the issue key.
+func (c *Client) GetIssue(ctx context.Context, key string) (*Issue, error) {
+	var issue Issue
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/rest/api/2/issue/%s?fields=status&expand=changelog", url.PathEscape(key)), nil, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// LastStatusChange returns the author of the latest transition of the issue
+// to its current status, if the changelog has it.
+func (i *Issue) LastStatusChange() (User, bool) {
+	for h := len(i.Changelog.Histories) - 1; h >= 0; h-- {
+		for _, item := range i.Changelog.Histories[h].Items {
+			if item.Field == "status" && item.ToString == i.Fields.Status.Name {
+				return i.Changelog.Histories[h].Author, true
+			}
+		}
+	}
+	return User{}, false
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		raw, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.token)
+	} else if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: unexpected status %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
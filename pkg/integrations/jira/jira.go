@@ -0,0 +1,435 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jira links ApprovalTasks to Jira issues: an issue is opened, or
+// commented on, when an ApprovalTask becomes pending, and transitioning the
+// issue approves or rejects it.
+package jira
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	approvaltaskclient "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/client"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations"
+	clientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	pipelineclient "github.com/tektoncd/pipeline/pkg/client/injection/client"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// SecretName is the Secret, in the namespace of the ApprovalTask, which
+	// enables the Jira integration for that namespace.
+	SecretName = "manual-approval-gate-jira"
+
+	// IssueAnnotation on a PipelineRun names an existing issue to comment on
+	// instead of opening a new one.
+	IssueAnnotation = "jira.openshift-pipelines.org/issue"
+
+	// UserMappingKey is the key of the mapping of the Jira users, by their
+	// username, email address or account id, in
+	// integrations.UserMappingConfigMap.
+	UserMappingKey = "jira"
+
+	// IssueKeyResult is the CustomRun result holding the key of the issue.
+	IssueKeyResult = "jiraIssueKey"
+	// IssueKeyAnnotation records in status.annotations the key of the issue.
+	// It is published as the IssueKeyResult of the CustomRun.
+	IssueKeyAnnotation = v1alpha1.ResultAnnotationPrefix + IssueKeyResult
+	// DecidedAnnotation records in status.annotations the last issue status,
+	// and the user who moved the issue to it, applied to the ApprovalTask.
+	DecidedAnnotation = "jira.openshift-pipelines.org/decided"
+	// ReportedAnnotation records in status.annotations the final state of
+	// the ApprovalTask commented on the issue.
+	ReportedAnnotation = "jira.openshift-pipelines.org/reported"
+
+	urlKey              = "url"
+	userKey             = "user"
+	tokenKey            = "token"
+	projectKey          = "project"
+	issueTypeKey        = "issue-type"
+	approvedStatusesKey = "approved-statuses"
+	rejectedStatusesKey = "rejected-statuses"
+	pollIntervalKey     = "poll-interval"
+	webhookSecretKey    = "webhook-secret"
+)
+
+// linker opens or comments on an issue when an ApprovalTask becomes pending,
+// applies the transitions of the issue to the ApprovalTask and comments the
+// outcome once it is approved or rejected.
+type linker struct {
+	kubeClientSet         kubernetes.Interface
+	pipelineClientSet     clientset.Interface
+	approvaltaskClientSet versioned.Interface
+	clientFor             integrations.ClientFactory
+	httpClient            *http.Client
+	// systemNamespace holds the integrations.UserMappingConfigMap
+	systemNamespace string
+	logger          *zap.SugaredLogger
+}
+
+var _ integrations.Interface = (*linker)(nil)
+
+// NewController instantiates the Jira linker and registers the endpoint
+// receiving the issue webhooks.
+func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	l := &linker{
+		kubeClientSet:         kubeclient.Get(ctx),
+		pipelineClientSet:     pipelineclient.Get(ctx),
+		approvaltaskClientSet: approvaltaskclient.Get(ctx),
+		clientFor:             integrations.ImpersonatingClientFactory(injection.GetConfig(ctx)),
+		httpClient:            &http.Client{Timeout: 10 * time.Second},
+		systemNamespace:       system.Namespace(),
+		logger:                logging.FromContext(ctx).Named("jira"),
+	}
+
+	integrations.HandleCallback("POST /jira/{namespace}", &webhookHandler{linker: l})
+
+	return integrations.NewController(ctx, "JiraLinker", l)
+}
+
+func (l *linker) client(cfg integrations.Config) *Client {
+	return NewClient(l.httpClient, cfg.Get(urlKey, ""), cfg.Get(userKey, ""), cfg.Get(tokenKey, ""))
+}
+
+// ReconcileApprovalTask implements integrations.Interface
+func (l *linker) ReconcileApprovalTask(ctx context.Context, at *v1alpha1.ApprovalTask) error {
+	key := at.Status.Annotations[IssueKeyAnnotation]
+	switch {
+	case at.Status.State == "":
+		return nil
+	case key == "" && at.Status.State != "pending":
+		return nil
+	case integrations.IsFinal(at) && at.Status.Annotations[ReportedAnnotation] == at.Status.State:
+		return nil
+	}
+
+	cfg, err := integrations.GetConfig(ctx, l.kubeClientSet, at.Namespace, SecretName)
+	if err != nil || cfg == nil {
+		return err
+	}
+	if cfg.Get(urlKey, "") == "" {
+		l.logger.Warnf("Secret %s/%s has no %s, not linking Jira issues", at.Namespace, SecretName, urlKey)
+		return nil
+	}
+	client := l.client(cfg)
+
+	if key == "" {
+		return l.link(ctx, cfg, client, at)
+	}
+
+	if integrations.IsFinal(at) {
+		if err := client.AddComment(ctx, key, outcomeComment(at)); err != nil {
+			return fmt.Errorf("failed to comment on Jira issue %s for ApprovalTask %s/%s: %w", key, at.Namespace, at.Name, err)
+		}
+		return integrations.SetStatusAnnotations(ctx, l.approvaltaskClientSet, at, map[string]string{
+			ReportedAnnotation: at.Status.State,
+		})
+	}
+
+	// Without polling, transitions are learnt from the webhook.
+	raw := cfg.Get(pollIntervalKey, "")
+	if raw == "" {
+		return nil
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil {
+		l.logger.Warnf("Secret %s/%s has an invalid %s %q: %v", at.Namespace, SecretName, pollIntervalKey, raw, err)
+		return nil
+	}
+
+	issue, err := client.GetIssue(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to get Jira issue %s for ApprovalTask %s/%s: %w", key, at.Namespace, at.Name, err)
+	}
+	if user, ok := issue.LastStatusChange(); ok {
+		if err := l.decide(ctx, cfg, client, at, issue.Fields.Status.Name, user); err != nil {
+			return err
+		}
+	}
+	return controller.NewRequeueAfter(interval)
+}
+
+// link opens an issue in the configured project, or comments on the issue
+// named by the PipelineRun, and records its key.
+func (l *linker) link(ctx context.Context, cfg integrations.Config, client *Client, at *v1alpha1.ApprovalTask) error {
+	metadata, err := integrations.PipelineRunMetadata(ctx, l.pipelineClientSet, at)
+	if err != nil {
+		return err
+	}
+
+	key := metadata[IssueAnnotation]
+	if key != "" {
+		if err := client.AddComment(ctx, key, issueDescription(cfg, at)); err != nil {
+			return fmt.Errorf("failed to comment on Jira issue %s for ApprovalTask %s/%s: %w", key, at.Namespace, at.Name, err)
+		}
+	} else {
+		project := cfg.Get(projectKey, "")
+		if project == "" {
+			l.logger.Warnf("Secret %s/%s has no %s, not opening a Jira issue for ApprovalTask %s", at.Namespace, SecretName, projectKey, at.Name)
+			return nil
+		}
+		summary := fmt.Sprintf("Approval required: %s/%s", at.Namespace, at.Name)
+		key, err = client.CreateIssue(ctx, project, cfg.Get(issueTypeKey, "Task"), summary, issueDescription(cfg, at))
+		if err != nil {
+			return fmt.Errorf("failed to open Jira issue for ApprovalTask %s/%s: %w", at.Namespace, at.Name, err)
+		}
+	}
+	l.logger.Infof("Linked ApprovalTask %s/%s to Jira issue %s", at.Namespace, at.Name, key)
+
+	return integrations.SetStatusAnnotations(ctx, l.approvaltaskClientSet, at, map[string]string{
+		IssueKeyAnnotation: key,
+	})
+}
+
+// decide records the decision matching the status of the issue as the user
+// who moved the issue to it. A transition is applied once; when it is denied
+// the reason is commented on the issue.
+func (l *linker) decide(ctx context.Context, cfg integrations.Config, client *Client, at *v1alpha1.ApprovalTask, status string, user User) error {
+	input := inputForStatus(cfg, status)
+	if input == "" {
+		return nil
+	}
+	decided := status + "/" + identity(user)
+	if at.Status.Annotations[DecidedAnnotation] == decided {
+		return nil
+	}
+
+	key := at.Status.Annotations[IssueKeyAnnotation]
+	mapping, err := integrations.LoadUserMapping(ctx, l.kubeClientSet, l.systemNamespace, UserMappingKey)
+	if err != nil {
+		return err
+	}
+	username, err := jiraUsername(mapping, user)
+	if err == nil {
+		err = integrations.Apply(ctx, l.clientFor, integrations.Decision{
+			Namespace: at.Namespace,
+			Name:      at.Name,
+			Username:  username,
+			Input:     input,
+			Message:   fmt.Sprintf("Jira issue %s moved to %s", key, status),
+		})
+	}
+	if err != nil {
+		l.logger.Warnf("Jira user %s could not %s ApprovalTask %s/%s: %v", identity(user), input, at.Namespace, at.Name, err)
+		comment := fmt.Sprintf("Moving the issue to %s did not %s ApprovalTask %s/%s: %v", status, input, at.Namespace, at.Name, err)
+		if err := client.AddComment(ctx, key, comment); err != nil {
+			return fmt.Errorf("failed to comment on Jira issue %s: %w", key, err)
+		}
+	} else {
+		l.logger.Infof("Jira user %s sent %s as %s for ApprovalTask %s/%s", identity(user), input, username, at.Namespace, at.Name)
+	}
+
+	// The decision changed the ApprovalTask, so the annotation is set on the
+	// latest version of it.
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := l.approvaltaskClientSet.OpenshiftpipelinesV1alpha1().ApprovalTasks(at.Namespace).Get(ctx, at.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		return integrations.SetStatusAnnotations(ctx, l.approvaltaskClientSet, latest, map[string]string{
+			DecidedAnnotation: decided,
+		})
+	})
+}
+
+// inputForStatus returns "approve" or "reject" when status is one of the
+// configured approved or rejected statuses.
+func inputForStatus(cfg integrations.Config, status string) string {
+	if containsFold(cfg.Get(approvedStatusesKey, "Approved"), status) {
+		return "approve"
+	}
+	if containsFold(cfg.Get(rejectedStatusesKey, "Rejected"), status) {
+		return "reject"
+	}
+	return ""
+}
+
+func containsFold(list, s string) bool {
+	for _, item := range strings.Split(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(item), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// identity is how a Jira user is referred to: the username on Jira Data
+// Center, the email address or account id on Jira Cloud.
+func identity(u User) string {
+	for _, id := range []string{u.Name, u.EmailAddress, u.AccountID} {
+		if id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// jiraUsername maps the first identity of the user found in the mapping to
+// a Kubernetes username. Users without a mapped identity are rejected.
+func jiraUsername(mapping integrations.UserMapping, u User) (string, error) {
+	for _, id := range []string{u.Name, u.EmailAddress, u.AccountID} {
+		if username, err := mapping.Username(id); err == nil {
+			return username, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", integrations.ErrUnmappedIdentity, identity(u))
+}
+
+func issueDescription(cfg integrations.Config, at *v1alpha1.ApprovalTask) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ApprovalTask %s in namespace %s is waiting for %d approval(s).\n\n", at.Name, at.Namespace, at.Status.ApprovalsRequired)
+	if at.Spec.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", at.Spec.Description)
+	}
+	if pr := at.Labels[integrations.PipelineRunLabelKey]; pr != "" {
+		fmt.Fprintf(&b, "PipelineRun: %s\n\n", pr)
+	}
+	b.WriteString("Approvers:\n")
+	for _, approver := range at.Spec.Approvers {
+		if v1alpha1.IsGroupApproverType(approver.Type) {
+			fmt.Fprintf(&b, "* %s (%s)\n", approver.Name, strings.ToLower(approver.Type))
+		} else {
+			fmt.Fprintf(&b, "* %s\n", approver.Name)
+		}
+	}
+	fmt.Fprintf(&b, "\nAn approver can approve by moving this issue to %s, or reject by moving it to %s.\n",
+		cfg.Get(approvedStatusesKey, "Approved"), cfg.Get(rejectedStatusesKey, "Rejected"))
+	return b.String()
+}
+
+func outcomeComment(at *v1alpha1.ApprovalTask) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ApprovalTask %s/%s is %s.\n", at.Namespace, at.Name, at.Status.State)
+	for _, response := range at.Status.ApproversResponse {
+		if response.Message != "" {
+			fmt.Fprintf(&b, "* %s: %s (%s)\n", response.Name, response.Response, response.Message)
+		} else {
+			fmt.Fprintf(&b, "* %s: %s\n", response.Name, response.Response)
+		}
+	}
+	return b.String()
+}
+
+// webhookHandler serves the issue webhooks of Jira. The namespace in the path
+// selects the Secret holding the webhook secret and the ApprovalTasks the
+// issues are linked to.
+type webhookHandler struct {
+	linker *linker
+}
+
+type webhookEvent struct {
+	WebhookEvent string `json:"webhookEvent"`
+	User         User   `json:"user"`
+	Issue        Issue  `json:"issue"`
+}
+
+// ServeHTTP applies the new status of an issue to the pending ApprovalTasks
+// linked to it.
+func (h *webhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	l := h.linker
+	namespace := r.PathValue("namespace")
+
+	cfg, err := integrations.GetConfig(ctx, l.kubeClientSet, namespace, SecretName)
+	if err != nil {
+		http.Error(w, "failed to read configuration", http.StatusInternalServerError)
+		return
+	}
+	secret := cfg.Get(webhookSecretKey, "")
+	if secret == "" {
+		http.Error(w, "Jira webhooks are not enabled for this namespace", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if err := verifySignature(r, body, secret); err != nil {
+		l.logger.Warnf("Rejected Jira webhook for namespace %s: %v", namespace, err)
+		http.Error(w, "invalid webhook signature", http.StatusUnauthorized)
+		return
+	}
+
+	var ev webhookEvent
+	if err := json.Unmarshal(body, &ev); err != nil {
+		http.Error(w, "malformed event", http.StatusBadRequest)
+		return
+	}
+	if ev.WebhookEvent != "jira:issue_updated" || ev.Issue.Key == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	list, err := l.approvaltaskClientSet.OpenshiftpipelinesV1alpha1().ApprovalTasks(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		http.Error(w, "failed to list ApprovalTasks", http.StatusInternalServerError)
+		return
+	}
+	client := l.client(cfg)
+	for i := range list.Items {
+		at := &list.Items[i]
+		if at.Status.State != "pending" || at.Status.Annotations[IssueKeyAnnotation] != ev.Issue.Key {
+			continue
+		}
+		if err := l.decide(ctx, cfg, client, at, ev.Issue.Fields.Status.Name, ev.User); err != nil {
+			l.logger.Errorf("Failed to apply Jira issue %s to ApprovalTask %s/%s: %v", ev.Issue.Key, namespace, at.Name, err)
+			http.Error(w, "failed to apply the issue status", http.StatusInternalServerError)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// verifySignature checks the X-Hub-Signature header Jira sets to the HMAC of
+// the body keyed with the webhook secret.
+func verifySignature(r *http.Request, body []byte, secret string) error {
+	signature, ok := strings.CutPrefix(r.Header.Get("X-Hub-Signature"), "sha256=")
+	if !ok {
+		return fmt.Errorf("missing X-Hub-Signature header")
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("malformed X-Hub-Signature header")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
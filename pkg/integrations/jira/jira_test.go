@@ -0,0 +1,256 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jira
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/fake"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/controller"
+)
+
+const webhookSecret = "hunter2"
+
+func pendingApprovalTask() *v1alpha1.ApprovalTask {
+	return &v1alpha1.ApprovalTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "at-1", Namespace: "foo"},
+		Spec: v1alpha1.ApprovalTaskSpec{
+			Approvers: []v1alpha1.ApproverDetails{
+				{Name: "alice", Input: "pending", Type: "User"},
+			},
+			NumberOfApprovalsRequired: 1,
+			Description:               "Deploy to production",
+		},
+		Status: v1alpha1.ApprovalTaskStatus{State: "pending", ApprovalsRequired: 1},
+	}
+}
+
+// jiraAPI is a fake Jira site serving a single issue.
+type jiraAPI struct {
+	server   *httptest.Server
+	created  []map[string]interface{}
+	comments []string
+	status   string
+}
+
+func newJiraAPI(t *testing.T) *jiraAPI {
+	api := &jiraAPI{status: "To Do"}
+	api.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, token, _ := r.BasicAuth()
+		assert.Equal(t, "bot@example.com", user)
+		assert.Equal(t, "s3cr3t", token)
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+			var in map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&in)
+			api.created = append(api.created, in)
+			w.Write([]byte(`{"key": "OPS-1"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/OPS-1/comment":
+			var in map[string]string
+			json.NewDecoder(r.Body).Decode(&in)
+			api.comments = append(api.comments, in["body"])
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/OPS-1":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"key":    "OPS-1",
+				"fields": map[string]interface{}{"status": map[string]string{"name": api.status}},
+				"changelog": map[string]interface{}{"histories": []interface{}{
+					map[string]interface{}{
+						"author": map[string]string{"accountId": "5b10ac8d82e05b22cc7d4ef5"},
+						"items":  []interface{}{map[string]string{"field": "status", "toString": api.status}},
+					},
+				}},
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+func newLinker(api *jiraAPI, atClient versioned.Interface, data map[string]string, impersonated *string) *linker {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: SecretName, Namespace: "foo"},
+		Data: map[string][]byte{
+			urlKey:     []byte(api.server.URL),
+			userKey:    []byte("bot@example.com"),
+			tokenKey:   []byte("s3cr3t"),
+			projectKey: []byte("OPS"),
+		},
+	}
+	for k, v := range data {
+		secret.Data[k] = []byte(v)
+	}
+	return &linker{
+		kubeClientSet: fakekube.NewSimpleClientset(secret, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: integrations.UserMappingConfigMap, Namespace: "manual-approval-gate"},
+			Data:       map[string]string{UserMappingKey: "5b10ac8d82e05b22cc7d4ef5: alice"},
+		}),
+		approvaltaskClientSet: atClient,
+		clientFor: func(username string, _ []string) (versioned.Interface, error) {
+			*impersonated = username
+			return atClient, nil
+		},
+		httpClient:      api.server.Client(),
+		systemNamespace: "manual-approval-gate",
+		logger:          zap.NewNop().Sugar(),
+	}
+}
+
+func TestReconcileApprovalTaskWithPolling(t *testing.T) {
+	api := newJiraAPI(t)
+	atClient := fake.NewSimpleClientset(pendingApprovalTask())
+	var impersonated string
+	l := newLinker(api, atClient, map[string]string{pollIntervalKey: "30s"}, &impersonated)
+
+	ctx := context.Background()
+	get := func() *v1alpha1.ApprovalTask {
+		at, err := atClient.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(ctx, "at-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return at
+	}
+
+	// The issue is opened and its key recorded.
+	assert.NoError(t, l.ReconcileApprovalTask(ctx, get()))
+	if !assert.Len(t, api.created, 1) {
+		t.FailNow()
+	}
+	fields := api.created[0]["fields"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"key": "OPS"}, fields["project"])
+	assert.Equal(t, map[string]interface{}{"name": "Task"}, fields["issuetype"])
+	assert.Equal(t, "OPS-1", get().Status.Annotations[IssueKeyAnnotation])
+
+	// The issue is polled until it is transitioned.
+	err := l.ReconcileApprovalTask(ctx, get())
+	requeue, after := controller.IsRequeueKey(err)
+	assert.True(t, requeue)
+	assert.Equal(t, 30*time.Second, after)
+	assert.Equal(t, "pending", get().Spec.Approvers[0].Input)
+
+	api.status = "Approved"
+	err = l.ReconcileApprovalTask(ctx, get())
+	requeue, _ = controller.IsRequeueKey(err)
+	assert.True(t, requeue)
+	at := get()
+	assert.Equal(t, "alice", impersonated)
+	assert.Equal(t, "approve", at.Spec.Approvers[0].Input)
+	assert.Equal(t, "Jira issue OPS-1 moved to Approved", at.Spec.Approvers[0].Message)
+	assert.Equal(t, "Approved/5b10ac8d82e05b22cc7d4ef5", at.Status.Annotations[DecidedAnnotation])
+
+	// The outcome is commented once.
+	at.Status.State = "approved"
+	assert.NoError(t, l.ReconcileApprovalTask(ctx, at.DeepCopy()))
+	assert.NoError(t, l.ReconcileApprovalTask(ctx, get()))
+	if assert.Len(t, api.comments, 1) {
+		assert.Contains(t, api.comments[0], "ApprovalTask foo/at-1 is approved")
+	}
+}
+
+func TestWebhookHandler(t *testing.T) {
+	payload := `{
+		"webhookEvent": "jira:issue_updated",
+		"user": {"accountId": "5b10ac8d82e05b22cc7d4ef5"},
+		"issue": {"key": "OPS-1", "fields": {"status": {"name": "rejected"}}}
+	}`
+	sign := func(key string) string {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(payload))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	tests := []struct {
+		name       string
+		signature  string
+		wantStatus int
+		wantInput  string
+	}{{
+		name:       "signed transition rejects as the mapped user",
+		signature:  sign(webhookSecret),
+		wantStatus: http.StatusOK,
+		wantInput:  "reject",
+	}, {
+		name:       "wrong signature",
+		signature:  sign("wrong"),
+		wantStatus: http.StatusUnauthorized,
+		wantInput:  "pending",
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			api := newJiraAPI(t)
+			at := pendingApprovalTask()
+			at.Status.Annotations = map[string]string{IssueKeyAnnotation: "OPS-1"}
+			atClient := fake.NewSimpleClientset(at)
+			var impersonated string
+			l := newLinker(api, atClient, map[string]string{webhookSecretKey: webhookSecret}, &impersonated)
+
+			mux := http.NewServeMux()
+			mux.Handle("POST /jira/{namespace}", &webhookHandler{linker: l})
+			req := httptest.NewRequest(http.MethodPost, "/jira/foo", strings.NewReader(payload))
+			req.Header.Set("X-Hub-Signature", tc.signature)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.wantStatus, rec.Code)
+			updated, err := atClient.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(context.Background(), "at-1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.wantInput, updated.Spec.Approvers[0].Input)
+			if tc.wantStatus == http.StatusOK {
+				assert.Equal(t, "alice", impersonated)
+			}
+		})
+	}
+}
+
+func TestLastStatusChange(t *testing.T) {
+	issue := &Issue{}
+	issue.Fields.Status.Name = "Approved"
+	issue.Changelog.Histories = []History{
+		{Author: User{Name: "bob"}, Items: []ChangeItem{{Field: "status", ToString: "Approved"}}},
+		{Author: User{Name: "carol"}, Items: []ChangeItem{{Field: "assignee", ToString: "dave"}}},
+	}
+	user, ok := issue.LastStatusChange()
+	assert.True(t, ok)
+	assert.Equal(t, "bob", user.Name)
+
+	issue.Fields.Status.Name = "Rejected"
+	_, ok = issue.LastStatusChange()
+	assert.False(t, ok)
+}
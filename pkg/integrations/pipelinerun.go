@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrations
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	clientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineRunLabelKey is set by Tekton on the CustomRun, and copied to the
+// ApprovalTask, with the name of the PipelineRun the approval belongs to.
+const PipelineRunLabelKey = "tekton.dev/pipelineRun"
+
+// GetPipelineRun returns the PipelineRun the ApprovalTask belongs to, or nil
+// when the ApprovalTask was not created by a PipelineRun or the PipelineRun
+// no longer exists.
+func GetPipelineRun(ctx context.Context, pipelineClientSet clientset.Interface, at *v1alpha1.ApprovalTask) (*pipelinev1.PipelineRun, error) {
+	name := at.Labels[PipelineRunLabelKey]
+	if name == "" {
+		return nil, nil
+	}
+	pr, err := pipelineClientSet.TektonV1().PipelineRuns(at.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	return pr, err
+}
+
+// PipelineRunMetadata merges the labels of the ApprovalTask, which Tekton
+// propagates from the PipelineRun, with the annotations of the PipelineRun.
+// Integrations use it to find e.g. the repository and commit of a run.
+func PipelineRunMetadata(ctx context.Context, pipelineClientSet clientset.Interface, at *v1alpha1.ApprovalTask) (map[string]string, error) {
+	metadata := make(map[string]string, len(at.Labels))
+	for k, v := range at.Labels {
+		metadata[k] = v
+	}
+
+	pr, err := GetPipelineRun(ctx, pipelineClientSet, at)
+	if err != nil || pr == nil {
+		return metadata, err
+	}
+	for k, v := range pr.Labels {
+		metadata[k] = v
+	}
+	for k, v := range pr.Annotations {
+		metadata[k] = v
+	}
+	return metadata, nil
+}
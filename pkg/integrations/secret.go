@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrations
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Config holds the string data of an integration Secret.
+type Config map[string]string
+
+// GetConfig reads the named Secret from namespace. A missing Secret is not an
+// error: it means the integration is not enabled for that namespace and nil
+// is returned.
+func GetConfig(ctx context.Context, kube kubernetes.Interface, namespace, name string) (Config, error) {
+	secret, err := kube.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	cfg := Config{}
+	for k, v := range secret.Data {
+		cfg[k] = strings.TrimSpace(string(v))
+	}
+	return cfg, nil
+}
+
+// Get returns the value of key, or def when it is unset.
+func (c Config) Get(key, def string) string {
+	if v, ok := c[key]; ok && v != "" {
+		return v
+	}
+	return def
+}
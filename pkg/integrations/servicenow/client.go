@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicenow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	changeRequestTable = "/api/now/table/change_request"
+	// readFields are the fields read from change requests
+	readFields = "sys_id,number,approval,state"
+)
+
+// Client is a minimal client of the ServiceNow Table API.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	user       string
+	password   string
+	token      string
+}
+
+// NewClient returns a Client for the instance at baseURL, authenticating with
+// basic auth when user is set and with token as an OAuth bearer token
+// otherwise.
+func NewClient(httpClient *http.Client, baseURL, user, password, token string) *Client {
+	return &Client{
+		httpClient: httpClient,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		user:       user,
+		password:   password,
+		token:      token,
+	}
+}
+
+// ChangeRequest is the part of a change_request record the gate uses. Values
+// are the raw, not the display, values.
+type ChangeRequest struct {
+	SysID  string `json:"sys_id"`
+	Number string `json:"number"`
+	// Approval is "not requested", "requested", "approved" or "rejected"
+	Approval string `json:"approval"`
+	// State is the numeric state, "4" being Canceled
+	State string `json:"state"`
+}
+
+// CreateChangeRequest creates a change request with fields and returns it.
+func (c *Client) CreateChangeRequest(ctx context.Context, fields map[string]string) (*ChangeRequest, error) {
+	var out struct {
+		Result ChangeRequest `json:"result"`
+	}
+	path := changeRequestTable + "?sysparm_fields=" + url.QueryEscape(readFields)
+	if err := c.do(ctx, http.MethodPost, path, fields, &out); err != nil {
+		return nil, err
+	}
+	return &out.Result, nil
+}
+
+// GetChangeRequest returns the change request number, or nil when there is
+// no such change request.
+func (c *Client) GetChangeRequest(ctx context.Context, number string) (*ChangeRequest, error) {
+	var out struct {
+		Result []ChangeRequest `json:"result"`
+	}
+	query := url.Values{
+		"sysparm_query":  {"number=" + number},
+		"sysparm_limit":  {"1"},
+		"sysparm_fields": {readFields},
+	}
+	if err := c.do(ctx, http.MethodGet, changeRequestTable+"?"+query.Encode(), nil, &out); err != nil {
+		return nil, err
+	}
+	if len(out.Result) == 0 {
+		return nil, nil
+	}
+	return &out.Result[0], nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		raw, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if c.user != "" {
+		req.SetBasicAuth(c.user, c.password)
+	} else if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: unexpected status %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package servicenow answers ChangeRequest approvers: it creates, or
+// validates, a change request through the ServiceNow Table API when the
+// ApprovalTask starts and polls it until it is approved or rejected.
+package servicenow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	approvaltaskclient "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/client"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations"
+	"k8s.io/client-go/kubernetes"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// SecretName is the Secret, in the namespace of the ApprovalTask, holding
+	// the ServiceNow instance and credentials.
+	SecretName = "manual-approval-gate-servicenow"
+
+	// ChangeRequestResult is the CustomRun result holding the number of the
+	// change request.
+	ChangeRequestResult = "changeRequest"
+	// ChangeRequestAnnotation records in status.annotations the number of the
+	// change request. It is published as the ChangeRequestResult of the
+	// CustomRun.
+	ChangeRequestAnnotation = v1alpha1.ResultAnnotationPrefix + ChangeRequestResult
+
+	// DefaultPollInterval is how often pending change requests are read.
+	DefaultPollInterval = time.Minute
+
+	urlKey          = "url"
+	userKey         = "user"
+	passwordKey     = "password"
+	tokenKey        = "token"
+	pollIntervalKey = "poll-interval"
+	fieldsKey       = "fields"
+
+	// canceledState is the raw value of the Canceled state of change requests
+	canceledState = "4"
+)
+
+// gate creates or validates the change request of the ChangeRequest approver
+// of a pending ApprovalTask and records its outcome as the response of that
+// approver.
+type gate struct {
+	kubeClientSet         kubernetes.Interface
+	approvaltaskClientSet versioned.Interface
+	httpClient            *http.Client
+}
+
+var _ integrations.Interface = (*gate)(nil)
+
+// NewController instantiates the ServiceNow change request gate.
+func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	return integrations.NewController(ctx, "ServiceNowGate", &gate{
+		kubeClientSet:         kubeclient.Get(ctx),
+		approvaltaskClientSet: approvaltaskclient.Get(ctx),
+		httpClient:            &http.Client{Timeout: 10 * time.Second},
+	})
+}
+
+// ReconcileApprovalTask implements integrations.Interface
+func (g *gate) ReconcileApprovalTask(ctx context.Context, at *v1alpha1.ApprovalTask) error {
+	logger := logging.FromContext(ctx)
+
+	if at.Status.State != "pending" {
+		return nil
+	}
+	approver, ok := changeRequestApprover(at)
+	if !ok {
+		return nil
+	}
+	for _, response := range at.ExternalResponses() {
+		if response.Type == v1alpha1.ChangeRequestApproverType && response.Name == approver.Name {
+			return nil
+		}
+	}
+
+	cfg, err := integrations.GetConfig(ctx, g.kubeClientSet, at.Namespace, SecretName)
+	if err != nil {
+		return err
+	}
+	if cfg.Get(urlKey, "") == "" {
+		logger.Warnf("ApprovalTask %s/%s has a %s approver but Secret %s/%s has no %s", at.Namespace, at.Name, v1alpha1.ChangeRequestApproverType, at.Namespace, SecretName, urlKey)
+		return nil
+	}
+	interval := DefaultPollInterval
+	if raw := cfg.Get(pollIntervalKey, ""); raw != "" {
+		if interval, err = time.ParseDuration(raw); err != nil {
+			return fmt.Errorf("invalid %s %q in Secret %s/%s: %w", pollIntervalKey, raw, at.Namespace, SecretName, err)
+		}
+	}
+	client := NewClient(g.httpClient, cfg.Get(urlKey, ""), cfg.Get(userKey, ""), cfg.Get(passwordKey, ""), cfg.Get(tokenKey, ""))
+
+	number := at.Status.Annotations[ChangeRequestAnnotation]
+	if number == "" && approver.Name == v1alpha1.NewChangeRequest {
+		fields, err := changeRequestFields(cfg, at)
+		if err != nil {
+			return fmt.Errorf("invalid %s in Secret %s/%s: %w", fieldsKey, at.Namespace, SecretName, err)
+		}
+		cr, err := client.CreateChangeRequest(ctx, fields)
+		if err != nil {
+			return fmt.Errorf("failed to create change request for ApprovalTask %s/%s: %w", at.Namespace, at.Name, err)
+		}
+		logger.Infof("Created change request %s for ApprovalTask %s/%s", cr.Number, at.Namespace, at.Name)
+		return integrations.SetStatusAnnotations(ctx, g.approvaltaskClientSet, at, map[string]string{
+			ChangeRequestAnnotation: cr.Number,
+		})
+	}
+	if number == "" {
+		number = approver.Name
+	}
+
+	cr, err := client.GetChangeRequest(ctx, number)
+	if err != nil {
+		return fmt.Errorf("failed to get change request %s for ApprovalTask %s/%s: %w", number, at.Namespace, at.Name, err)
+	}
+	annotations := map[string]string{ChangeRequestAnnotation: number}
+	if cr == nil {
+		logger.Infof("Change request %s of ApprovalTask %s/%s does not exist", number, at.Namespace, at.Name)
+		return integrations.SetExternalResponse(ctx, g.approvaltaskClientSet, at, v1alpha1.ApproverState{
+			Name:     approver.Name,
+			Type:     v1alpha1.ChangeRequestApproverType,
+			Response: "rejected",
+			Message:  fmt.Sprintf("Change request %s does not exist", number),
+		}, annotations)
+	}
+
+	if response, message := outcome(cr); response != "" {
+		logger.Infof("Change request %s of ApprovalTask %s/%s is %s", number, at.Namespace, at.Name, response)
+		return integrations.SetExternalResponse(ctx, g.approvaltaskClientSet, at, v1alpha1.ApproverState{
+			Name:     approver.Name,
+			Type:     v1alpha1.ChangeRequestApproverType,
+			Response: response,
+			Message:  message,
+		}, annotations)
+	}
+
+	if at.Status.Annotations[ChangeRequestAnnotation] == "" {
+		if err := integrations.SetStatusAnnotations(ctx, g.approvaltaskClientSet, at, annotations); err != nil {
+			return err
+		}
+	}
+	return controller.NewRequeueAfter(interval)
+}
+
+// changeRequestApprover returns the ChangeRequest approver of the ApprovalTask.
+func changeRequestApprover(at *v1alpha1.ApprovalTask) (v1alpha1.ApproverDetails, bool) {
+	for _, approver := range at.Spec.Approvers {
+		if approver.Type == v1alpha1.ChangeRequestApproverType {
+			return approver, true
+		}
+	}
+	return v1alpha1.ApproverDetails{}, false
+}
+
+// outcome maps the change request to the response of its approver, or to ""
+// while it is still waiting for approval.
+func outcome(cr *ChangeRequest) (response, message string) {
+	switch {
+	case cr.Approval == "approved":
+		return "approved", fmt.Sprintf("Change request %s was approved", cr.Number)
+	case cr.Approval == "rejected":
+		return "rejected", fmt.Sprintf("Change request %s was rejected", cr.Number)
+	case cr.State == canceledState:
+		return "rejected", fmt.Sprintf("Change request %s was canceled", cr.Number)
+	}
+	return "", ""
+}
+
+// changeRequestFields returns the fields of a new change request: a short
+// description and description of the ApprovalTask, overridden by the YAML
+// map of the fields key of the Secret.
+func changeRequestFields(cfg integrations.Config, at *v1alpha1.ApprovalTask) (map[string]string, error) {
+	var description strings.Builder
+	if at.Spec.Description != "" {
+		fmt.Fprintf(&description, "%s\n\n", at.Spec.Description)
+	}
+	fmt.Fprintf(&description, "ApprovalTask %s in namespace %s", at.Name, at.Namespace)
+	if pr := at.Labels[integrations.PipelineRunLabelKey]; pr != "" {
+		fmt.Fprintf(&description, " of PipelineRun %s", pr)
+	}
+	description.WriteString(".")
+
+	fields := map[string]string{
+		"short_description": fmt.Sprintf("Approval required: %s/%s", at.Namespace, at.Name),
+		"description":       description.String(),
+	}
+	if raw := cfg.Get(fieldsKey, ""); raw != "" {
+		extra := map[string]string{}
+		if err := yaml.Unmarshal([]byte(raw), &extra); err != nil {
+			return nil, err
+		}
+		for k, v := range extra {
+			fields[k] = v
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package servicenow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/controller"
+)
+
+func changeRequestApprovalTask(number string) *v1alpha1.ApprovalTask {
+	return &v1alpha1.ApprovalTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "at-1", Namespace: "foo"},
+		Spec: v1alpha1.ApprovalTaskSpec{
+			Approvers: []v1alpha1.ApproverDetails{
+				{Name: "alice", Input: "pending", Type: "User"},
+				{Name: number, Input: "pending", Type: v1alpha1.ChangeRequestApproverType},
+			},
+			NumberOfApprovalsRequired: 2,
+		},
+		Status: v1alpha1.ApprovalTaskStatus{State: "pending", ApprovalsRequired: 2},
+	}
+}
+
+// instance is a fake ServiceNow instance holding change requests by number.
+type instance struct {
+	server  *httptest.Server
+	records map[string]*ChangeRequest
+	created []map[string]string
+}
+
+func newInstance(t *testing.T) *instance {
+	i := &instance{records: map[string]*ChangeRequest{}}
+	i.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		assert.Equal(t, "pipelines", user)
+		assert.Equal(t, "s3cr3t", password)
+		assert.Equal(t, "/api/now/table/change_request", r.URL.Path)
+
+		switch r.Method {
+		case http.MethodPost:
+			var fields map[string]string
+			json.NewDecoder(r.Body).Decode(&fields)
+			i.created = append(i.created, fields)
+			cr := &ChangeRequest{SysID: "abc", Number: "CHG0000042", Approval: "requested", State: "-4"}
+			i.records[cr.Number] = cr
+			json.NewEncoder(w).Encode(map[string]interface{}{"result": cr})
+		case http.MethodGet:
+			result := []*ChangeRequest{}
+			for number, cr := range i.records {
+				if r.URL.Query().Get("sysparm_query") == "number="+number {
+					result = append(result, cr)
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+		}
+	}))
+	t.Cleanup(i.server.Close)
+	return i
+}
+
+func newGate(i *instance, at *v1alpha1.ApprovalTask) (*gate, *fake.Clientset) {
+	atClient := fake.NewSimpleClientset(at)
+	return &gate{
+		kubeClientSet: fakekube.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: SecretName, Namespace: "foo"},
+			Data: map[string][]byte{
+				urlKey:          []byte(i.server.URL),
+				userKey:         []byte("pipelines"),
+				passwordKey:     []byte("s3cr3t"),
+				pollIntervalKey: []byte("10s"),
+				fieldsKey:       []byte("assignment_group: release-managers\ntype: normal"),
+			},
+		}),
+		approvaltaskClientSet: atClient,
+		httpClient:            i.server.Client(),
+	}, atClient
+}
+
+func TestReconcileCreatesAndPollsChangeRequest(t *testing.T) {
+	i := newInstance(t)
+	g, atClient := newGate(i, changeRequestApprovalTask(v1alpha1.NewChangeRequest))
+
+	ctx := context.Background()
+	get := func() *v1alpha1.ApprovalTask {
+		at, err := atClient.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(ctx, "at-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return at
+	}
+
+	assert.NoError(t, g.ReconcileApprovalTask(ctx, get()))
+	if !assert.Len(t, i.created, 1) {
+		t.FailNow()
+	}
+	assert.Equal(t, "Approval required: foo/at-1", i.created[0]["short_description"])
+	assert.Equal(t, "release-managers", i.created[0]["assignment_group"])
+	assert.Equal(t, "CHG0000042", get().Status.Annotations[ChangeRequestAnnotation])
+
+	// Waiting for approval, the change request is polled.
+	requeue, _ := controller.IsRequeueKey(g.ReconcileApprovalTask(ctx, get()))
+	assert.True(t, requeue)
+	assert.Empty(t, get().Status.ApproversResponse)
+
+	i.records["CHG0000042"].Approval = "approved"
+	assert.NoError(t, g.ReconcileApprovalTask(ctx, get()))
+	assert.Equal(t, []v1alpha1.ApproverState{{
+		Name:     v1alpha1.NewChangeRequest,
+		Type:     v1alpha1.ChangeRequestApproverType,
+		Response: "approved",
+		Message:  "Change request CHG0000042 was approved",
+	}}, get().Status.ApproversResponse)
+	assert.Len(t, i.created, 1)
+}
+
+func TestReconcileValidatesChangeRequest(t *testing.T) {
+	tests := []struct {
+		name         string
+		record       *ChangeRequest
+		wantResponse string
+		wantMessage  string
+	}{{
+		name:         "missing change request",
+		wantResponse: "rejected",
+		wantMessage:  "Change request CHG0030001 does not exist",
+	}, {
+		name:         "canceled change request",
+		record:       &ChangeRequest{Number: "CHG0030001", Approval: "requested", State: canceledState},
+		wantResponse: "rejected",
+		wantMessage:  "Change request CHG0030001 was canceled",
+	}, {
+		name:         "approved change request",
+		record:       &ChangeRequest{Number: "CHG0030001", Approval: "approved"},
+		wantResponse: "approved",
+		wantMessage:  "Change request CHG0030001 was approved",
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			i := newInstance(t)
+			if tc.record != nil {
+				i.records[tc.record.Number] = tc.record
+			}
+			g, atClient := newGate(i, changeRequestApprovalTask("CHG0030001"))
+
+			ctx := context.Background()
+			at, err := atClient.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(ctx, "at-1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.NoError(t, g.ReconcileApprovalTask(ctx, at))
+
+			at, err = atClient.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(ctx, "at-1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Empty(t, i.created)
+			assert.Equal(t, "CHG0030001", at.Status.Annotations[ChangeRequestAnnotation])
+			if assert.Len(t, at.Status.ApproversResponse, 1) {
+				assert.Equal(t, tc.wantResponse, at.Status.ApproversResponse[0].Response)
+				assert.Equal(t, tc.wantMessage, at.Status.ApproversResponse[0].Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrations
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IsFinal reports whether the ApprovalTask has been approved or rejected.
+func IsFinal(at *v1alpha1.ApprovalTask) bool {
+	return at.Status.State == "approved" || at.Status.State == "rejected"
+}
+
+// SetStatusAnnotations records bookkeeping for an integration, such as the
+// id of a message or ticket, in status.annotations and persists it. Status
+// is used rather than metadata because the admission webhook only lets
+// approvers update the ApprovalTask itself.
+func SetStatusAnnotations(ctx context.Context, client versioned.Interface, at *v1alpha1.ApprovalTask, annotations map[string]string) error {
+	if at.Status.Annotations == nil {
+		at.Status.Annotations = make(map[string]string, len(annotations))
+	}
+	for k, v := range annotations {
+		at.Status.Annotations[k] = v
+	}
+	_, err := client.OpenshiftpipelinesV1alpha1().ApprovalTasks(at.Namespace).UpdateStatus(ctx, at, metav1.UpdateOptions{})
+	return err
+}
+
+// SetExternalResponse records the response of an external approver, such as
+// a change request, in status.approversResponse along with annotations. The
+// approvaltask reconciler then counts it like the response of a user.
+func SetExternalResponse(ctx context.Context, client versioned.Interface, at *v1alpha1.ApprovalTask, response v1alpha1.ApproverState, annotations map[string]string) error {
+	responses := make([]v1alpha1.ApproverState, 0, len(at.Status.ApproversResponse)+1)
+	for _, r := range at.Status.ApproversResponse {
+		if r.Type != response.Type || r.Name != response.Name {
+			responses = append(responses, r)
+		}
+	}
+	at.Status.ApproversResponse = append(responses, response)
+	return SetStatusAnnotations(ctx, client, at, annotations)
+}
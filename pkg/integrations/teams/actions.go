@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package teams
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations"
+	"k8s.io/client-go/kubernetes"
+)
+
+// getActionKey returns the key of the ActionKeySecret Secret of
+// systemNamespace, which signs the actions of the cards.
+func getActionKey(ctx context.Context, kube kubernetes.Interface, systemNamespace string) ([]byte, error) {
+	cfg, err := integrations.GetConfig(ctx, kube, systemNamespace, ActionKeySecret)
+	if err != nil {
+		return nil, err
+	}
+	key := cfg.Get(ActionKeySecretKey, "")
+	if key == "" {
+		return nil, fmt.Errorf("secret %s/%s has no %q", systemNamespace, ActionKeySecret, ActionKeySecretKey)
+	}
+	return []byte(key), nil
+}
+
+// signAction returns the signature of the action of a card answering the
+// ApprovalTask namespace/name with input.
+func signAction(key []byte, namespace, name, input string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(namespace + "/" + name + "/" + input))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyAction tells whether signature is the one of the action answering
+// the ApprovalTask namespace/name with input.
+func verifyAction(key []byte, namespace, name, input, signature string) bool {
+	return hmac.Equal([]byte(signAction(key, namespace, name, input)), []byte(signature))
+}
+
+// usedTokens remembers the action tokens used until they expire, so that a
+// token answers a single action. It is kept in memory: a token can still be
+// used once on each replica of the controller.
+type usedTokens struct {
+	mu sync.Mutex
+	// expiresAt is when the tokens, by hash, expire
+	expiresAt map[[sha256.Size]byte]int64
+}
+
+func newUsedTokens() *usedTokens {
+	return &usedTokens{expiresAt: map[[sha256.Size]byte]int64{}}
+}
+
+// use records token, which expires at expiresAt, and tells whether it was
+// not used before.
+func (u *usedTokens) use(token string, expiresAt int64, now time.Time) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for hash, expiry := range u.expiresAt {
+		if expiry <= now.Unix() {
+			delete(u.expiresAt, hash)
+		}
+	}
+	hash := sha256.Sum256([]byte(token))
+	if _, used := u.expiresAt[hash]; used {
+		return false
+	}
+	u.expiresAt[hash] = expiresAt
+	return true
+}
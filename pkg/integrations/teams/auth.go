@@ -0,0 +1,207 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package teams
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultJWKSURL serves the keys Microsoft signs action tokens with.
+	DefaultJWKSURL = "https://substrate.office.com/sts/common/discovery/keys"
+	// DefaultTokenIssuer is the issuer of action tokens.
+	DefaultTokenIssuer = "https://substrate.office.com/sts/"
+
+	jwksRefreshInterval = 24 * time.Hour
+)
+
+// claims are the parts of the action token we rely on.
+type claims struct {
+	Issuer    string `json:"iss"`
+	Audience  string `json:"aud"`
+	Subject   string `json:"sub"`
+	ExpiresAt int64  `json:"exp"`
+	NotBefore int64  `json:"nbf"`
+}
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+type jwks struct {
+	Keys []struct {
+		KeyID string `json:"kid"`
+		Type  string `json:"kty"`
+		N     string `json:"n"`
+		E     string `json:"e"`
+	} `json:"keys"`
+}
+
+// tokenVerifier verifies the RS256 bearer tokens Microsoft attaches to
+// Action.Http requests, caching the signing keys of each JWKS URL.
+type tokenVerifier struct {
+	client *http.Client
+	now    func() time.Time
+
+	mu      sync.Mutex
+	keys    map[string]map[string]*rsa.PublicKey
+	fetched map[string]time.Time
+}
+
+func newTokenVerifier(client *http.Client) *tokenVerifier {
+	return &tokenVerifier{
+		client:  client,
+		now:     time.Now,
+		keys:    map[string]map[string]*rsa.PublicKey{},
+		fetched: map[string]time.Time{},
+	}
+}
+
+// verify checks the signature, issuer, audience and validity period of token
+// and returns its claims.
+func (v *tokenVerifier) verify(ctx context.Context, token, jwksURL, issuer, audience string) (*claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	var h jwtHeader
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("malformed token header: %w", err)
+	}
+	if h.Algorithm != "RS256" {
+		return nil, fmt.Errorf("unsupported token algorithm %q", h.Algorithm)
+	}
+
+	key, err := v.key(ctx, jwksURL, h.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %w", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+
+	var c claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %w", err)
+	}
+
+	now := v.now().Unix()
+	switch {
+	case c.Issuer != issuer:
+		return nil, fmt.Errorf("unexpected token issuer %q", c.Issuer)
+	case strings.TrimSuffix(c.Audience, "/") != strings.TrimSuffix(audience, "/"):
+		return nil, fmt.Errorf("unexpected token audience %q", c.Audience)
+	case c.ExpiresAt == 0 || now >= c.ExpiresAt:
+		return nil, fmt.Errorf("token has expired")
+	case c.NotBefore != 0 && now < c.NotBefore:
+		return nil, fmt.Errorf("token is not valid yet")
+	case c.Subject == "":
+		return nil, fmt.Errorf("token has no subject")
+	}
+	return &c, nil
+}
+
+// key returns the signing key kid from jwksURL, refreshing the cached key set
+// when it is stale or does not contain kid.
+func (v *tokenVerifier) key(ctx context.Context, jwksURL, kid string) (*rsa.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if key, ok := v.keys[jwksURL][kid]; ok && v.now().Sub(v.fetched[jwksURL]) < jwksRefreshInterval {
+		return key, nil
+	}
+
+	keys, err := v.fetch(ctx, jwksURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch token signing keys: %w", err)
+	}
+	v.keys[jwksURL] = keys
+	v.fetched[jwksURL] = v.now()
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown token signing key %q", kid)
+	}
+	return key, nil
+}
+
+func (v *tokenVerifier) fetch(ctx context.Context, jwksURL string) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var set jwks
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Type != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		keys[k.KeyID] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package teams
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+)
+
+const (
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardVersion     = "1.4"
+
+	// messageInputID is the id of the text input whose value is sent as the
+	// approval message by the card actions.
+	messageInputID = "message"
+)
+
+// message is the payload accepted by Teams incoming webhooks.
+type message struct {
+	Type        string       `json:"type"`
+	Attachments []attachment `json:"attachments"`
+}
+
+type attachment struct {
+	ContentType string `json:"contentType"`
+	Content     card   `json:"content"`
+}
+
+type card struct {
+	Schema  string        `json:"$schema"`
+	Type    string        `json:"type"`
+	Version string        `json:"version"`
+	Body    []interface{} `json:"body"`
+	Actions []action      `json:"actions,omitempty"`
+}
+
+type textBlock struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Size   string `json:"size,omitempty"`
+	Weight string `json:"weight,omitempty"`
+	Wrap   bool   `json:"wrap,omitempty"`
+}
+
+type factSet struct {
+	Type  string `json:"type"`
+	Facts []fact `json:"facts"`
+}
+
+type fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type inputText struct {
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	Placeholder string `json:"placeholder,omitempty"`
+	IsMultiline bool   `json:"isMultiline,omitempty"`
+}
+
+type action struct {
+	Type    string   `json:"type"`
+	Title   string   `json:"title"`
+	Method  string   `json:"method"`
+	URL     string   `json:"url"`
+	Body    string   `json:"body"`
+	Headers []header `json:"headers,omitempty"`
+}
+
+type header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// newMessage builds the adaptive card for the ApprovalTask. When callbackURL
+// is set and the task is still pending the card carries Approve and Reject
+// actions which call back into the controller, signed with key.
+func newMessage(at *v1alpha1.ApprovalTask, callbackURL string, key []byte) message {
+	c := card{
+		Schema:  adaptiveCardSchema,
+		Type:    "AdaptiveCard",
+		Version: adaptiveCardVersion,
+		Body: []interface{}{
+			textBlock{
+				Type:   "TextBlock",
+				Text:   title(at),
+				Size:   "Medium",
+				Weight: "Bolder",
+				Wrap:   true,
+			},
+		},
+	}
+
+	if at.Spec.Description != "" {
+		c.Body = append(c.Body, textBlock{Type: "TextBlock", Text: at.Spec.Description, Wrap: true})
+	}
+	c.Body = append(c.Body, factSet{Type: "FactSet", Facts: facts(at)})
+
+	if callbackURL != "" && at.Status.State == "pending" {
+		c.Body = append(c.Body, inputText{
+			Type:        "Input.Text",
+			ID:          messageInputID,
+			Placeholder: "Message (optional)",
+			IsMultiline: true,
+		})
+		url := fmt.Sprintf("%s/teams/%s/%s", strings.TrimSuffix(callbackURL, "/"), at.Namespace, at.Name)
+		c.Actions = []action{
+			newAction("Approve", url, "approve", signAction(key, at.Namespace, at.Name, "approve")),
+			newAction("Reject", url, "reject", signAction(key, at.Namespace, at.Name, "reject")),
+		}
+	}
+
+	return message{
+		Type: "message",
+		Attachments: []attachment{{
+			ContentType: adaptiveCardContentType,
+			Content:     c,
+		}},
+	}
+}
+
+func newAction(title, url, input, signature string) action {
+	return action{
+		Type:   "Action.Http",
+		Title:  title,
+		Method: "POST",
+		URL:    url,
+		Body:   fmt.Sprintf(`{"input":%q,"message":"{{%s.value}}","action":%q}`, input, messageInputID, signature),
+		Headers: []header{{
+			Name:  "Content-Type",
+			Value: "application/json",
+		}},
+	}
+}
+
+func title(at *v1alpha1.ApprovalTask) string {
+	switch at.Status.State {
+	case "approved":
+		return fmt.Sprintf("✅ ApprovalTask %s/%s is approved", at.Namespace, at.Name)
+	case "rejected":
+		return fmt.Sprintf("❌ ApprovalTask %s/%s is rejected", at.Namespace, at.Name)
+	default:
+		return fmt.Sprintf("⏳ ApprovalTask %s/%s is waiting for approval", at.Namespace, at.Name)
+	}
+}
+
+func facts(at *v1alpha1.ApprovalTask) []fact {
+	var approvers []string
+	for _, approver := range at.Spec.Approvers {
+		if v1alpha1.IsGroupApproverType(approver.Type) {
+			approvers = append(approvers, approver.Name+" ("+approver.Type+")")
+			continue
+		}
+		approvers = append(approvers, approver.Name)
+	}
+
+	approvalsRequired := at.Status.ApprovalsRequired
+	if approvalsRequired == 0 {
+		approvalsRequired = at.Spec.NumberOfApprovalsRequired
+	}
+
+	f := []fact{
+		{Title: "Approvers", Value: strings.Join(approvers, ", ")},
+		{Title: "Approvals", Value: fmt.Sprintf("%d of %d", at.Status.ApprovalsReceived, approvalsRequired)},
+		{Title: "State", Value: at.Status.State},
+	}
+	if pipelineRun := at.Labels["tekton.dev/pipelineRun"]; pipelineRun != "" {
+		f = append(f, fact{Title: "PipelineRun", Value: pipelineRun})
+	}
+	for _, response := range at.Status.ApproversResponse {
+		value := response.Response
+		if response.Message != "" {
+			value = fmt.Sprintf("%s: %s", value, response.Message)
+		}
+		f = append(f, fact{Title: response.Name, Value: value})
+	}
+	return f
+}
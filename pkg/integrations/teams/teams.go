@@ -0,0 +1,333 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package teams posts ApprovalTasks to Microsoft Teams channels as adaptive
+// cards and lets approvers answer them from the card.
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	approvaltaskclient "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/client"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// SecretName is the Secret, in the namespace of the ApprovalTask, which
+	// enables the Teams integration for that namespace.
+	SecretName = "manual-approval-gate-teams"
+
+	// NotifiedAnnotation records in status.annotations the state of the
+	// ApprovalTask the last card was posted for.
+	NotifiedAnnotation = "teams.openshift-pipelines.org/notified"
+
+	// JWKSURLEnv and TokenIssuerEnv, set on the controller, override the
+	// JWKS URL and the issuer action tokens are verified with. They are not
+	// read from the Secret, which the tenants can change.
+	JWKSURLEnv     = "TEAMS_JWKS_URL"
+	TokenIssuerEnv = "TEAMS_TOKEN_ISSUER"
+
+	// CallbackURLEnv, set on the controller, is the public URL of the
+	// callbacks Service the card actions call, whose origin is the audience
+	// of the action tokens. The cards have no actions when it is empty.
+	CallbackURLEnv = "TEAMS_CALLBACK_URL"
+
+	// ActionKeySecret is the Secret, in the namespace of the controller,
+	// whose ActionKeySecretKey signs the actions of the cards, so that an
+	// action token only answers the ApprovalTask and with the input of the
+	// button it was issued for.
+	ActionKeySecret    = "manual-approval-gate-teams-actions"
+	ActionKeySecretKey = "key"
+
+	// UserMappingKey is the key of the mapping of the Teams users, by the
+	// subject of their action token, in integrations.UserMappingConfigMap.
+	UserMappingKey = "teams"
+
+	webhookURLKey = "webhook-url"
+	actionsKey    = "actions"
+)
+
+// notifier posts a card when an ApprovalTask becomes pending and another one
+// once it is approved or rejected.
+type notifier struct {
+	kubeClientSet         kubernetes.Interface
+	approvaltaskClientSet versioned.Interface
+	httpClient            *http.Client
+	// callbackURL is the URL the card actions call, empty without actions
+	callbackURL string
+	// systemNamespace holds the ActionKeySecret
+	systemNamespace string
+}
+
+var _ integrations.Interface = (*notifier)(nil)
+
+// NewController instantiates the Teams notifier and registers the endpoint
+// the Approve and Reject card actions call.
+func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	kubeclientset := kubeclient.Get(ctx)
+
+	callbackURL := os.Getenv(CallbackURLEnv)
+
+	n := &notifier{
+		kubeClientSet:         kubeclientset,
+		approvaltaskClientSet: approvaltaskclient.Get(ctx),
+		httpClient:            httpClient,
+		callbackURL:           callbackURL,
+		systemNamespace:       system.Namespace(),
+	}
+
+	integrations.HandleCallback("POST /teams/{namespace}/{name}", &callbackHandler{
+		kubeClientSet:   kubeclientset,
+		clientFor:       integrations.ImpersonatingClientFactory(injection.GetConfig(ctx)),
+		verifier:        newTokenVerifier(httpClient),
+		jwksURL:         getEnvOrDefault(JWKSURLEnv, DefaultJWKSURL),
+		issuer:          getEnvOrDefault(TokenIssuerEnv, DefaultTokenIssuer),
+		callbackURL:     callbackURL,
+		usedTokens:      newUsedTokens(),
+		systemNamespace: system.Namespace(),
+		logger:          logging.FromContext(ctx).Named("teams-callback"),
+	})
+
+	return integrations.NewController(ctx, "TeamsNotifier", n)
+}
+
+// ReconcileApprovalTask implements integrations.Interface
+func (n *notifier) ReconcileApprovalTask(ctx context.Context, at *v1alpha1.ApprovalTask) error {
+	logger := logging.FromContext(ctx)
+
+	if at.Status.State == "" || at.Status.Annotations[NotifiedAnnotation] == at.Status.State {
+		return nil
+	}
+
+	cfg, err := integrations.GetConfig(ctx, n.kubeClientSet, at.Namespace, SecretName)
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		return nil
+	}
+	webhookURL := cfg.Get(webhookURLKey, "")
+	if webhookURL == "" {
+		logger.Warnf("Secret %s/%s has no %s, not notifying Teams", at.Namespace, SecretName, webhookURLKey)
+		return nil
+	}
+
+	var callbackURL string
+	var key []byte
+	if n.callbackURL != "" && cfg.Get(actionsKey, "") == "true" && at.Status.State == "pending" {
+		if key, err = getActionKey(ctx, n.kubeClientSet, n.systemNamespace); err != nil {
+			logger.Warnf("Posting ApprovalTask %s/%s to Teams without actions: %v", at.Namespace, at.Name, err)
+		} else {
+			callbackURL = n.callbackURL
+		}
+	}
+	if err := n.post(ctx, webhookURL, newMessage(at, callbackURL, key)); err != nil {
+		return fmt.Errorf("failed to post ApprovalTask %s/%s to Teams: %w", at.Namespace, at.Name, err)
+	}
+	logger.Infof("Posted %s ApprovalTask %s/%s to Teams", at.Status.State, at.Namespace, at.Name)
+
+	return integrations.SetStatusAnnotations(ctx, n.approvaltaskClientSet, at, map[string]string{
+		NotifiedAnnotation: at.Status.State,
+	})
+}
+
+func (n *notifier) post(ctx context.Context, webhookURL string, msg message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// callbackHandler serves the Action.Http requests sent by the card buttons.
+type callbackHandler struct {
+	kubeClientSet kubernetes.Interface
+	clientFor     integrations.ClientFactory
+	verifier      *tokenVerifier
+	// jwksURL and issuer verify the action tokens, whose audience is the
+	// origin of callbackURL
+	jwksURL     string
+	issuer      string
+	callbackURL string
+	// usedTokens rejects the action tokens used already
+	usedTokens *usedTokens
+	// systemNamespace holds the integrations.UserMappingConfigMap and the
+	// ActionKeySecret
+	systemNamespace string
+	logger          *zap.SugaredLogger
+}
+
+type callbackRequest struct {
+	Input   string `json:"input"`
+	Message string `json:"message"`
+	// Action is the signature of the namespace, name and input of the
+	// button, see signAction
+	Action string `json:"action"`
+}
+
+// ServeHTTP authenticates the user who pressed the button from the token
+// Teams attaches to the request, checks that the action is the one of a card
+// of the ApprovalTask, and records their decision as that user.
+func (h *callbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	namespace, name := r.PathValue("namespace"), r.PathValue("name")
+
+	cfg, err := integrations.GetConfig(ctx, h.kubeClientSet, namespace, SecretName)
+	if err != nil {
+		h.respond(w, http.StatusInternalServerError, "Failed to read Teams configuration")
+		return
+	}
+	if h.callbackURL == "" || cfg == nil || cfg.Get(actionsKey, "") != "true" {
+		h.respond(w, http.StatusNotFound, "Teams actions are not enabled for this namespace")
+		return
+	}
+
+	audience, err := origin(h.callbackURL)
+	if err != nil {
+		h.respond(w, http.StatusInternalServerError, "Invalid Teams callback URL")
+		return
+	}
+	token := bearerToken(r)
+	if token == "" {
+		h.respond(w, http.StatusUnauthorized, "Missing action token")
+		return
+	}
+	c, err := h.verifier.verify(ctx, token, h.jwksURL, h.issuer, audience)
+	if err != nil {
+		h.logger.Warnf("Rejected Teams action for ApprovalTask %s/%s: %v", namespace, name, err)
+		h.respond(w, http.StatusUnauthorized, "Invalid action token")
+		return
+	}
+	if !h.usedTokens.use(token, c.ExpiresAt, h.verifier.now()) {
+		h.logger.Warnf("Rejected replayed Teams action for ApprovalTask %s/%s", namespace, name)
+		h.respond(w, http.StatusUnauthorized, "Action token was used already")
+		return
+	}
+	mapping, err := integrations.LoadUserMapping(ctx, h.kubeClientSet, h.systemNamespace, UserMappingKey)
+	if err != nil {
+		h.logger.Errorf("Failed to read the Teams user mapping: %v", err)
+		h.respond(w, http.StatusInternalServerError, "Invalid Teams user mapping")
+		return
+	}
+	username, err := mapping.Username(c.Subject)
+	if err != nil {
+		h.logger.Warnf("Rejected Teams action for ApprovalTask %s/%s: %v", namespace, name, err)
+		h.respond(w, http.StatusForbidden, "Your Teams user is not mapped to a Kubernetes user")
+		return
+	}
+
+	var body callbackRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&body); err != nil {
+		h.respond(w, http.StatusBadRequest, "Malformed action body")
+		return
+	}
+	key, err := getActionKey(ctx, h.kubeClientSet, h.systemNamespace)
+	if err != nil {
+		h.logger.Errorf("Failed to read the key of the Teams actions: %v", err)
+		h.respond(w, http.StatusInternalServerError, "Invalid Teams configuration")
+		return
+	}
+	if !verifyAction(key, namespace, name, body.Input, body.Action) {
+		h.logger.Warnf("Rejected Teams action of %s for ApprovalTask %s/%s: the action is not one of its cards", username, namespace, name)
+		h.respond(w, http.StatusForbidden, "The action is not one of this ApprovalTask")
+		return
+	}
+
+	err = integrations.Apply(ctx, h.clientFor, integrations.Decision{
+		Namespace: namespace,
+		Name:      name,
+		Username:  username,
+		Input:     body.Input,
+		Message:   body.Message,
+	})
+	if err != nil {
+		h.logger.Warnf("Teams user %s could not %s ApprovalTask %s/%s: %v", username, body.Input, namespace, name, err)
+		h.respond(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	h.logger.Infof("Teams user %s sent %s for ApprovalTask %s/%s", username, body.Input, namespace, name)
+	h.respond(w, http.StatusOK, fmt.Sprintf("Your %s for ApprovalTask %s has been recorded", body.Input, name))
+}
+
+// respond reports the outcome to the user: Teams shows the
+// CARD-ACTION-STATUS header below the card.
+func (h *callbackHandler) respond(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("CARD-ACTION-STATUS", msg)
+	w.WriteHeader(status)
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func bearerToken(r *http.Request) string {
+	for _, name := range []string{"Authorization", "Action-Authorization"} {
+		if v := r.Header.Get(name); strings.HasPrefix(v, "Bearer ") {
+			return strings.TrimPrefix(v, "Bearer ")
+		}
+	}
+	return ""
+}
+
+// origin returns the scheme and host of rawURL, which is the audience of the
+// action tokens issued for it.
+func origin(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("%q is not an absolute URL", rawURL)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
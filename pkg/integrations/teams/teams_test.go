@@ -0,0 +1,422 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package teams
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/fake"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/integrations"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+)
+
+const callbackURL = "https://approvals.example.com"
+
+var actionKey = []byte("0123456789abcdef")
+
+func actionKeySecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: ActionKeySecret, Namespace: "manual-approval-gate"},
+		Data:       map[string][]byte{ActionKeySecretKey: actionKey},
+	}
+}
+
+// actionBody is the body of the action of a card answering the ApprovalTask
+// namespace/name with input.
+func actionBody(namespace, name, input string) string {
+	return fmt.Sprintf(`{"input":%q,"message":"ship it","action":%q}`, input, signAction(actionKey, namespace, name, input))
+}
+
+func pendingApprovalTask() *v1alpha1.ApprovalTask {
+	return &v1alpha1.ApprovalTask{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "at-1",
+			Namespace: "foo",
+			Labels:    map[string]string{"tekton.dev/pipelineRun": "deploy-run"},
+		},
+		Spec: v1alpha1.ApprovalTaskSpec{
+			Approvers: []v1alpha1.ApproverDetails{
+				{Name: "alice", Input: "pending", Type: "User"},
+				{Name: "dev-team", Input: "pending", Type: "Group"},
+			},
+			NumberOfApprovalsRequired: 2,
+			Description:               "Deploy to production",
+		},
+		Status: v1alpha1.ApprovalTaskStatus{
+			State:             "pending",
+			ApprovalsRequired: 2,
+			ApprovalsReceived: 1,
+		},
+	}
+}
+
+func teamsSecret(data map[string]string) *corev1.Secret {
+	s := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: SecretName, Namespace: "foo"},
+		Data:       map[string][]byte{},
+	}
+	for k, v := range data {
+		s.Data[k] = []byte(v)
+	}
+	return s
+}
+
+func userMapping(data string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: integrations.UserMappingConfigMap, Namespace: "manual-approval-gate"},
+		Data:       map[string]string{UserMappingKey: data},
+	}
+}
+
+func TestNewMessage(t *testing.T) {
+	msg := newMessage(pendingApprovalTask(), callbackURL, actionKey)
+
+	if !assert.Len(t, msg.Attachments, 1) {
+		t.FailNow()
+	}
+	c := msg.Attachments[0].Content
+	assert.Equal(t, adaptiveCardContentType, msg.Attachments[0].ContentType)
+	assert.Equal(t, "AdaptiveCard", c.Type)
+
+	facts := c.Body[2].(factSet).Facts
+	assert.Contains(t, facts, fact{Title: "Approvers", Value: "alice, dev-team (Group)"})
+	assert.Contains(t, facts, fact{Title: "Approvals", Value: "1 of 2"})
+	assert.Contains(t, facts, fact{Title: "PipelineRun", Value: "deploy-run"})
+
+	if !assert.Len(t, c.Actions, 2) {
+		t.FailNow()
+	}
+	assert.Equal(t, "Action.Http", c.Actions[0].Type)
+	assert.Equal(t, callbackURL+"/teams/foo/at-1", c.Actions[0].URL)
+	assert.Equal(t, `{"input":"approve","message":"{{message.value}}","action":"`+signAction(actionKey, "foo", "at-1", "approve")+`"}`, c.Actions[0].Body)
+	assert.Equal(t, `{"input":"reject","message":"{{message.value}}","action":"`+signAction(actionKey, "foo", "at-1", "reject")+`"}`, c.Actions[1].Body)
+}
+
+func TestNewMessageWithoutActions(t *testing.T) {
+	at := pendingApprovalTask()
+	assert.Empty(t, newMessage(at, "", nil).Attachments[0].Content.Actions)
+
+	at.Status.State = "approved"
+	assert.Empty(t, newMessage(at, callbackURL, actionKey).Attachments[0].Content.Actions)
+}
+
+func TestReconcileApprovalTask(t *testing.T) {
+	var posted []message
+	teamsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg message
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Errorf("invalid card posted: %v", err)
+		}
+		posted = append(posted, msg)
+	}))
+	defer teamsServer.Close()
+
+	at := pendingApprovalTask()
+	atClient := fake.NewSimpleClientset(at)
+	n := &notifier{
+		kubeClientSet: fakekube.NewSimpleClientset(teamsSecret(map[string]string{
+			webhookURLKey: teamsServer.URL,
+			actionsKey:    "true",
+		}), actionKeySecret()),
+		approvaltaskClientSet: atClient,
+		httpClient:            teamsServer.Client(),
+		callbackURL:           callbackURL,
+		systemNamespace:       "manual-approval-gate",
+	}
+
+	ctx := context.Background()
+	assert.NoError(t, n.ReconcileApprovalTask(ctx, at.DeepCopy()))
+	if !assert.Len(t, posted, 1) {
+		t.FailNow()
+	}
+	assert.Len(t, posted[0].Attachments[0].Content.Actions, 2)
+
+	updated, err := atClient.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(ctx, "at-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "pending", updated.Status.Annotations[NotifiedAnnotation])
+
+	// The same state is not posted twice.
+	assert.NoError(t, n.ReconcileApprovalTask(ctx, updated.DeepCopy()))
+	assert.Len(t, posted, 1)
+
+	updated.Status.State = "approved"
+	assert.NoError(t, n.ReconcileApprovalTask(ctx, updated.DeepCopy()))
+	if assert.Len(t, posted, 2) {
+		assert.Empty(t, posted[1].Attachments[0].Content.Actions)
+	}
+}
+
+func TestReconcileApprovalTaskWithoutSecret(t *testing.T) {
+	at := pendingApprovalTask()
+	n := &notifier{
+		kubeClientSet:         fakekube.NewSimpleClientset(),
+		approvaltaskClientSet: fake.NewSimpleClientset(at),
+		httpClient:            http.DefaultClient,
+	}
+	assert.NoError(t, n.ReconcileApprovalTask(context.Background(), at))
+}
+
+// signer issues action tokens and serves the matching JWKS.
+type signer struct {
+	key    *rsa.PrivateKey
+	server *httptest.Server
+}
+
+func newSigner(t *testing.T) *signer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &signer{key: key}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "test-key",
+				"kty": "RSA",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *signer) token(t *testing.T, c claims) string {
+	header, _ := json.Marshal(jwtHeader{Algorithm: "RS256", KeyID: "test-key"})
+	payload, _ := json.Marshal(c)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestCallbackHandler(t *testing.T) {
+	s := newSigner(t)
+	validClaims := claims{
+		Issuer:    DefaultTokenIssuer,
+		Audience:  callbackURL,
+		Subject:   "Alice@corp.example.com",
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}
+
+	tests := []struct {
+		name       string
+		token      string
+		body       string
+		actions    string
+		wantStatus int
+		wantInput  string
+	}{{
+		name:       "valid token approves as the mapped user",
+		token:      s.token(t, validClaims),
+		wantStatus: http.StatusOK,
+		wantInput:  "approve",
+	}, {
+		name:       "missing token",
+		wantStatus: http.StatusUnauthorized,
+		wantInput:  "pending",
+	}, {
+		name: "token for another audience",
+		token: s.token(t, claims{
+			Issuer:    DefaultTokenIssuer,
+			Audience:  "https://evil.example.com",
+			Subject:   "Alice@corp.example.com",
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		}),
+		wantStatus: http.StatusUnauthorized,
+		wantInput:  "pending",
+	}, {
+		name: "expired token",
+		token: s.token(t, claims{
+			Issuer:    DefaultTokenIssuer,
+			Audience:  callbackURL,
+			Subject:   "Alice@corp.example.com",
+			ExpiresAt: time.Now().Add(-time.Hour).Unix(),
+		}),
+		wantStatus: http.StatusUnauthorized,
+		wantInput:  "pending",
+	}, {
+		name: "unmapped user",
+		token: s.token(t, claims{
+			Issuer:    DefaultTokenIssuer,
+			Audience:  callbackURL,
+			Subject:   "alice",
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		}),
+		wantStatus: http.StatusForbidden,
+		wantInput:  "pending",
+	}, {
+		name: "token of another issuer",
+		token: s.token(t, claims{
+			Issuer:    "https://evil.example.com/",
+			Audience:  callbackURL,
+			Subject:   "Alice@corp.example.com",
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		}),
+		wantStatus: http.StatusUnauthorized,
+		wantInput:  "pending",
+	}, {
+		name:       "action of another input",
+		token:      s.token(t, validClaims),
+		body:       strings.Replace(actionBody("foo", "at-1", "reject"), "reject", "approve", 1),
+		wantStatus: http.StatusForbidden,
+		wantInput:  "pending",
+	}, {
+		name:       "action of another ApprovalTask",
+		token:      s.token(t, validClaims),
+		body:       actionBody("bar", "at-1", "approve"),
+		wantStatus: http.StatusForbidden,
+		wantInput:  "pending",
+	}, {
+		name:       "action without signature",
+		token:      s.token(t, validClaims),
+		body:       `{"input":"approve","message":"ship it"}`,
+		wantStatus: http.StatusForbidden,
+		wantInput:  "pending",
+	}, {
+		name:       "actions not enabled for the namespace",
+		token:      s.token(t, validClaims),
+		actions:    "false",
+		wantStatus: http.StatusNotFound,
+		wantInput:  "pending",
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			atClient := fake.NewSimpleClientset(pendingApprovalTask())
+			var impersonated string
+			h := newTestCallbackHandler(s, atClient, &impersonated, tc.actions)
+
+			body := tc.body
+			if body == "" {
+				body = actionBody("foo", "at-1", "approve")
+			}
+			rec := callback(h, tc.token, body)
+
+			assert.Equal(t, tc.wantStatus, rec.Code, rec.Header().Get("CARD-ACTION-STATUS"))
+			at, err := atClient.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(context.Background(), "at-1", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.wantInput, at.Spec.Approvers[0].Input)
+			if tc.wantStatus == http.StatusOK {
+				assert.Equal(t, "alice", impersonated)
+				assert.Equal(t, "ship it", at.Spec.Approvers[0].Message)
+			}
+		})
+	}
+}
+
+func TestCallbackHandlerRejectsReplayedTokens(t *testing.T) {
+	s := newSigner(t)
+	token := s.token(t, claims{
+		Issuer:    DefaultTokenIssuer,
+		Audience:  callbackURL,
+		Subject:   "Alice@corp.example.com",
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	})
+	atClient := fake.NewSimpleClientset(pendingApprovalTask())
+	var impersonated string
+	h := newTestCallbackHandler(s, atClient, &impersonated, "")
+
+	rec := callback(h, token, actionBody("foo", "at-1", "reject"))
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Header().Get("CARD-ACTION-STATUS"))
+
+	// the token of the rejection cannot approve afterwards
+	rec = callback(h, token, actionBody("foo", "at-1", "approve"))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "Action token was used already", rec.Header().Get("CARD-ACTION-STATUS"))
+	at, err := atClient.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(context.Background(), "at-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "reject", at.Spec.Approvers[0].Input)
+}
+
+// newTestCallbackHandler returns a handler verifying the tokens of s and
+// answering with atClient as the impersonated user. actions is the actions
+// key of the Secret of the namespace, true when empty.
+func newTestCallbackHandler(s *signer, atClient versioned.Interface, impersonated *string, actions string) *callbackHandler {
+	if actions == "" {
+		actions = "true"
+	}
+	return &callbackHandler{
+		kubeClientSet: fakekube.NewSimpleClientset(teamsSecret(map[string]string{
+			webhookURLKey: "https://teams.example.com/webhook",
+			actionsKey:    actions,
+			// The Secret cannot change the audience of the tokens
+			"callback-url": "https://evil.example.com/",
+			// nor how tokens are verified
+			"jwks-url":     "https://evil.example.com/keys",
+			"token-issuer": "https://evil.example.com/",
+			// nor how users are mapped
+			"user-mapping": "alice: alice",
+		}), userMapping("Alice@corp.example.com: alice"), actionKeySecret()),
+		clientFor: func(username string, _ []string) (versioned.Interface, error) {
+			*impersonated = username
+			return atClient, nil
+		},
+		verifier:        newTokenVerifier(s.server.Client()),
+		jwksURL:         s.server.URL,
+		issuer:          DefaultTokenIssuer,
+		callbackURL:     callbackURL + "/",
+		usedTokens:      newUsedTokens(),
+		systemNamespace: "manual-approval-gate",
+		logger:          zap.NewNop().Sugar(),
+	}
+}
+
+// callback sends an action to h with token.
+func callback(h *callbackHandler, token, body string) *httptest.ResponseRecorder {
+	mux := http.NewServeMux()
+	mux.Handle("POST /teams/{namespace}/{name}", h)
+
+	req := httptest.NewRequest(http.MethodPost, "/teams/foo/at-1", strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package integrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// UserMappingConfigMap, in the namespace of the controller, maps the
+// identities of the users of the external systems to Kubernetes usernames,
+// one YAML map per system under the key of the system, e.g.:
+//
+//	github: |
+//	  alice-gh: alice
+//
+// The controller records decisions as the usernames it maps to, so only
+// cluster admins must be able to change it.
+const UserMappingConfigMap = "manual-approval-gate-user-mapping"
+
+// ErrUnmappedIdentity is returned for the identities the
+// UserMappingConfigMap does not map.
+var ErrUnmappedIdentity = errors.New("identity is not mapped to a Kubernetes user")
+
+// UserMapping maps the identities of the users of an external system to
+// Kubernetes usernames.
+type UserMapping map[string]string
+
+// LoadUserMapping reads the mapping of system from the UserMappingConfigMap
+// in namespace. A missing ConfigMap, or system, maps no identity.
+func LoadUserMapping(ctx context.Context, kube kubernetes.Interface, namespace, system string) (UserMapping, error) {
+	cm, err := kube.CoreV1().ConfigMaps(namespace).Get(ctx, UserMappingConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return UserMapping{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	mapping := UserMapping{}
+	if err := yaml.Unmarshal([]byte(cm.Data[system]), &mapping); err != nil {
+		return nil, fmt.Errorf("invalid %s user mapping in ConfigMap %s/%s: %w", system, namespace, UserMappingConfigMap, err)
+	}
+	return mapping, nil
+}
+
+// Username returns the Kubernetes username identity maps to, and
+// ErrUnmappedIdentity when it is not mapped: an identity of an external
+// system is never taken for a Kubernetes username as it is.
+func (m UserMapping) Username(identity string) (string, error) {
+	if username := m[identity]; identity != "" && username != "" {
+		return username, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnmappedIdentity, identity)
+}
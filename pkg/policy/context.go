@@ -0,0 +1,33 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import "context"
+
+type hookKey struct{}
+
+// WithHook returns a context carrying the policy Hook.
+func WithHook(ctx context.Context, hook *Hook) context.Context {
+	return context.WithValue(ctx, hookKey{}, hook)
+}
+
+// FromContext returns the policy Hook of the context, nil when no external
+// policy is configured.
+func FromContext(ctx context.Context) *Hook {
+	hook, _ := ctx.Value(hookKey{}).(*Hook)
+	return hook
+}
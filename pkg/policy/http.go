@@ -0,0 +1,81 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// HTTPAuthorizer posts the Input to a policy decision point, wrapped as
+// {"input": ...} the way the Open Policy Agent data API expects it. The
+// Decision is read from the "result" field of the response, or from the
+// response itself when there is none.
+type HTTPAuthorizer struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+// NewHTTPAuthorizer returns an Authorizer posting to url, with token as
+// bearer token when it is set. The timeout of client bounds every decision.
+func NewHTTPAuthorizer(client *http.Client, url, token string) *HTTPAuthorizer {
+	return &HTTPAuthorizer{client: client, url: url, token: token}
+}
+
+func (a *HTTPAuthorizer) Authorize(ctx context.Context, input Input) (Decision, error) {
+	body, err := json.Marshal(struct {
+		Input Input `json:"input"`
+	}{Input: input})
+	if err != nil {
+		return Decision{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return Decision{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return Decision{}, fmt.Errorf("policy decision point returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var answer struct {
+		Result *Decision `json:"result"`
+		Decision
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1024*1024)).Decode(&answer); err != nil {
+		return Decision{}, fmt.Errorf("invalid policy decision: %w", err)
+	}
+	if answer.Result != nil {
+		return *answer.Result, nil
+	}
+	return answer.Decision, nil
+}
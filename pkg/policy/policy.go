@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy asks an external policy decision point, e.g. Open Policy
+// Agent, whether a change to an ApprovalTask is allowed.
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+)
+
+// Input is the document sent to the policy decision point.
+type Input struct {
+	Operation string                    `json:"operation"`
+	Namespace string                    `json:"namespace"`
+	Name      string                    `json:"name"`
+	UserInfo  authenticationv1.UserInfo `json:"userInfo"`
+	Object    json.RawMessage           `json:"object,omitempty"`
+	OldObject json.RawMessage           `json:"oldObject,omitempty"`
+}
+
+// Decision is the answer of the policy decision point.
+type Decision struct {
+	Allow  bool   `json:"allow"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Authorizer decides whether a change is allowed. An error means that no
+// decision could be made.
+type Authorizer interface {
+	Authorize(ctx context.Context, input Input) (Decision, error)
+}
+
+// AuthorizerFunc is an in-process Authorizer, e.g. for tests.
+type AuthorizerFunc func(ctx context.Context, input Input) (Decision, error)
+
+func (f AuthorizerFunc) Authorize(ctx context.Context, input Input) (Decision, error) {
+	return f(ctx, input)
+}
+
+// FailurePolicy tells what to do when no decision could be made.
+type FailurePolicy string
+
+const (
+	// FailClosed denies changes without a decision.
+	FailClosed FailurePolicy = "closed"
+	// FailOpen allows changes without a decision.
+	FailOpen FailurePolicy = "open"
+)
+
+// ParseFailurePolicy parses a FailurePolicy, FailClosed when value is empty.
+func ParseFailurePolicy(value string) (FailurePolicy, error) {
+	switch FailurePolicy(value) {
+	case "", FailClosed:
+		return FailClosed, nil
+	case FailOpen:
+		return FailOpen, nil
+	}
+	return "", fmt.Errorf("unknown policy failure mode %q, expected closed or open", value)
+}
+
+// Hook is an Authorizer with the FailurePolicy to apply on its errors.
+type Hook struct {
+	Authorizer    Authorizer
+	FailurePolicy FailurePolicy
+}
+
+// Decide asks the Authorizer and applies the FailurePolicy when it fails.
+// The error, if any, is returned along with the decision for logging.
+func (h *Hook) Decide(ctx context.Context, input Input) (Decision, error) {
+	decision, err := h.Authorizer.Authorize(ctx, input)
+	if err == nil {
+		return decision, nil
+	}
+	if h.FailurePolicy == FailOpen {
+		return Decision{Allow: true}, err
+	}
+	return Decision{Reason: "the policy decision is unavailable"}, err
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	authenticationv1 "k8s.io/api/authentication/v1"
+)
+
+func input() Input {
+	return Input{
+		Operation: "UPDATE",
+		Namespace: "foo",
+		Name:      "at-1",
+		UserInfo:  authenticationv1.UserInfo{Username: "alice", Groups: []string{"tekton"}},
+		Object:    json.RawMessage(`{"kind":"ApprovalTask"}`),
+	}
+}
+
+func TestHTTPAuthorizer(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     Decision
+	}{{
+		name:     "opa result",
+		response: `{"result":{"allow":false,"reason":"outside of the change window"}}`,
+		want:     Decision{Reason: "outside of the change window"},
+	}, {
+		name:     "plain decision",
+		response: `{"allow":true}`,
+		want:     Decision{Allow: true},
+	}, {
+		name:     "undefined opa result",
+		response: `{}`,
+		want:     Decision{},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got Input
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+				var body struct {
+					Input Input `json:"input"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Error(err)
+				}
+				got = body.Input
+				_, _ = w.Write([]byte(tc.response))
+			}))
+			defer server.Close()
+
+			decision, err := NewHTTPAuthorizer(server.Client(), server.URL, "secret").Authorize(context.Background(), input())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, decision)
+			assert.Equal(t, input(), got)
+		})
+	}
+}
+
+func TestHTTPAuthorizerErrors(t *testing.T) {
+	unavailable := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	}))
+	defer unavailable.Close()
+	_, err := NewHTTPAuthorizer(unavailable.Client(), unavailable.URL, "").Authorize(context.Background(), input())
+	assert.ErrorContains(t, err, "503")
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+	client := &http.Client{Timeout: 20 * time.Millisecond}
+	_, err = NewHTTPAuthorizer(client, slow.URL, "").Authorize(context.Background(), input())
+	assert.Error(t, err)
+}
+
+func TestHookFailurePolicy(t *testing.T) {
+	failing := AuthorizerFunc(func(context.Context, Input) (Decision, error) {
+		return Decision{}, errors.New("unreachable")
+	})
+
+	decision, err := (&Hook{Authorizer: failing, FailurePolicy: FailClosed}).Decide(context.Background(), input())
+	assert.Error(t, err)
+	assert.False(t, decision.Allow)
+
+	decision, err = (&Hook{Authorizer: failing, FailurePolicy: FailOpen}).Decide(context.Background(), input())
+	assert.Error(t, err)
+	assert.True(t, decision.Allow)
+
+	denying := AuthorizerFunc(func(_ context.Context, in Input) (Decision, error) {
+		return Decision{Allow: in.UserInfo.Username != "alice", Reason: "alice is on leave"}, nil
+	})
+	decision, err = (&Hook{Authorizer: denying, FailurePolicy: FailOpen}).Decide(context.Background(), input())
+	assert.NoError(t, err)
+	assert.Equal(t, Decision{Reason: "alice is on leave"}, decision)
+}
+
+func TestParseFailurePolicy(t *testing.T) {
+	for value, want := range map[string]FailurePolicy{"": FailClosed, "closed": FailClosed, "open": FailOpen} {
+		got, err := ParseFailurePolicy(value)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseFailurePolicy("maybe")
+	assert.Error(t, err)
+}
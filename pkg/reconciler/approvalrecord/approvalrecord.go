@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package approvalrecord enforces the retention of ApprovalRecords.
+package approvalrecord
+
+import (
+	"context"
+
+	approvaltaskclientset "github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	approvaltaskclient "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/client"
+	approvalrecordinformer "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/informers/approvaltask/v1alpha1/approvalrecord"
+	listersapprovaltask "github.com/openshift-pipelines/manual-approval-gate/pkg/client/listers/approvaltask/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+// Reconciler deletes the ApprovalRecords which expired.
+type Reconciler struct {
+	pkgreconciler.LeaderAwareFuncs
+
+	clock                 clock.PassiveClock
+	approvaltaskClientSet approvaltaskclientset.Interface
+	lister                listersapprovaltask.ApprovalRecordLister
+}
+
+var _ controller.Reconciler = (*Reconciler)(nil)
+var _ pkgreconciler.LeaderAware = (*Reconciler)(nil)
+
+// NewController instantiates a new controller.Impl from knative.dev/pkg/controller
+func NewController(clock clock.PassiveClock) func(context.Context, configmap.Watcher) *controller.Impl {
+	return func(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+		logger := logging.FromContext(ctx)
+		approvalrecordInformer := approvalrecordinformer.Get(ctx)
+		lister := approvalrecordInformer.Lister()
+
+		r := &Reconciler{
+			LeaderAwareFuncs: pkgreconciler.LeaderAwareFuncs{
+				// Enqueue every ApprovalRecord owned by a bucket when we become its leader.
+				PromoteFunc: func(bkt pkgreconciler.Bucket, enq func(pkgreconciler.Bucket, types.NamespacedName)) error {
+					all, err := lister.List(labels.Everything())
+					if err != nil {
+						return err
+					}
+					for _, record := range all {
+						enq(bkt, types.NamespacedName{Namespace: record.Namespace, Name: record.Name})
+					}
+					return nil
+				},
+			},
+			clock:                 clock,
+			approvaltaskClientSet: approvaltaskclient.Get(ctx),
+			lister:                lister,
+		}
+
+		impl := controller.NewContext(ctx, r, controller.ControllerOptions{WorkQueueName: "ApprovalRecordRetention", Logger: logger.Named("ApprovalRecordRetention")})
+
+		if _, err := approvalrecordInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    impl.Enqueue,
+			UpdateFunc: controller.PassNew(impl.Enqueue),
+		}); err != nil {
+			logger.Panicf("couldn't register ApprovalRecord informer event handler: %v", err)
+		}
+
+		return impl
+	}
+}
+
+// Reconcile implements controller.Reconciler
+func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Errorw("Invalid resource key", zap.String("key", key), zap.Error(err))
+		return nil
+	}
+
+	if !r.IsLeaderFor(types.NamespacedName{Namespace: namespace, Name: name}) {
+		return controller.NewSkipKey(key)
+	}
+
+	record, err := r.lister.ApprovalRecords(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if record.Spec.ExpirationTime == nil || record.DeletionTimestamp != nil {
+		return nil
+	}
+
+	if remaining := record.Spec.ExpirationTime.Sub(r.clock.Now()); remaining > 0 {
+		return controller.NewRequeueAfter(remaining)
+	}
+
+	logger.Infof("ApprovalRecord %s/%s expired at %s, deleting it", namespace, name, record.Spec.ExpirationTime)
+	err = r.approvaltaskClientSet.OpenshiftpipelinesV1alpha1().ApprovalRecords(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &record.UID},
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approvalrecord
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/fake"
+	listersapprovaltask "github.com/openshift-pipelines/manual-approval-gate/pkg/client/listers/approvaltask/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	clocktesting "k8s.io/utils/clock/testing"
+	"knative.dev/pkg/controller"
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+func TestRetention(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		expiration  *metav1.Time
+		wantDeleted bool
+		wantRequeue time.Duration
+	}{{
+		name: "kept forever",
+	}, {
+		name:        "not expired",
+		expiration:  &metav1.Time{Time: now.Add(time.Hour)},
+		wantRequeue: time.Hour,
+	}, {
+		name:        "expired",
+		expiration:  &metav1.Time{Time: now.Add(-time.Second)},
+		wantDeleted: true,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			record := &v1alpha1.ApprovalRecord{
+				ObjectMeta: metav1.ObjectMeta{Name: "bar-01234567", Namespace: "foo", UID: "record-uid"},
+				Spec: v1alpha1.ApprovalRecordSpec{
+					ApprovalTask:   v1alpha1.ApprovalRecordReference{Name: "bar"},
+					Decision:       "approved",
+					ExpirationTime: tc.expiration,
+				},
+			}
+			client := fake.NewSimpleClientset(record)
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if err := indexer.Add(record); err != nil {
+				t.Fatal(err)
+			}
+
+			r := &Reconciler{
+				clock:                 clocktesting.NewFakePassiveClock(now),
+				approvaltaskClientSet: client,
+				lister:                listersapprovaltask.NewApprovalRecordLister(indexer),
+			}
+			if err := r.Promote(pkgreconciler.UniversalBucket(), func(pkgreconciler.Bucket, types.NamespacedName) {}); err != nil {
+				t.Fatal(err)
+			}
+			err := r.Reconcile(ctx, "foo/bar-01234567")
+			if tc.wantRequeue > 0 {
+				ok, after := controller.IsRequeueKey(err)
+				assert.True(t, ok, "expected a requeue, got %v", err)
+				assert.Equal(t, tc.wantRequeue, after)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			_, err = client.OpenshiftpipelinesV1alpha1().ApprovalRecords("foo").Get(ctx, "bar-01234567", metav1.GetOptions{})
+			assert.Equal(t, tc.wantDeleted, errors.IsNotFound(err))
+		})
+	}
+}
@@ -19,6 +19,7 @@ package approvaltask
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -27,15 +28,20 @@ import (
 	approvaltaskv1alpha1 "github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
 	approvaltaskclientset "github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
 	listersapprovaltask "github.com/openshift-pipelines/manual-approval-gate/pkg/client/listers/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/groups"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	clientset "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
 	customrunreconciler "github.com/tektoncd/pipeline/pkg/client/injection/reconciler/pipeline/v1beta1/customrun"
 	listersalpha "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1alpha1"
 	listers "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1beta1"
 	"github.com/tektoncd/pipeline/pkg/reconciler/events"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"gomodules.xyz/jsonpatch/v2"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/utils/clock"
 	"knative.dev/pkg/apis"
@@ -61,6 +67,9 @@ const (
 	description       = "description"
 	timeout           = "timeout"
 
+	changeRequestPrefix = "changerequest:"
+	rbacPrefix          = "rbac:"
+
 	// CustomRunLabelKey is used as the label identifier for a ApprovalTask
 	CustomRunLabelKey = "tekton.dev/customRun"
 
@@ -76,7 +85,21 @@ type Reconciler struct {
 	runLister             listersalpha.RunLister
 	customRunLister       listers.CustomRunLister
 	approvaltaskLister    listersapprovaltask.ApprovalTaskLister
+	approvalrecordLister  listersapprovaltask.ApprovalRecordLister
 	taskRunLister         listers.TaskRunLister
+	// recordRetention is how long ApprovalRecords are kept, forever when 0
+	recordRetention time.Duration
+	// signingSecret holds the key signing the attestations of the
+	// ApprovalRecords, which are not signed when its name is empty
+	signingSecret types.NamespacedName
+	// groupResolver resolves the members of the Group approvers
+	groupResolver groups.DirectoryProvider
+	// excludeAutomatedApprovals keeps the approvals of ServiceAccounts from
+	// counting toward the approvals required of new ApprovalTasks
+	excludeAutomatedApprovals bool
+	// identityStore keeps the identity configuration, usernames are
+	// compared as they are when nil
+	identityStore *identity.Store
 }
 
 var (
@@ -116,6 +139,43 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, run *v1beta1.CustomRun)
 
 	if run.IsDone() {
 		logger.Infof("Run %s/%s is done", run.Namespace, run.Name)
+		// Keep the ApprovalTask once its CustomRun is garbage collected
+		return c.archive(ctx, run)
+	}
+
+	// Trace the ApprovalTask as part of its PipelineRun
+	ctx = c.initTracing(ctx, run)
+
+	if run.IsCancelled() {
+		var approvalTask *approvaltaskv1alpha1.ApprovalTask
+		if c.approvaltaskLister != nil {
+			approvalTask, _ = c.approvaltaskLister.ApprovalTasks(run.Namespace).Get(run.Name)
+		}
+		// Reject the ApprovalTask before failing the run, so that it takes
+		// no more responses
+		if approvalTask != nil && approvalTask.Status.State != approvedState && approvalTask.Status.State != rejectedState {
+			approvalTask = approvalTask.DeepCopy()
+			approvalTask.Status.State = rejectedState
+			if _, err := c.approvaltaskClientSet.OpenshiftpipelinesV1alpha1().ApprovalTasks(approvalTask.Namespace).UpdateStatus(ctx, approvalTask, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+		}
+
+		beforeCondition := run.Status.GetCondition(apis.ConditionSucceeded)
+		logger.Infof("Run %s/%s is cancelled", run.Namespace, run.Name)
+		run.Status.MarkCustomRunFailed(v1beta1.CustomRunReasonCancelled.String(),
+			"ApprovalTask %s was cancelled", run.Name)
+		recordDecision(ctx, run.Namespace, cancelledDecision, c.clock.Since(run.Status.StartTime.Time))
+		traceWait(ctx, "ApprovalTask:Decision", run.Status.StartTime, c.clock.Now(),
+			attribute.String("approvaltask", run.Name),
+			attribute.String("namespace", run.Namespace),
+			attribute.String("decision", cancelledDecision),
+		)
+		emitEvent(ctx, approvalTask, run, corev1.EventTypeNormal, EventReasonCancelled,
+			"ApprovalTask %s was cancelled", run.Name)
+
+		afterCondition := run.Status.GetCondition(apis.ConditionSucceeded)
+		events.Emit(ctx, beforeCondition, afterCondition, run)
 		return nil
 	}
 
@@ -192,7 +252,18 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, run *v1beta1.CustomRun)
 func (r *Reconciler) reconcile(ctx context.Context, run *v1beta1.CustomRun, status *approvaltaskv1alpha1.ApprovalTaskRunStatus) error {
 	// Get the ApprovalTask referenced by the Run
 	logger := logging.FromContext(ctx)
-	approvalTask, err := getOrCreateApprovalTask(ctx, r.approvaltaskClientSet, run)
+	ctx = withExcludeAutomatedApprovals(ctx, r.excludeAutomatedApprovals)
+	// Approvals are counted once per user, whatever username they answered as
+	ctx = identity.WithConfig(ctx, r.identityStore.Load())
+	// The spec of the ApprovalTask is stored on the CustomRun once it exists
+	approvalTask, err := getOrCreateApprovalTask(ctx, r.approvaltaskClientSet, run, status.ApprovalTaskSpec != nil)
+	if errors.Is(err, errApprovalTaskDeleted) {
+		message := fmt.Sprintf("ApprovalTask %s was deleted before a decision", run.Name)
+		logger.Warn(message)
+		run.Status.MarkCustomRunFailed(approvaltaskv1alpha1.ApprovalTaskRunReasonApprovalTaskDeleted.String(), message)
+		emitEvent(ctx, nil, run, corev1.EventTypeWarning, EventReasonDeleted, message)
+		return nil
+	}
 	if err != nil {
 		logger.Errorf("Error getting or creating the approval task: %v", err.Error())
 		return err
@@ -207,6 +278,9 @@ func (r *Reconciler) reconcile(ctx context.Context, run *v1beta1.CustomRun, stat
 	// Propagate labels and annotations from ApprovalTask to Run.
 	propagateApprovalTaskLabelsAndAnnotations(run, approvalTaskMeta)
 
+	// Publish the results recorded by integrations, e.g. a linked issue key.
+	propagateApprovalTaskResults(run, approvalTask)
+
 	if !approvalTask.HasStarted() {
 		approvalTask.Status.StartTime = &approvalTask.CreationTimestamp
 	}
@@ -216,16 +290,26 @@ func (r *Reconciler) reconcile(ctx context.Context, run *v1beta1.CustomRun, stat
 		timeout = &metav1.Duration{Duration: time.Duration(60) * time.Minute}
 	}
 	if approvalTask.ApprovalTaskHasTimedOut(ctx, r.clock, timeout.Duration) {
+		wasPending := approvalTask.Status.State == pendingState
 		approvalTask.Status.State = rejectedState
 		_, err := r.approvaltaskClientSet.OpenshiftpipelinesV1alpha1().ApprovalTasks(approvalTask.Namespace).UpdateStatus(ctx, approvalTask, metav1.UpdateOptions{})
 		if err != nil {
 			return err
 		}
+		if wasPending {
+			recordDecision(ctx, approvalTask.Namespace, timedOutDecision, r.waitingTime(*approvalTask))
+			r.traceDecision(ctx, *approvalTask, timedOutDecision)
+			emitEvent(ctx, approvalTask, run, corev1.EventTypeWarning, EventReasonTimedOut,
+				"ApprovalTask %s timed out after %s without a decision", approvalTask.Name, timeout.Duration)
+		}
 		message := fmt.Sprintf("Approval task %s is failed because of timeout", approvalTask.Name)
 		run.Status.MarkCustomRunFailed(approvaltaskv1alpha1.ApprovalTaskRunReasonFailed.String(), message)
 		return nil
 	}
 
+	// Snapshot the members of the Group approvers, for the pending approvers
+	approvalTask = r.snapshotGroups(ctx, approvalTask)
+
 	if err := r.checkIfUpdateRequired(ctx, *approvalTask, run); err != nil {
 		return err
 	}
@@ -0,0 +1,233 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approvaltask
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/attestation"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// RecordRetentionEnv is the environment variable holding how long
+	// ApprovalRecords are kept, e.g. 8760h. They are kept forever when unset.
+	RecordRetentionEnv = "APPROVAL_RECORD_RETENTION"
+
+	// SigningSecretEnv is the environment variable holding the name of the
+	// Secret, in the namespace of the controller, whose signingKeyKey signs
+	// the attestations of the ApprovalRecords. They are not signed when unset.
+	SigningSecretEnv = "ATTESTATION_SIGNING_SECRET"
+
+	// signingKeyKey is the key of the private key in the signing Secret, the
+	// one Tekton Chains uses
+	signingKeyKey = "cosign.key"
+)
+
+// recordRetention reads the retention of the ApprovalRecords from the
+// environment.
+func recordRetention() (time.Duration, error) {
+	raw := os.Getenv(RecordRetentionEnv)
+	if raw == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(raw)
+}
+
+// archive writes the ApprovalRecord of the ApprovalTask of a done CustomRun.
+// It is called every time the CustomRun is reconciled once done, so a failure
+// is retried, and does nothing once the ApprovalRecord exists.
+func (c *Reconciler) archive(ctx context.Context, run *v1beta1.CustomRun) error {
+	if c.approvaltaskLister == nil || c.approvalrecordLister == nil {
+		return nil
+	}
+	approvalTask, err := c.approvaltaskLister.ApprovalTasks(run.Namespace).Get(run.Name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	decision := recordDecisionOf(approvalTask, run)
+	if decision == "" {
+		return nil
+	}
+	// Do not archive again once the ApprovalRecord expired and was deleted
+	if c.recordRetention > 0 && run.Status.CompletionTime != nil && c.clock.Since(run.Status.CompletionTime.Time) > c.recordRetention {
+		return nil
+	}
+
+	name := approvalRecordName(approvalTask)
+	if _, err := c.approvalrecordLister.ApprovalRecords(run.Namespace).Get(name); err == nil {
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+
+	record := newApprovalRecord(approvalTask, run, decision, c.clock.Now(), c.recordRetention)
+	if err := c.attest(ctx, record); err != nil {
+		return fmt.Errorf("failed to sign the attestation of ApprovalRecord %s/%s: %w", record.Namespace, record.Name, err)
+	}
+	_, err = c.approvaltaskClientSet.OpenshiftpipelinesV1alpha1().ApprovalRecords(run.Namespace).Create(ctx, record, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Infof("ApprovalTask %s/%s archived as ApprovalRecord %s", run.Namespace, approvalTask.Name, name)
+	return nil
+}
+
+// attest annotates the ApprovalRecord with the signed attestation of its
+// decision. The Secret is read every time so that the key can be rotated, and
+// an ApprovalRecord is not created unsigned when signing is enabled.
+func (c *Reconciler) attest(ctx context.Context, record *v1alpha1.ApprovalRecord) error {
+	if c.signingSecret.Name == "" {
+		return nil
+	}
+	secret, err := c.kubeClientSet.CoreV1().Secrets(c.signingSecret.Namespace).Get(ctx, c.signingSecret.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	key, ok := secret.Data[signingKeyKey]
+	if !ok {
+		return fmt.Errorf("secret %s has no %s", c.signingSecret, signingKeyKey)
+	}
+	signer, err := attestation.NewSigner(key)
+	if err != nil {
+		return err
+	}
+	envelope, err := attestation.Attest(signer, record)
+	if err != nil {
+		return err
+	}
+	if record.Annotations == nil {
+		record.Annotations = map[string]string{}
+	}
+	record.Annotations[v1alpha1.ApprovalRecordAttestationAnnotationKey] = envelope
+	return nil
+}
+
+// approvalRecordName is unique to the ApprovalTask, whose name is reused when
+// a PipelineRun of the same name runs again.
+func approvalRecordName(approvalTask *v1alpha1.ApprovalTask) string {
+	uid := string(approvalTask.UID)
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	if uid == "" {
+		return approvalTask.Name
+	}
+	return kmeta.ChildName(approvalTask.Name, "-"+uid)
+}
+
+// recordDecisionOf returns the decision the ApprovalTask of a done CustomRun
+// ended with, empty when it has none. A rejection without any rejecting
+// approver is a timeout.
+func recordDecisionOf(approvalTask *v1alpha1.ApprovalTask, run *v1beta1.CustomRun) string {
+	if condition := run.Status.GetCondition(apis.ConditionSucceeded); condition != nil && condition.Reason == v1beta1.CustomRunReasonCancelled.String() {
+		return cancelledDecision
+	}
+	switch approvalTask.Status.State {
+	case approvedState:
+		return approvedState
+	case rejectedState:
+		for _, response := range approvalTask.Status.ApproversResponse {
+			if response.Response == rejectedState {
+				return rejectedState
+			}
+			for _, member := range response.GroupMembers {
+				if member.Response == rejectedState {
+					return rejectedState
+				}
+			}
+		}
+		return timedOutDecision
+	}
+	return ""
+}
+
+// newApprovalRecord returns a trimmed copy of the ApprovalTask, without owner
+// reference so that it outlives its CustomRun.
+func newApprovalRecord(approvalTask *v1alpha1.ApprovalTask, run *v1beta1.CustomRun, decision string, now time.Time, retention time.Duration) *v1alpha1.ApprovalRecord {
+	labels := map[string]string{
+		v1alpha1.ApprovalTaskLabelKey:           approvalTask.Name,
+		v1alpha1.ApprovalRecordDecisionLabelKey: decision,
+	}
+	for _, key := range []string{pipeline.PipelineRunLabelKey, pipeline.PipelineLabelKey, pipeline.PipelineTaskLabelKey} {
+		if value, ok := approvalTask.Labels[key]; ok {
+			labels[key] = value
+		}
+	}
+
+	completionTime := run.Status.CompletionTime
+	if completionTime == nil {
+		completionTime = &metav1.Time{Time: now}
+	}
+
+	record := &v1alpha1.ApprovalRecord{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      approvalRecordName(approvalTask),
+			Namespace: approvalTask.Namespace,
+			Labels:    labels,
+		},
+		Spec: v1alpha1.ApprovalRecordSpec{
+			ApprovalTask: v1alpha1.ApprovalRecordReference{
+				Name: approvalTask.Name,
+				UID:  string(approvalTask.UID),
+			},
+			Approvers:                 approvalTask.Spec.Approvers,
+			NumberOfApprovalsRequired: approvalTask.Spec.NumberOfApprovalsRequired,
+			Description:               approvalTask.Spec.Description,
+			Decision:                  decision,
+			ApprovalsReceived:         approvalTask.Status.ApprovalsReceived,
+			History:                   approvalTask.Status.ApproversResponse,
+			StartTime:                 approvalTask.Status.StartTime,
+			CompletionTime:            completionTime,
+		},
+	}
+	if record.Spec.StartTime == nil {
+		record.Spec.StartTime = run.Status.StartTime
+	}
+	if retention > 0 {
+		record.Spec.ExpirationTime = &metav1.Time{Time: completionTime.Add(retention)}
+	}
+
+	if name := approvalTask.Labels[pipeline.PipelineRunLabelKey]; name != "" {
+		pipelineRun := &v1alpha1.ApprovalRecordPipelineRun{
+			Name:         name,
+			Pipeline:     approvalTask.Labels[pipeline.PipelineLabelKey],
+			PipelineTask: approvalTask.Labels[pipeline.PipelineTaskLabelKey],
+		}
+		for _, ref := range run.OwnerReferences {
+			if ref.Kind == pipeline.PipelineRunControllerName && ref.Name == name {
+				pipelineRun.UID = string(ref.UID)
+			}
+		}
+		record.Spec.PipelineRun = pipelineRun
+	}
+	return record.DeepCopy()
+}
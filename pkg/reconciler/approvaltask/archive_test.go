@@ -0,0 +1,285 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approvaltask
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/attestation"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/fake"
+	listersapprovaltask "github.com/openshift-pipelines/manual-approval-gate/pkg/client/listers/approvaltask/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	clocktesting "k8s.io/utils/clock/testing"
+	"knative.dev/pkg/apis"
+)
+
+func TestArchive(t *testing.T) {
+	completion := metav1.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	start := metav1.NewTime(completion.Add(-time.Hour))
+
+	tests := []struct {
+		name      string
+		state     string
+		responses []v1alpha1.ApproverState
+		reason    string
+		existing  bool
+		now       time.Time
+		want      string
+	}{{
+		name:      "approved",
+		state:     approvedState,
+		responses: []v1alpha1.ApproverState{{Name: "alice", Type: "User", Response: approvedState}},
+		want:      approvedState,
+	}, {
+		name:  "rejected by a group member",
+		state: rejectedState,
+		responses: []v1alpha1.ApproverState{{Name: "qa", Type: "Group", Response: rejectedState,
+			GroupMembers: []v1alpha1.GroupMemberState{{Name: "bob", Response: rejectedState, Message: "flaky"}}}},
+		want: rejectedState,
+	}, {
+		name:  "timed out",
+		state: rejectedState,
+		want:  timedOutDecision,
+	}, {
+		name:   "cancelled",
+		state:  pendingState,
+		reason: v1beta1.CustomRunReasonCancelled.String(),
+		want:   cancelledDecision,
+	}, {
+		name:  "failed without decision",
+		state: pendingState,
+	}, {
+		name:     "already archived",
+		state:    approvedState,
+		existing: true,
+	}, {
+		name:  "expired",
+		state: approvedState,
+		now:   completion.Add(48 * time.Hour),
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			approvalTask := &v1alpha1.ApprovalTask{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bar",
+					Namespace: "foo",
+					UID:       "0123456789abcdef",
+					Labels: map[string]string{
+						"tekton.dev/pipelineRun":  "release",
+						"tekton.dev/pipeline":     "deploy",
+						"tekton.dev/pipelineTask": "wait",
+						CustomRunLabelKey:         "bar",
+					},
+				},
+				Spec: v1alpha1.ApprovalTaskSpec{
+					Approvers:                 []v1alpha1.ApproverDetails{{Name: "alice", Type: "User", Input: "approve"}},
+					NumberOfApprovalsRequired: 1,
+				},
+				Status: v1alpha1.ApprovalTaskStatus{
+					State:             tc.state,
+					ApproversResponse: tc.responses,
+					StartTime:         &start,
+				},
+			}
+			run := &v1beta1.CustomRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "bar",
+					Namespace: "foo",
+					OwnerReferences: []metav1.OwnerReference{{
+						Kind: "PipelineRun",
+						Name: "release",
+						UID:  "pipelinerun-uid",
+					}},
+				},
+			}
+			run.Status.CompletionTime = &completion
+			reason := tc.reason
+			if reason == "" {
+				reason = "Failed"
+			}
+			run.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: "False", Reason: reason})
+
+			taskIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			recordIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			if err := taskIndexer.Add(approvalTask); err != nil {
+				t.Fatal(err)
+			}
+			client := fake.NewSimpleClientset()
+			if tc.existing {
+				if err := recordIndexer.Add(&v1alpha1.ApprovalRecord{ObjectMeta: metav1.ObjectMeta{Name: "bar-01234567", Namespace: "foo"}}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			now := tc.now
+			if now.IsZero() {
+				now = completion.Add(time.Minute)
+			}
+			r := &Reconciler{
+				clock:                 clocktesting.NewFakePassiveClock(now),
+				approvaltaskClientSet: client,
+				approvaltaskLister:    listersapprovaltask.NewApprovalTaskLister(taskIndexer),
+				approvalrecordLister:  listersapprovaltask.NewApprovalRecordLister(recordIndexer),
+				recordRetention:       24 * time.Hour,
+			}
+			if err := r.archive(ctx, run); err != nil {
+				t.Fatal(err)
+			}
+
+			records, err := client.OpenshiftpipelinesV1alpha1().ApprovalRecords("foo").List(ctx, metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				assert.Empty(t, records.Items)
+				return
+			}
+			if !assert.Len(t, records.Items, 1) {
+				t.FailNow()
+			}
+			record := records.Items[0]
+			assert.Equal(t, "bar-01234567", record.Name)
+			assert.Empty(t, record.OwnerReferences)
+			assert.Equal(t, map[string]string{
+				v1alpha1.ApprovalTaskLabelKey:           "bar",
+				v1alpha1.ApprovalRecordDecisionLabelKey: tc.want,
+				"tekton.dev/pipelineRun":                "release",
+				"tekton.dev/pipeline":                   "deploy",
+				"tekton.dev/pipelineTask":               "wait",
+			}, record.Labels)
+			assert.Equal(t, v1alpha1.ApprovalRecordSpec{
+				ApprovalTask:              v1alpha1.ApprovalRecordReference{Name: "bar", UID: "0123456789abcdef"},
+				PipelineRun:               &v1alpha1.ApprovalRecordPipelineRun{Name: "release", UID: "pipelinerun-uid", Pipeline: "deploy", PipelineTask: "wait"},
+				Approvers:                 approvalTask.Spec.Approvers,
+				NumberOfApprovalsRequired: 1,
+				Decision:                  tc.want,
+				History:                   tc.responses,
+				StartTime:                 &start,
+				CompletionTime:            &completion,
+				ExpirationTime:            &metav1.Time{Time: completion.Add(24 * time.Hour)},
+			}, record.Spec)
+		})
+	}
+}
+
+func TestArchiveSigned(t *testing.T) {
+	ctx := context.Background()
+	completion := metav1.NewTime(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})
+
+	approvalTask := &v1alpha1.ApprovalTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo", UID: "0123456789abcdef",
+			Labels: map[string]string{"tekton.dev/pipelineRun": "release"}},
+		Status: v1alpha1.ApprovalTaskStatus{
+			State:             approvedState,
+			ApproversResponse: []v1alpha1.ApproverState{{Name: "alice", Type: "User", Response: approvedState, Message: "LGTM"}},
+		},
+	}
+	run := &v1beta1.CustomRun{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo",
+		OwnerReferences: []metav1.OwnerReference{{Kind: "PipelineRun", Name: "release", UID: "pipelinerun-uid"}}}}
+	run.Status.CompletionTime = &completion
+	run.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: "True"})
+
+	newReconciler := func(secrets ...runtime.Object) (*Reconciler, *fake.Clientset) {
+		taskIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+		if err := taskIndexer.Add(approvalTask); err != nil {
+			t.Fatal(err)
+		}
+		client := fake.NewSimpleClientset()
+		return &Reconciler{
+			clock:                 clocktesting.NewFakePassiveClock(completion.Time),
+			kubeClientSet:         fakekube.NewSimpleClientset(secrets...),
+			approvaltaskClientSet: client,
+			approvaltaskLister:    listersapprovaltask.NewApprovalTaskLister(taskIndexer),
+			approvalrecordLister:  listersapprovaltask.NewApprovalRecordLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+			signingSecret:         types.NamespacedName{Namespace: "tekton-pipelines", Name: "signing-secrets"},
+		}, client
+	}
+
+	t.Run("signed", func(t *testing.T) {
+		r, client := newReconciler(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "signing-secrets", Namespace: "tekton-pipelines"},
+			Data:       map[string][]byte{"cosign.key": pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})},
+		})
+		if err := r.archive(ctx, run); err != nil {
+			t.Fatal(err)
+		}
+		record, err := client.OpenshiftpipelinesV1alpha1().ApprovalRecords("foo").Get(ctx, "bar-01234567", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var envelope attestation.Envelope
+		if err := json.Unmarshal([]byte(record.Annotations[v1alpha1.ApprovalRecordAttestationAnnotationKey]), &envelope); err != nil {
+			t.Fatal(err)
+		}
+		payload, err := attestation.Verify(&envelope, pubPEM)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var statement attestation.Statement
+		if err := json.Unmarshal(payload, &statement); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, []attestation.Subject{{
+			Name:   "pipelineruns/foo/release",
+			Digest: map[string]string{"sha256": attestation.Digest("pipelinerun-uid")},
+		}}, statement.Subject)
+		assert.Equal(t, approvedState, statement.Predicate.Decision)
+		assert.Equal(t, "LGTM", statement.Predicate.Responses[0].Message)
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		r, client := newReconciler()
+		assert.Error(t, r.archive(ctx, run))
+		records, err := client.OpenshiftpipelinesV1alpha1().ApprovalRecords("foo").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Empty(t, records.Items)
+	})
+}
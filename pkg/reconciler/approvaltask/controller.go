@@ -17,23 +17,37 @@ package approvaltask
 
 import (
 	"context"
+	"os"
 
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask"
 	approvaltaskv1alpha1 "github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	versionedscheme "github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/scheme"
 	approvaltaskclient "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/client"
+	approvalrecordinformer "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/informers/approvaltask/v1alpha1/approvalrecord"
 	approvaltaskinformer "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/informers/approvaltask/v1alpha1/approvaltask"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/groups"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
 	pipelineclient "github.com/tektoncd/pipeline/pkg/client/injection/client"
 	customruninformer "github.com/tektoncd/pipeline/pkg/client/injection/informers/pipeline/v1beta1/customrun"
 	customrunreconciler "github.com/tektoncd/pipeline/pkg/client/injection/reconciler/pipeline/v1beta1/customrun"
 	pipelinecontroller "github.com/tektoncd/pipeline/pkg/controller"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/utils/clock"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection/clients/dynamicclient"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
 )
 
+func init() {
+	// Events are recorded on ApprovalTasks with the client-go scheme
+	versionedscheme.AddToScheme(scheme.Scheme)
+}
+
 // NewController instantiates a new controller.Impl from knative.dev/pkg/controller
 func NewController(clock clock.PassiveClock) func(context.Context, configmap.Watcher) *controller.Impl {
 	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
@@ -44,6 +58,20 @@ func NewController(clock clock.PassiveClock) func(context.Context, configmap.Wat
 		approvaltaskclientset := approvaltaskclient.Get(ctx)
 		customRunInformer := customruninformer.Get(ctx)
 		approvaltaskInformer := approvaltaskinformer.Get(ctx)
+		approvalrecordInformer := approvalrecordinformer.Get(ctx)
+
+		retention, err := recordRetention()
+		if err != nil {
+			logger.Errorf("Invalid %s, ApprovalRecords are kept forever: %v", RecordRetentionEnv, err)
+		}
+
+		groupResolver, err := groups.NewResolver(dynamicclient.Get(ctx), kubeclientset, system.Namespace(), clock)
+		if err != nil {
+			logger.Errorf("Invalid %s, group members are cached for %s: %v", groups.CacheTTLEnv, groups.DefaultCacheTTL, err)
+		}
+
+		identityStore := identity.NewStore(logger.Named("config-store"))
+		identityStore.WatchConfigs(cmw)
 
 		c := &Reconciler{
 			clock:                 clock,
@@ -52,6 +80,13 @@ func NewController(clock clock.PassiveClock) func(context.Context, configmap.Wat
 			approvaltaskClientSet: approvaltaskclientset,
 			customRunLister:       customRunInformer.Lister(),
 			approvaltaskLister:    approvaltaskInformer.Lister(),
+			approvalrecordLister:  approvalrecordInformer.Lister(),
+			recordRetention:       retention,
+			signingSecret:         types.NamespacedName{Namespace: system.Namespace(), Name: os.Getenv(SigningSecretEnv)},
+			groupResolver:         groupResolver,
+			identityStore:         identityStore,
+
+			excludeAutomatedApprovals: os.Getenv(ExcludeAutomatedApprovalsEnv) == "true",
 		}
 
 		impl := customrunreconciler.NewImpl(ctx, c, func(impl *controller.Impl) controller.Options {
@@ -60,6 +95,10 @@ func NewController(clock clock.PassiveClock) func(context.Context, configmap.Wat
 			}
 		})
 
+		if _, err := registerPendingGauge(customRunInformer.Lister()); err != nil {
+			logger.Errorf("Failed to register the pending ApprovalTasks gauge: %v", err)
+		}
+
 		logger.Info("Setting up event handlers")
 
 		// Add event handler for Runs
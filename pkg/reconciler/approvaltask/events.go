@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approvaltask
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/controller"
+)
+
+// Reasons of the Events recorded on the ApprovalTask and its CustomRun.
+const (
+	// EventReasonCreated is recorded when the ApprovalTask is created
+	EventReasonCreated = "ApprovalTaskCreated"
+	// EventReasonApproved is recorded when an approver approves
+	EventReasonApproved = "Approved"
+	// EventReasonRejected is recorded when an approver rejects
+	EventReasonRejected = "Rejected"
+	// EventReasonQuorumReached is recorded when enough approvers approved
+	EventReasonQuorumReached = "QuorumReached"
+	// EventReasonTimedOut is recorded when nobody decided in time
+	EventReasonTimedOut = "TimedOut"
+	// EventReasonCancelled is recorded when the CustomRun is cancelled
+	EventReasonCancelled = "Cancelled"
+	// EventReasonDeleted is recorded when the ApprovalTask is deleted while
+	// pending
+	EventReasonDeleted = "ApprovalTaskDeleted"
+)
+
+// emitEvent records an Event on the ApprovalTask and on the CustomRun, either
+// of which may be nil, with the recorder of the reconciler.
+func emitEvent(ctx context.Context, approvalTask *v1alpha1.ApprovalTask, run *v1beta1.CustomRun, eventtype, reason, messageFmt string, args ...interface{}) {
+	recorder := controller.GetEventRecorder(ctx)
+	if recorder == nil {
+		return
+	}
+	if approvalTask != nil {
+		recorder.Eventf(approvalTask, eventtype, reason, messageFmt, args...)
+	}
+	if run != nil {
+		recorder.Eventf(run, eventtype, reason, messageFmt, args...)
+	}
+}
+
+// emitAnswerEvent records the answer of an approver.
+func emitAnswerEvent(ctx context.Context, approvalTask *v1alpha1.ApprovalTask, run *v1beta1.CustomRun, a answer) {
+	eventtype, reason, verb := corev1.EventTypeNormal, EventReasonApproved, "approved"
+	if a.Response == rejectedState {
+		eventtype, reason, verb = corev1.EventTypeWarning, EventReasonRejected, "rejected"
+	}
+
+	who := a.Approver
+	switch {
+	case a.Group != "":
+		who = fmt.Sprintf("%s (group %s)", a.Approver, a.Group)
+	case a.Type == v1alpha1.ChangeRequestApproverType:
+		who = "Change request " + a.Approver
+	}
+	if v1alpha1.IsServiceAccount(a.Approver) {
+		who += " (automated)"
+	}
+	message := fmt.Sprintf("%s %s ApprovalTask %s", who, verb, approvalTask.Name)
+	if a.Message != "" {
+		message += ": " + a.Message
+	}
+	emitEvent(ctx, approvalTask, run, eventtype, reason, "%s", message)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approvaltask
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/fake"
+	listersapprovaltask "github.com/openshift-pipelines/manual-approval-gate/pkg/client/listers/approvaltask/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/controller"
+)
+
+func TestEvents(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers []struct{ input, message string }
+		want    []string
+	}{{
+		name: "approved",
+		answers: []struct{ input, message string }{
+			{input: "approve"},
+			{input: "approve", message: "ship it"},
+		},
+		want: []string{
+			"Normal Approved alice approved ApprovalTask bar",
+			"Normal Approved bob approved ApprovalTask bar: ship it",
+			"Normal QuorumReached ApprovalTask bar approved, 2 of 2 approval(s) received",
+		},
+	}, {
+		name: "rejected",
+		answers: []struct{ input, message string }{
+			{input: "reject", message: "not tested"},
+		},
+		want: []string{
+			"Warning Rejected alice rejected ApprovalTask bar: not tested",
+		},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(20)
+			ctx := controller.WithEventRecorder(context.Background(), recorder)
+			run := &v1beta1.CustomRun{
+				ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"},
+				Spec: v1beta1.CustomRunSpec{
+					Params: []v1beta1.Param{
+						{Name: "approvers", Value: *v1beta1.NewArrayOrString("alice", "bob")},
+						{Name: "numberOfApprovalsRequired", Value: *v1beta1.NewArrayOrString("2")},
+					},
+				},
+			}
+			client := fake.NewSimpleClientset()
+			if _, err := createApprovalTask(ctx, client, run); err != nil {
+				t.Fatal(err)
+			}
+			want := []string{"Normal ApprovalTaskCreated ApprovalTask bar created, waiting for 2 approval(s) from alice, bob"}
+
+			r := &Reconciler{clock: clocktesting.NewFakePassiveClock(metav1.Now().Time), approvaltaskClientSet: client}
+			for i, answer := range tc.answers {
+				at, err := client.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(ctx, "bar", metav1.GetOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+				at.Spec.Approvers[i].Input = answer.input
+				at.Spec.Approvers[i].Message = answer.message
+				if err := r.checkIfUpdateRequired(ctx, *at, run); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want = append(want, tc.want...)
+
+			// Every Event is recorded on the ApprovalTask and the CustomRun
+			var got []string
+			for len(recorder.Events) > 0 {
+				got = append(got, <-recorder.Events)
+			}
+			var expected []string
+			for _, event := range want {
+				expected = append(expected, event, event)
+			}
+			assert.Equal(t, expected, got)
+		})
+	}
+}
+
+func TestCancellationRejectsTheApprovalTask(t *testing.T) {
+	recorder := record.NewFakeRecorder(20)
+	ctx := controller.WithEventRecorder(context.Background(), recorder)
+	start := metav1.Now()
+	run := &v1beta1.CustomRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"},
+		Spec: v1beta1.CustomRunSpec{
+			CustomRef: &v1beta1.TaskRef{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       approvaltask.ControllerName,
+			},
+			Params: []v1beta1.Param{
+				{Name: "approvers", Value: *v1beta1.NewArrayOrString("alice", "bob")},
+				{Name: "numberOfApprovalsRequired", Value: *v1beta1.NewArrayOrString("2")},
+			},
+			Status: v1beta1.CustomRunSpecStatusCancelled,
+		},
+	}
+	run.Status.InitializeConditions()
+	run.Status.StartTime = &start
+
+	client := fake.NewSimpleClientset()
+	approvalTask, err := createApprovalTask(ctx, client, run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	approvalTask.Status.State = pendingState
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := indexer.Add(&approvalTask); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &Reconciler{
+		clock:                 clocktesting.NewFakePassiveClock(start.Time),
+		approvaltaskClientSet: client,
+		approvaltaskLister:    listersapprovaltask.NewApprovalTaskLister(indexer),
+	}
+	assert.NoError(t, r.ReconcileKind(ctx, run))
+
+	at, err := client.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(ctx, "bar", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, rejectedState, at.Status.State)
+	assert.Equal(t, v1beta1.CustomRunReasonCancelled.String(), run.Status.GetCondition(apis.ConditionSucceeded).Reason)
+	// and is archived as cancelled
+	assert.Equal(t, cancelledDecision, recordDecisionOf(at, run))
+}
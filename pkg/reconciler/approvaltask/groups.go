@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approvaltask
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+)
+
+// snapshotGroups records the members of the Group approvers of the pending
+// ApprovalTask in its status, once, so that the users who may still answer
+// are known without reading the Group objects. The snapshot is retried on the
+// next reconcile when a group cannot be resolved.
+func (r *Reconciler) snapshotGroups(ctx context.Context, approvalTask *v1alpha1.ApprovalTask) *v1alpha1.ApprovalTask {
+	if r.groupResolver == nil || approvalTask.Status.State != pendingState || approvalTask.Status.ResolvedGroups != nil {
+		return approvalTask
+	}
+	logger := logging.FromContext(ctx)
+
+	resolved := []v1alpha1.ResolvedGroup{}
+	seen := make(map[string]bool)
+	for _, approver := range approvalTask.Spec.Approvers {
+		if v1alpha1.DefaultedApproverType(approver.Type) != "Group" || seen[approver.Name] {
+			continue
+		}
+		seen[approver.Name] = true
+		members, err := r.groupResolver.Members(ctx, approver.Name)
+		if err != nil {
+			logger.Warnf("Failed to resolve the members of group %s of approval task %s: %v", approver.Name, approvalTask.Name, err)
+			return approvalTask
+		}
+		resolved = append(resolved, v1alpha1.ResolvedGroup{Name: approver.Name, Members: members})
+	}
+	if len(resolved) == 0 {
+		return approvalTask
+	}
+
+	approvalTask.Status.ResolvedGroups = resolved
+	updated, err := r.approvaltaskClientSet.OpenshiftpipelinesV1alpha1().ApprovalTasks(approvalTask.Namespace).UpdateStatus(ctx, approvalTask, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Warnf("Failed to record the group members of approval task %s: %v", approvalTask.Name, err)
+		approvalTask.Status.ResolvedGroups = nil
+		return approvalTask
+	}
+	return updated
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approvaltask
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type staticResolver struct {
+	members map[string][]string
+	err     error
+}
+
+func (r staticResolver) Members(_ context.Context, group string) ([]string, error) {
+	return r.members[group], r.err
+}
+
+func TestSnapshotGroups(t *testing.T) {
+	run := &v1beta1.CustomRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bar",
+			Namespace: "foo",
+		},
+		Spec: v1beta1.CustomRunSpec{
+			Params: []v1beta1.Param{
+				{
+					Name:  "approvers",
+					Value: *v1beta1.NewArrayOrString("foo", "group:tekton", "group:release"),
+				},
+				{
+					Name:  "numberOfApprovalsRequired",
+					Value: *v1beta1.NewArrayOrString("1"),
+				},
+			},
+		},
+	}
+
+	client := fake.NewSimpleClientset()
+	approvalTask, err := createApprovalTask(context.TODO(), client, run)
+	if err != nil {
+		t.Fatalf("createApprovalTask returned an error: %v", err)
+	}
+	approvalTask.Status.State = pendingState
+
+	// A group that cannot be resolved is retried on the next reconcile
+	r := &Reconciler{approvaltaskClientSet: client, groupResolver: staticResolver{err: errors.New("unavailable")}}
+	at := r.snapshotGroups(context.TODO(), &approvalTask)
+	assert.Nil(t, at.Status.ResolvedGroups)
+
+	r.groupResolver = staticResolver{members: map[string][]string{"tekton": {"alice", "bob"}}}
+	at = r.snapshotGroups(context.TODO(), at)
+	assert.Equal(t, []v1alpha1.ResolvedGroup{
+		{Name: "tekton", Members: []string{"alice", "bob"}},
+		{Name: "release"},
+	}, at.Status.ResolvedGroups)
+
+	stored, err := client.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(context.TODO(), "bar", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, at.Status.ResolvedGroups, stored.Status.ResolvedGroups)
+
+	// The snapshot is taken once
+	r.groupResolver = staticResolver{members: map[string][]string{"tekton": {"carol"}}}
+	at = r.snapshotGroups(context.TODO(), at)
+	assert.Equal(t, []string{"alice", "bob"}, at.Status.ResolvedGroups[0].Members)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approvaltask
+
+import (
+	"context"
+	"time"
+
+	listers "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1beta1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"k8s.io/apimachinery/pkg/labels"
+	"knative.dev/pkg/observability/attributekey"
+)
+
+const (
+	scopeName = "github.com/openshift-pipelines/manual-approval-gate/pkg/reconciler/approvaltask"
+
+	// Decisions other than approvedState and rejectedState
+	timedOutDecision  = "timed_out"
+	cancelledDecision = "cancelled"
+)
+
+var (
+	// NamespaceAttr is the namespace of the ApprovalTask
+	NamespaceAttr = attributekey.String("namespace")
+	// DecisionAttr is how the ApprovalTask ended: approved, rejected,
+	// timed_out or cancelled, or the answer of the first response
+	DecisionAttr = attributekey.String("decision")
+)
+
+var (
+	createdCounter       metric.Int64Counter
+	decidedCounter       metric.Int64Counter
+	pendingGauge         metric.Int64ObservableGauge
+	firstResponseLatency metric.Float64Histogram
+	decisionLatency      metric.Float64Histogram
+)
+
+func init() {
+	resetPackageMetrics()
+}
+
+// resetPackageMetrics creates the instruments with the global meter provider,
+// which sharedmain configures from config-observability.
+func resetPackageMetrics() {
+	var err error
+	meter := otel.GetMeterProvider().Meter(scopeName)
+
+	// Approvals can take from seconds to days.
+	buckets := metric.WithExplicitBucketBoundaries(10, 30, 60, 300, 600, 1800, 3600, 7200, 14400, 28800, 86400, 259200)
+
+	if createdCounter, err = meter.Int64Counter(
+		"approvaltask.created",
+		metric.WithDescription("The number of ApprovalTasks created."),
+	); err != nil {
+		panic(err)
+	}
+	if decidedCounter, err = meter.Int64Counter(
+		"approvaltask.decided",
+		metric.WithDescription("The number of ApprovalTasks approved, rejected, timed out or cancelled."),
+	); err != nil {
+		panic(err)
+	}
+	if pendingGauge, err = meter.Int64ObservableGauge(
+		"approvaltask.pending",
+		metric.WithDescription("The number of ApprovalTasks waiting for a decision."),
+	); err != nil {
+		panic(err)
+	}
+	if firstResponseLatency, err = meter.Float64Histogram(
+		"approvaltask.first_response.duration",
+		metric.WithDescription("The time from the start of an ApprovalTask to the first response of an approver."),
+		metric.WithUnit("s"),
+		buckets,
+	); err != nil {
+		panic(err)
+	}
+	if decisionLatency, err = meter.Float64Histogram(
+		"approvaltask.decision.duration",
+		metric.WithDescription("The time from the start of an ApprovalTask to its decision."),
+		metric.WithUnit("s"),
+		buckets,
+	); err != nil {
+		panic(err)
+	}
+}
+
+// registerPendingGauge reports the pending ApprovalTasks, i.e. the running
+// CustomRuns of ApprovalTasks, per namespace.
+func registerPendingGauge(customRunLister listers.CustomRunLister) (metric.Registration, error) {
+	meter := otel.GetMeterProvider().Meter(scopeName)
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		runs, err := customRunLister.List(labels.Everything())
+		if err != nil {
+			return err
+		}
+		pending := map[string]int64{}
+		for _, run := range runs {
+			if checkCustomRunReferencesApprovalTask(run) != nil || run.IsDone() {
+				continue
+			}
+			pending[run.Namespace]++
+		}
+		for namespace, count := range pending {
+			o.ObserveInt64(pendingGauge, count, metric.WithAttributes(NamespaceAttr.With(namespace)))
+		}
+		return nil
+	}, pendingGauge)
+}
+
+func recordCreated(ctx context.Context, namespace string) {
+	createdCounter.Add(ctx, 1, metric.WithAttributes(NamespaceAttr.With(namespace)))
+}
+
+// recordFirstResponse records how long the ApprovalTask waited for its first
+// response, answered with decision.
+func recordFirstResponse(ctx context.Context, namespace, decision string, elapsed time.Duration) {
+	firstResponseLatency.Record(ctx, elapsed.Seconds(), metric.WithAttributes(
+		NamespaceAttr.With(namespace),
+		DecisionAttr.With(decision),
+	))
+}
+
+// recordDecision records that the ApprovalTask ended with decision after
+// elapsed.
+func recordDecision(ctx context.Context, namespace, decision string, elapsed time.Duration) {
+	attrs := metric.WithAttributes(NamespaceAttr.With(namespace), DecisionAttr.With(decision))
+	decidedCounter.Add(ctx, 1, attrs)
+	decisionLatency.Record(ctx, elapsed.Seconds(), attrs)
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approvaltask
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	listers "github.com/tektoncd/pipeline/pkg/client/listers/pipeline/v1beta1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/controller"
+)
+
+// setupMetrics directs the metrics of the package to a manual reader.
+func setupMetrics(t *testing.T) *sdkmetric.ManualReader {
+	reader := sdkmetric.NewManualReader()
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	resetPackageMetrics()
+	t.Cleanup(func() {
+		otel.SetMeterProvider(previous)
+		resetPackageMetrics()
+	})
+	return reader
+}
+
+// collect returns the metrics of the package by name.
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		if sm.Scope.Name != scopeName {
+			continue
+		}
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+func TestMetricsOfDecision(t *testing.T) {
+	reader := setupMetrics(t)
+
+	run := &v1beta1.CustomRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"},
+		Spec: v1beta1.CustomRunSpec{
+			Params: []v1beta1.Param{
+				{Name: "approvers", Value: *v1beta1.NewArrayOrString("alice", "bob")},
+				{Name: "numberOfApprovalsRequired", Value: *v1beta1.NewArrayOrString("2")},
+			},
+		},
+	}
+	client := fake.NewSimpleClientset()
+	ctx := context.Background()
+
+	if _, err := createApprovalTask(ctx, client, run); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakePassiveClock(start.Add(90 * time.Second))
+	r := &Reconciler{clock: clock, approvaltaskClientSet: client}
+
+	answer := func(approver int) {
+		at, err := client.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Get(ctx, "bar", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		at.Spec.Approvers[approver].Input = "approve"
+		at.Status.StartTime = &metav1.Time{Time: start}
+		if err := r.checkIfUpdateRequired(ctx, *at, run.DeepCopy()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	answer(0)
+	clock.SetTime(start.Add(10 * time.Minute))
+	answer(1)
+
+	metrics := collect(t, reader)
+	namespace := attribute.String("namespace", "foo")
+	approved := attribute.String("decision", "approved")
+
+	created := metrics["approvaltask.created"].(metricdata.Sum[int64])
+	if assert.Len(t, created.DataPoints, 1) {
+		assert.Equal(t, int64(1), created.DataPoints[0].Value)
+		assert.Equal(t, attribute.NewSet(namespace), created.DataPoints[0].Attributes)
+	}
+
+	decided := metrics["approvaltask.decided"].(metricdata.Sum[int64])
+	if assert.Len(t, decided.DataPoints, 1) {
+		assert.Equal(t, int64(1), decided.DataPoints[0].Value)
+		assert.Equal(t, attribute.NewSet(namespace, approved), decided.DataPoints[0].Attributes)
+	}
+
+	firstResponse := metrics["approvaltask.first_response.duration"].(metricdata.Histogram[float64])
+	if assert.Len(t, firstResponse.DataPoints, 1) {
+		assert.Equal(t, uint64(1), firstResponse.DataPoints[0].Count)
+		assert.Equal(t, float64(90), firstResponse.DataPoints[0].Sum)
+		assert.Equal(t, attribute.NewSet(namespace, approved), firstResponse.DataPoints[0].Attributes)
+	}
+
+	decision := metrics["approvaltask.decision.duration"].(metricdata.Histogram[float64])
+	if assert.Len(t, decision.DataPoints, 1) {
+		assert.Equal(t, uint64(1), decision.DataPoints[0].Count)
+		assert.Equal(t, float64(600), decision.DataPoints[0].Sum)
+	}
+}
+
+func TestMetricsOfCancellation(t *testing.T) {
+	reader := setupMetrics(t)
+
+	start := metav1.NewTime(time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC))
+	run := &v1beta1.CustomRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"},
+		Spec: v1beta1.CustomRunSpec{
+			CustomRef: &v1beta1.TaskRef{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       approvaltask.ControllerName,
+			},
+			Status: v1beta1.CustomRunSpecStatusCancelled,
+		},
+	}
+	run.Status.InitializeConditions()
+	run.Status.StartTime = &start
+
+	r := &Reconciler{clock: clocktesting.NewFakePassiveClock(start.Add(time.Hour))}
+	ctx := controller.WithEventRecorder(context.Background(), record.NewFakeRecorder(10))
+	assert.NoError(t, r.ReconcileKind(ctx, run))
+
+	condition := run.Status.GetCondition(apis.ConditionSucceeded)
+	assert.True(t, condition.IsFalse())
+	assert.Equal(t, v1beta1.CustomRunReasonCancelled.String(), condition.Reason)
+
+	decided := collect(t, reader)["approvaltask.decided"].(metricdata.Sum[int64])
+	if assert.Len(t, decided.DataPoints, 1) {
+		assert.Equal(t, attribute.NewSet(
+			attribute.String("namespace", "foo"),
+			attribute.String("decision", "cancelled"),
+		), decided.DataPoints[0].Attributes)
+	}
+}
+
+func TestPendingGauge(t *testing.T) {
+	reader := setupMetrics(t)
+
+	newRun := func(namespace, name, kind string, done bool) *v1beta1.CustomRun {
+		run := &v1beta1.CustomRun{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: v1beta1.CustomRunSpec{
+				CustomRef: &v1beta1.TaskRef{
+					APIVersion: v1alpha1.SchemeGroupVersion.String(),
+					Kind:       v1beta1.TaskKind(kind),
+				},
+			},
+		}
+		run.Status.InitializeConditions()
+		if done {
+			run.Status.MarkCustomRunSucceeded("Succeeded", "")
+		}
+		return run
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, run := range []*v1beta1.CustomRun{
+		newRun("foo", "pending-1", approvaltask.ControllerName, false),
+		newRun("foo", "pending-2", approvaltask.ControllerName, false),
+		newRun("foo", "approved", approvaltask.ControllerName, true),
+		newRun("bar", "pending", approvaltask.ControllerName, false),
+		newRun("bar", "other", "OtherTask", false),
+	} {
+		if err := indexer.Add(run); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := registerPendingGauge(listers.NewCustomRunLister(indexer)); err != nil {
+		t.Fatal(err)
+	}
+
+	pending := collect(t, reader)["approvaltask.pending"].(metricdata.Gauge[int64])
+	got := map[string]int64{}
+	for _, dp := range pending.DataPoints {
+		namespace, _ := dp.Attributes.Value("namespace")
+		got[namespace.AsString()] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{"foo": 2, "bar": 1}, got)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approvaltask
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+)
+
+const (
+	// ExcludeAutomatedApprovalsEnv is the environment variable which, when
+	// "true", keeps the approvals of ServiceAccounts from counting toward the
+	// approvals required of the ApprovalTasks created afterwards. Their
+	// rejections still reject.
+	ExcludeAutomatedApprovalsEnv = "EXCLUDE_AUTOMATED_APPROVALS"
+
+	serviceAccountPrefix = "serviceaccount:"
+)
+
+type excludeAutomatedApprovalsKey struct{}
+
+// withExcludeAutomatedApprovals returns a context creating ApprovalTasks with
+// the policy of the controller for automated approvals.
+func withExcludeAutomatedApprovals(ctx context.Context, exclude bool) context.Context {
+	return context.WithValue(ctx, excludeAutomatedApprovalsKey{}, exclude)
+}
+
+func excludeAutomatedApprovals(ctx context.Context) bool {
+	exclude, _ := ctx.Value(excludeAutomatedApprovalsKey{}).(bool)
+	return exclude
+}
+
+// serviceAccountUsername returns the username of a ServiceAccount approver,
+// given as serviceaccount:<namespace>:<name> or as its username.
+func serviceAccountUsername(paramValue string) (string, bool) {
+	if strings.HasPrefix(paramValue, serviceAccountPrefix) {
+		return v1alpha1.ServiceAccountUsernamePrefix + strings.TrimPrefix(paramValue, serviceAccountPrefix), true
+	}
+	return paramValue, v1alpha1.IsServiceAccount(paramValue)
+}
+
+// validateServiceAccountSyntax validates the namespace and the name of a
+// ServiceAccount approver.
+func validateServiceAccountSyntax(paramValue string, paramIndex int) error {
+	username, _ := serviceAccountUsername(paramValue)
+	if _, _, err := v1alpha1.ParseServiceAccountUsername(username); err != nil {
+		return fmt.Errorf("approvers[%d]: invalid ServiceAccount '%s' - use 'serviceaccount:<namespace>:<name>': %v", paramIndex, paramValue, err)
+	}
+	return nil
+}
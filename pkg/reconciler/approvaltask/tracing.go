@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approvaltask
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/tracing"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+)
+
+// TracerName is the name of the tracer of the ApprovalTask reconciler.
+const TracerName = "ApprovalTaskReconciler"
+
+// initTracing returns ctx carrying the span context of the PipelineRun the
+// CustomRun belongs to, so that the spans of the ApprovalTask are part of the
+// trace of the PipelineRun. The span context is looked up once and then
+// propagated through the SpanContextAnnotation of the CustomRun.
+func (c *Reconciler) initTracing(ctx context.Context, run *v1beta1.CustomRun) context.Context {
+	if carrier := tracing.Carrier(run.Annotations, tracing.SpanContextAnnotation); carrier != nil {
+		return tracing.Extract(ctx, carrier)
+	}
+
+	carrier := c.pipelineRunSpanContext(ctx, run)
+	if carrier == nil {
+		return ctx
+	}
+	spanContext, err := tracing.Marshal(carrier)
+	if err != nil {
+		logging.FromContext(ctx).Errorf("Unable to marshal the span context of Run %s/%s: %v", run.Namespace, run.Name, err)
+		return ctx
+	}
+	if run.Annotations == nil {
+		run.Annotations = map[string]string{}
+	}
+	run.Annotations[tracing.SpanContextAnnotation] = spanContext
+	return tracing.Extract(ctx, carrier)
+}
+
+// pipelineRunSpanContext returns the span context of the PipelineRun of the
+// CustomRun, or the one propagated by Tekton from the parent of the
+// PipelineRun, nil when the PipelineRun is not traced.
+func (c *Reconciler) pipelineRunSpanContext(ctx context.Context, run *v1beta1.CustomRun) propagation.MapCarrier {
+	if name := run.Labels[pipeline.PipelineRunLabelKey]; name != "" && c.pipelineClientSet != nil {
+		pr, err := c.pipelineClientSet.TektonV1().PipelineRuns(run.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err == nil && len(pr.Status.SpanContext) > 0 {
+			return pr.Status.SpanContext
+		}
+	}
+	return tracing.Carrier(run.Annotations, tracing.PipelineRunSpanContextAnnotation)
+}
+
+// traceWait records a span named name covering the time spent waiting on the
+// ApprovalTask from start until end.
+func traceWait(ctx context.Context, name string, start *metav1.Time, end time.Time, attrs ...attribute.KeyValue) {
+	startTime := end
+	if start != nil {
+		startTime = start.Time
+	}
+	_, span := otel.Tracer(TracerName).Start(ctx, name,
+		trace.WithTimestamp(startTime),
+		trace.WithAttributes(attrs...),
+	)
+	span.End(trace.WithTimestamp(end))
+}
+
+// traceResponse records a span from the start of the ApprovalTask to the
+// answer of an approver.
+func (r *Reconciler) traceResponse(ctx context.Context, approvalTask v1alpha1.ApprovalTask, a answer) {
+	attrs := []attribute.KeyValue{
+		attribute.String("approvaltask", approvalTask.Name),
+		attribute.String("namespace", approvalTask.Namespace),
+		attribute.String("approver", a.Approver),
+		attribute.String("approver.type", a.Type),
+		attribute.String("response", a.Response),
+	}
+	if a.Group != "" {
+		attrs = append(attrs, attribute.String("group", a.Group))
+	}
+	traceWait(ctx, "ApprovalTask:Response", approvalTask.Status.StartTime, r.clock.Now(), attrs...)
+}
+
+// traceDecision records a span from the start of the ApprovalTask to its
+// decision.
+func (r *Reconciler) traceDecision(ctx context.Context, approvalTask v1alpha1.ApprovalTask, decision string) {
+	traceWait(ctx, "ApprovalTask:Decision", approvalTask.Status.StartTime, r.clock.Now(),
+		attribute.String("approvaltask", approvalTask.Name),
+		attribute.String("namespace", approvalTask.Namespace),
+		attribute.String("decision", decision),
+		attribute.Int("approvals.received", approvalTask.Status.ApprovalsReceived),
+		attribute.Int("approvals.required", approvalTask.Status.ApprovalsRequired),
+	)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package approvaltask
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	pipelinev1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	pipelinefake "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+const pipelineRunTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// setupTracing records the spans of the package.
+func setupTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+func TestInitTracingFromPipelineRun(t *testing.T) {
+	setupTracing(t)
+
+	pipelineClient := pipelinefake.NewSimpleClientset(&pipelinev1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "pr", Namespace: "foo"},
+		Status: pipelinev1.PipelineRunStatus{
+			PipelineRunStatusFields: pipelinev1.PipelineRunStatusFields{
+				SpanContext: map[string]string{"traceparent": pipelineRunTraceParent},
+			},
+		},
+	})
+	r := &Reconciler{pipelineClientSet: pipelineClient}
+	run := &v1beta1.CustomRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bar",
+			Namespace: "foo",
+			Labels:    map[string]string{"tekton.dev/pipelineRun": "pr"},
+		},
+	}
+
+	ctx := r.initTracing(context.Background(), run)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID(ctx))
+	assert.Equal(t, `{"traceparent":"`+pipelineRunTraceParent+`"}`, run.Annotations[tracing.SpanContextAnnotation])
+
+	// The span context is then read from the CustomRun
+	r.pipelineClientSet = pipelinefake.NewSimpleClientset()
+	ctx = r.initTracing(context.Background(), run)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID(ctx))
+}
+
+func TestInitTracingWithoutPipelineRun(t *testing.T) {
+	setupTracing(t)
+
+	r := &Reconciler{pipelineClientSet: pipelinefake.NewSimpleClientset()}
+	run := &v1beta1.CustomRun{ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"}}
+
+	ctx := r.initTracing(context.Background(), run)
+	assert.Equal(t, "", traceID(ctx))
+	assert.NotContains(t, run.Annotations, tracing.SpanContextAnnotation)
+}
+
+func TestTraceResponsesAndDecision(t *testing.T) {
+	recorder := setupTracing(t)
+
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	r := &Reconciler{clock: clocktesting.NewFakePassiveClock(start.Add(time.Hour))}
+	ctx := tracing.Extract(context.Background(), propagation.MapCarrier{"traceparent": pipelineRunTraceParent})
+
+	approvalTask := v1alpha1.ApprovalTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "bar", Namespace: "foo"},
+		Status: v1alpha1.ApprovalTaskStatus{
+			StartTime: &metav1.Time{Time: start},
+			ApproversResponse: []v1alpha1.ApproverState{
+				{Name: "alice", Type: "User", Response: "approved"},
+				{Name: "qa", Type: "Group", Response: "approved", GroupMembers: []v1alpha1.GroupMemberState{
+					{Name: "bob", Response: "approved"},
+				}},
+			},
+		},
+	}
+	previous := approvalTask.Status.ApproversResponse[:1]
+
+	for _, a := range newAnswers(previous, approvalTask.Status.ApproversResponse) {
+		r.traceResponse(ctx, approvalTask, a)
+	}
+	r.traceDecision(ctx, approvalTask, approvedState)
+
+	spans := recorder.Ended()
+	if !assert.Len(t, spans, 2) {
+		t.FailNow()
+	}
+	for _, span := range spans {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+		assert.Equal(t, start, span.StartTime())
+		assert.Equal(t, start.Add(time.Hour), span.EndTime())
+	}
+
+	assert.Equal(t, "ApprovalTask:Response", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("approver", "bob"))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("group", "qa"))
+	assert.Equal(t, "ApprovalTask:Decision", spans[1].Name())
+	assert.Contains(t, spans[1].Attributes(), attribute.String("decision", "approved"))
+}
+
+func traceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
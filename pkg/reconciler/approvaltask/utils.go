@@ -20,20 +20,32 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask"
 	v1alpha1 "github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/tracing"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tektoncd/pipeline/pkg/reconciler/events"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/logging"
 )
@@ -42,7 +54,7 @@ var (
 	gvk = schema.GroupVersionKind{Group: "tekton.dev", Version: "v1beta1", Kind: "CustomRun"}
 )
 
-// validateApproverParameter validates a single approver string (user or group format).
+// validateApproverParameter validates a single approver string (user, group, RBAC, ServiceAccount or change request format).
 func validateApproverParameter(paramValue string, paramIndex int) error {
 	if strings.TrimSpace(paramValue) == "" {
 		return fmt.Errorf("approvers[%d]: approver name cannot be empty", paramIndex)
@@ -58,6 +70,21 @@ func validateApproverParameter(paramValue string, paramIndex int) error {
 		return validateGroupSyntax(paramValue, paramIndex)
 	}
 
+	// Handle change request syntax: "changerequest:<number>" or "changerequest:new"
+	if strings.HasPrefix(paramValue, changeRequestPrefix) {
+		return validateChangeRequestSyntax(paramValue, paramIndex)
+	}
+
+	// Handle RBAC syntax: "rbac:", "rbac:*" or "rbac:<resourceName>"
+	if strings.HasPrefix(paramValue, rbacPrefix) {
+		return validateRBACSyntax(paramValue, paramIndex)
+	}
+
+	// Handle ServiceAccount syntax: "serviceaccount:<namespace>:<name>" or its username
+	if _, ok := serviceAccountUsername(paramValue); ok {
+		return validateServiceAccountSyntax(paramValue, paramIndex)
+	}
+
 	return validateUserSyntax(paramValue, paramIndex)
 }
 
@@ -83,6 +110,32 @@ func validateGroupSyntax(paramValue string, paramIndex int) error {
 	return nil
 }
 
+// validateChangeRequestSyntax validates the "changerequest:<number>" format.
+func validateChangeRequestSyntax(paramValue string, paramIndex int) error {
+	number := strings.TrimPrefix(paramValue, changeRequestPrefix)
+	if strings.TrimSpace(number) == "" {
+		return fmt.Errorf("approvers[%d]: invalid change request format '%s' - use 'changerequest:<number>' or 'changerequest:%s'", paramIndex, paramValue, v1alpha1.NewChangeRequest)
+	}
+	if strings.ContainsAny(number, ": ") {
+		return fmt.Errorf("approvers[%d]: change request number '%s' cannot contain colons or spaces", paramIndex, number)
+	}
+
+	return nil
+}
+
+// validateRBACSyntax validates the "rbac:<resourceName>" format, the
+// resourceName being optional.
+func validateRBACSyntax(paramValue string, paramIndex int) error {
+	name := strings.TrimPrefix(paramValue, rbacPrefix)
+	if name == "" || name == v1alpha1.AnyApprovalTask {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("approvers[%d]: invalid RBAC resourceName '%s': %s", paramIndex, name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
 // validateUserSyntax validates a plain username approver.
 func validateUserSyntax(paramValue string, paramIndex int) error {
 	// Validate user name format inline
@@ -137,6 +190,7 @@ func validateApproversParam(param v1beta1.Param) (int, []string) {
 	approverList := parseApproversList(param, &validationErrors)
 
 	// Validate each approver
+	changeRequests := 0
 	for i, approver := range approverList {
 		switch val := approver.(type) {
 		case string:
@@ -145,6 +199,12 @@ func validateApproversParam(param v1beta1.Param) (int, []string) {
 			} else {
 				approversCount++
 			}
+			if strings.HasPrefix(val, changeRequestPrefix) {
+				changeRequests++
+				if changeRequests > 1 {
+					validationErrors = append(validationErrors, fmt.Sprintf("approvers[%d]: only one change request approver is supported", i))
+				}
+			}
 		case map[string]interface{}:
 			validateMalformedObjectApprover(val, i, &validationErrors)
 		default:
@@ -257,7 +317,14 @@ func initializeCustomRun(ctx context.Context, run *v1beta1.CustomRun) {
 	}
 }
 
-func getOrCreateApprovalTask(ctx context.Context, approvaltaskClientSet versioned.Interface, run *v1beta1.CustomRun) (*v1alpha1.ApprovalTask, error) {
+// errApprovalTaskDeleted is returned by getOrCreateApprovalTask when the
+// ApprovalTask of a CustomRun was deleted while it was pending
+var errApprovalTaskDeleted = stderrors.New("ApprovalTask was deleted")
+
+// getOrCreateApprovalTask returns the ApprovalTask of the CustomRun, created
+// unless it existed already, i.e. was deleted: recreating it would reset the
+// inputs of the approvers.
+func getOrCreateApprovalTask(ctx context.Context, approvaltaskClientSet versioned.Interface, run *v1beta1.CustomRun, existed bool) (*v1alpha1.ApprovalTask, error) {
 	approvalTask := v1alpha1.ApprovalTask{}
 
 	if run.Spec.CustomRef != nil {
@@ -267,6 +334,9 @@ func getOrCreateApprovalTask(ctx context.Context, approvaltaskClientSet versione
 		tl, err := approvaltaskClientSet.OpenshiftpipelinesV1alpha1().ApprovalTasks(run.Namespace).Get(ctx, run.Name, metav1.GetOptions{})
 		if err != nil {
 			if errors.IsNotFound(err) {
+				if existed {
+					return nil, errApprovalTaskDeleted
+				}
 				at, err := createApprovalTask(ctx, approvaltaskClientSet, run)
 				if err != nil {
 					return nil, err
@@ -314,6 +384,36 @@ func propagateApprovalTaskLabelsAndAnnotations(run *v1beta1.CustomRun, approvalt
 	}
 }
 
+// propagateApprovalTaskResults sets a result on the Run for every status
+// annotation of the ApprovalTask prefixed with v1alpha1.ResultAnnotationPrefix.
+func propagateApprovalTaskResults(run *v1beta1.CustomRun, approvalTask *v1alpha1.ApprovalTask) {
+	keys := make([]string, 0, len(approvalTask.Status.Annotations))
+	for key := range approvalTask.Status.Annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name, ok := strings.CutPrefix(key, v1alpha1.ResultAnnotationPrefix)
+		if !ok || name == "" {
+			continue
+		}
+		value := approvalTask.Status.Annotations[key]
+
+		found := false
+		for i := range run.Status.Results {
+			if run.Status.Results[i].Name == name {
+				run.Status.Results[i].Value = value
+				found = true
+				break
+			}
+		}
+		if !found {
+			run.Status.Results = append(run.Status.Results, v1beta1.CustomRunResult{Name: name, Value: value})
+		}
+	}
+}
+
 func (c *Reconciler) updateLabelsAndAnnotations(ctx context.Context, run *v1beta1.CustomRun) error {
 	newRun, err := c.customRunLister.CustomRuns(run.Namespace).Get(run.Name)
 	if err != nil {
@@ -360,7 +460,19 @@ func createApprovalTask(ctx context.Context, approvaltaskClientSet versioned.Int
 					approver.Input = pendingState
 
 					// Check if the type is mentioned in the params
-					if strings.HasPrefix(name, "group:") {
+					if strings.HasPrefix(name, changeRequestPrefix) {
+						approver.Type = v1alpha1.ChangeRequestApproverType
+						approver.Name = strings.TrimPrefix(name, changeRequestPrefix)
+					} else if strings.HasPrefix(name, rbacPrefix) {
+						approver.Type = v1alpha1.RBACApproverType
+						approver.Name = strings.TrimPrefix(name, rbacPrefix)
+						if approver.Name == "" {
+							approver.Name = v1alpha1.AnyApprovalTask
+						}
+					} else if username, ok := serviceAccountUsername(name); ok {
+						approver.Type = v1alpha1.ServiceAccountApproverType
+						approver.Name = username
+					} else if strings.HasPrefix(name, "group:") {
 						approver.Type = "Group"
 
 						if strings.HasPrefix(approver.Name, "group:") {
@@ -420,18 +532,35 @@ func createApprovalTask(ctx context.Context, approvaltaskClientSet versioned.Int
 	approvalTask.Annotations = map[string]string{
 		LastAppliedHashKey: approverSpecHash,
 	}
+	// Let the admission webhook trace decisions as part of the PipelineRun
+	if spanContext := run.Annotations[tracing.SpanContextAnnotation]; spanContext != "" {
+		approvalTask.Annotations[tracing.SpanContextAnnotation] = spanContext
+	}
 
+	_, span := otel.Tracer(TracerName).Start(ctx, "ApprovalTask:Create", trace.WithAttributes(
+		attribute.String("approvaltask", approvalTask.Name),
+		attribute.String("namespace", approvalTask.Namespace),
+		attribute.StringSlice("approvers", users),
+		attribute.Int("approvals.required", numberOfApprovalsRequired),
+	))
 	_, err = approvaltaskClientSet.OpenshiftpipelinesV1alpha1().ApprovalTasks(run.Namespace).Create(ctx, approvalTask, metav1.CreateOptions{})
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
 		return v1alpha1.ApprovalTask{}, err
 	}
+	span.End()
 	logger.Infof("Approval Task %s is created", approvalTask.Name)
+	recordCreated(ctx, run.Namespace)
 
 	at, err := approvaltaskClientSet.OpenshiftpipelinesV1alpha1().ApprovalTasks(run.Namespace).Get(ctx, run.Name, metav1.GetOptions{})
 	if err != nil {
 		logger.Errorf("Error retrieving the created ApprovalTask %s: %v", run.Name, err)
 		return v1alpha1.ApprovalTask{}, err
 	}
+	emitEvent(ctx, at, run, corev1.EventTypeNormal, EventReasonCreated,
+		"ApprovalTask %s created, waiting for %d approval(s) from %s", at.Name, numberOfApprovalsRequired, strings.Join(users, ", "))
 
 	status := v1alpha1.ApprovalTaskStatus{
 		State:             pendingState,
@@ -439,6 +568,8 @@ func createApprovalTask(ctx context.Context, approvaltaskClientSet versioned.Int
 		ApproversResponse: []v1alpha1.ApproverState{},
 		ApprovalsRequired: numberOfApprovalsRequired,
 		ApprovalsReceived: 0, // Initially no approvals received
+		// Record the policy, so that the webhook counts approvals like the controller
+		ExcludeAutomatedApprovals: excludeAutomatedApprovals(ctx),
 	}
 
 	at.Status = status
@@ -450,16 +581,35 @@ func createApprovalTask(ctx context.Context, approvaltaskClientSet versioned.Int
 	return *at, nil
 }
 
+// approvalState computes the state of the ApprovalTask from the inputs of its
+// approvers and the responses of its external approvers, rather than
+// trusting the stored state: rejected on any reject, approved once enough
+// approvals are received and pending otherwise.
+func approvalState(approvalTask v1alpha1.ApprovalTask, config *identity.Config) string {
+	if approvalTaskHasFalseInput(approvalTask) {
+		return rejectedState
+	}
+	if approvalTaskHasTrueInput(approvalTask, config) {
+		return approvedState
+	}
+	return pendingState
+}
+
 func approvalTaskHasFalseInput(approvalTask v1alpha1.ApprovalTask) bool {
 	for _, approver := range approvalTask.Spec.Approvers {
 		if approver.Input == hasRejected {
 			return true // Found an input that is "reject"
 		}
 	}
+	for _, response := range approvalTask.ExternalResponses() {
+		if response.Response == rejectedState {
+			return true
+		}
+	}
 	return false
 }
 
-func approvalTaskHasTrueInput(approvalTask v1alpha1.ApprovalTask) bool {
+func approvalTaskHasTrueInput(approvalTask v1alpha1.ApprovalTask, config *identity.Config) bool {
 	// Count approvers with input "approve"
 	requiredApprovals := approvalTask.Spec.NumberOfApprovalsRequired
 
@@ -470,22 +620,25 @@ func approvalTaskHasTrueInput(approvalTask v1alpha1.ApprovalTask) bool {
 			continue
 		}
 
-		if v1alpha1.DefaultedApproverType(approver.Type) == "User" {
-			approvedUsers[approver.Name] = true
-		} else if v1alpha1.DefaultedApproverType(approver.Type) == "Group" {
+		if v1alpha1.IsUserApproverType(approver.Type) {
+			if approvalTask.CountsTowardQuorum(approver.Name) {
+				approvedUsers[config.Normalize(approver.Name)] = true
+			}
+		} else if v1alpha1.IsGroupApproverType(approver.Type) {
 			for _, user := range approver.Users {
-				if user.Input == hasApproved {
-					approvedUsers[user.Name] = true
+				if user.Input == hasApproved && approvalTask.CountsTowardQuorum(user.Name) {
+					approvedUsers[config.Normalize(user.Name)] = true
 				}
 			}
 		}
 	}
+	addExternalApprovals(approvalTask, approvedUsers)
 
 	return len(approvedUsers) >= requiredApprovals
 }
 
-func countApprovalsReceived(approvalTask v1alpha1.ApprovalTask) int {
-	// Count unique users who have approved
+func countApprovalsReceived(approvalTask v1alpha1.ApprovalTask, config *identity.Config) int {
+	// Count unique users who have approved, whatever username they approved as
 	approvedUsers := make(map[string]bool)
 
 	for _, approver := range approvalTask.Spec.Approvers {
@@ -493,35 +646,69 @@ func countApprovalsReceived(approvalTask v1alpha1.ApprovalTask) int {
 			continue
 		}
 
-		if v1alpha1.DefaultedApproverType(approver.Type) == "User" {
-			approvedUsers[approver.Name] = true
-		} else if v1alpha1.DefaultedApproverType(approver.Type) == "Group" {
+		if v1alpha1.IsUserApproverType(approver.Type) {
+			if approvalTask.CountsTowardQuorum(approver.Name) {
+				approvedUsers[config.Normalize(approver.Name)] = true
+			}
+		} else if v1alpha1.IsGroupApproverType(approver.Type) {
 			for _, user := range approver.Users {
-				if user.Input == hasApproved {
-					approvedUsers[user.Name] = true
+				if user.Input == hasApproved && approvalTask.CountsTowardQuorum(user.Name) {
+					approvedUsers[config.Normalize(user.Name)] = true
 				}
 			}
 		}
 	}
+	addExternalApprovals(approvalTask, approvedUsers)
 
 	return len(approvedUsers)
 }
 
+// addExternalApprovals counts every approved external approver, such as a
+// change request, as one approval.
+func addExternalApprovals(approvalTask v1alpha1.ApprovalTask, approvedUsers map[string]bool) {
+	for _, response := range approvalTask.ExternalResponses() {
+		if response.Response == approvedState {
+			approvedUsers[response.Type+":"+response.Name] = true
+		}
+	}
+}
+
 func (r *Reconciler) checkIfUpdateRequired(ctx context.Context, approvalTask v1alpha1.ApprovalTask, run *v1beta1.CustomRun) error {
 	logger := logging.FromContext(ctx)
 
-	expectedHash, err := Compute(approvalTask.Spec.Approvers)
+	expectedHash, err := approversHash(approvalTask)
 	if err != nil {
 		logger.Errorf("Unable to compute the hash")
 		return err
 	}
 	lastAppliedHash := approvalTask.GetAnnotations()[LastAppliedHashKey]
 
-	if expectedHash != lastAppliedHash {
+	// A stored state which does not match the spec is fixed
+	if expectedHash != lastAppliedHash ||
+		approvalTask.Status.State != approvalState(approvalTask, identity.FromContext(ctx)) {
+		previousState := approvalTask.Status.State
+		previousResponses := approvalTask.Status.ApproversResponse
 		if _, err := updateApprovalState(ctx, r.approvaltaskClientSet, &approvalTask); err != nil {
 			return err
 		}
 
+		if len(previousResponses) == 0 && len(approvalTask.Status.ApproversResponse) > 0 {
+			recordFirstResponse(ctx, approvalTask.Namespace, firstResponseDecision(approvalTask), r.waitingTime(approvalTask))
+		}
+		for _, a := range newAnswers(previousResponses, approvalTask.Status.ApproversResponse) {
+			r.traceResponse(ctx, approvalTask, a)
+			emitAnswerEvent(ctx, &approvalTask, run, a)
+		}
+		if previousState == pendingState && approvalTask.Status.State != pendingState {
+			recordDecision(ctx, approvalTask.Namespace, approvalTask.Status.State, r.waitingTime(approvalTask))
+			r.traceDecision(ctx, approvalTask, approvalTask.Status.State)
+			if approvalTask.Status.State == approvedState {
+				emitEvent(ctx, &approvalTask, run, corev1.EventTypeNormal, EventReasonQuorumReached,
+					"ApprovalTask %s approved, %d of %d approval(s) received", approvalTask.Name,
+					approvalTask.Status.ApprovalsReceived, approvalTask.Spec.NumberOfApprovalsRequired)
+			}
+		}
+
 		switch approvalTask.Status.State {
 		case pendingState:
 			logger.Infof("Approval task %s is in pending state", approvalTask.Name)
@@ -538,18 +725,134 @@ func (r *Reconciler) checkIfUpdateRequired(ctx context.Context, approvalTask v1a
 	return nil
 }
 
+// answer is the response of one approver, or member of a group approver.
+type answer struct {
+	Approver string
+	Type     string
+	// Group is the group approver the member answered for, if any
+	Group    string
+	Response string
+	Message  string
+}
+
+// newAnswers returns the answers in current which are not in previous, in a
+// stable order.
+func newAnswers(previous, current []v1alpha1.ApproverState) []answer {
+	flatten := func(responses []v1alpha1.ApproverState) []answer {
+		var answers []answer
+		for _, response := range responses {
+			if len(response.GroupMembers) == 0 {
+				answers = append(answers, answer{
+					Approver: response.Name,
+					Type:     response.Type,
+					Response: response.Response,
+					Message:  response.Message,
+				})
+				continue
+			}
+			for _, member := range response.GroupMembers {
+				answers = append(answers, answer{
+					Approver: member.Name,
+					Type:     response.Type,
+					Group:    response.Name,
+					Response: member.Response,
+					Message:  member.Message,
+				})
+			}
+		}
+		return answers
+	}
+
+	known := make(map[answer]bool)
+	for _, a := range flatten(previous) {
+		known[a] = true
+	}
+	var result []answer
+	for _, a := range flatten(current) {
+		if !known[a] {
+			result = append(result, a)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Group != result[j].Group {
+			return result[i].Group < result[j].Group
+		}
+		return result[i].Approver < result[j].Approver
+	})
+	return result
+}
+
+// firstResponseDecision returns rejectedState when one of the first responses
+// rejects the ApprovalTask, approvedState otherwise.
+func firstResponseDecision(approvalTask v1alpha1.ApprovalTask) string {
+	for _, response := range approvalTask.Status.ApproversResponse {
+		if response.Response == rejectedState {
+			return rejectedState
+		}
+	}
+	return approvedState
+}
+
+// waitingTime returns how long the ApprovalTask has been waiting for a decision.
+func (r *Reconciler) waitingTime(approvalTask v1alpha1.ApprovalTask) time.Duration {
+	if approvalTask.Status.StartTime == nil {
+		return 0
+	}
+	return r.clock.Since(approvalTask.Status.StartTime.Time)
+}
+
+// approversHash returns the hash of the approvers, including the responses
+// of external approvers which answer through the status, leaving the spec
+// unchanged.
+func approversHash(approvalTask v1alpha1.ApprovalTask) (string, error) {
+	external := approvalTask.ExternalResponses()
+	if len(external) == 0 {
+		return Compute(approvalTask.Spec.Approvers)
+	}
+	sortResponses(external)
+	return Compute(struct {
+		Approvers []v1alpha1.ApproverDetails
+		External  []v1alpha1.ApproverState
+	}{approvalTask.Spec.Approvers, external})
+}
+
+// approverKey identifies an approver, approvers of different types may share
+// a name.
+type approverKey struct {
+	Type string
+	Name string
+}
+
+// sortResponses sorts the responses by approver type and name, so that the
+// status does not change from one reconcile to the next.
+func sortResponses(responses []v1alpha1.ApproverState) {
+	sort.Slice(responses, func(i, j int) bool {
+		if responses[i].Type != responses[j].Type {
+			return responses[i].Type < responses[j].Type
+		}
+		return responses[i].Name < responses[j].Name
+	})
+}
+
 func updateApprovalState(ctx context.Context, approvaltaskClientSet versioned.Interface, approvalTask *v1alpha1.ApprovalTask) (v1alpha1.ApprovalTask, error) {
+	previousStatus := approvalTask.Status.DeepCopy()
 	// Updating the approvedBy field in the status
 	// Temp map to hold current approvers with approve and reject input
-	currentApprovers := make(map[string]v1alpha1.ApproverState)
+	currentApprovers := make(map[approverKey]v1alpha1.ApproverState)
+	// Responses of external approvers are recorded in the status only, keep them
+	for _, response := range approvalTask.ExternalResponses() {
+		currentApprovers[approverKey{Type: response.Type, Name: response.Name}] = response
+	}
 	approvalTask.Status.ApproversResponse = []v1alpha1.ApproverState{}
 	// Track users who have already been processed as individual approvers
-	// to avoid duplicate entries when they are also group members
+	// to avoid duplicate entries when they are also group members, under
+	// any of their usernames
+	config := identity.FromContext(ctx)
 	processedUserApprovers := make(map[string]bool)
 	
 	// First pass: Process all User type approvers
 	for _, approver := range approvalTask.Spec.Approvers {
-		if (approver.Input == hasApproved || approver.Input == hasRejected) && v1alpha1.DefaultedApproverType(approver.Type) == "User" {
+		if (approver.Input == hasApproved || approver.Input == hasRejected) && v1alpha1.IsUserApproverType(approver.Type) {
 			response := ""
 			if approver.Input == hasApproved {
 				response = approvedState
@@ -557,20 +860,24 @@ func updateApprovalState(ctx context.Context, approvaltaskClientSet versioned.In
 				response = rejectedState
 			}
 			
-			currentApprovers[approver.Name] = v1alpha1.ApproverState{
-				Name:     approver.Name,
-				Type:     "User",
-				Response: response,
-				Message:  approver.Message,
+			currentApprovers[approverKey{Type: v1alpha1.DefaultedApproverType(approver.Type), Name: approver.Name}] = v1alpha1.ApproverState{
+				Name:      approver.Name,
+				Type:      v1alpha1.DefaultedApproverType(approver.Type),
+				Response:  response,
+				Message:   approver.Message,
+				Automated: v1alpha1.IsServiceAccount(approver.Name),
+
+				ImpersonatedBy: approver.ImpersonatedBy,
+				Responder:      approver.Responder.DeepCopy(),
 			}
 			// Mark this user as processed to avoid duplication in group processing
-			processedUserApprovers[approver.Name] = true
+			processedUserApprovers[config.Normalize(approver.Name)] = true
 		}
 	}
 	
-	// Second pass: Process Group type approvers, excluding users already processed as individuals
+	// Second pass: Process group approvers, excluding users already processed as individuals
 	for _, approver := range approvalTask.Spec.Approvers {
-		if (approver.Input == hasApproved || approver.Input == hasRejected) && v1alpha1.DefaultedApproverType(approver.Type) == "Group" {
+		if (approver.Input == hasApproved || approver.Input == hasRejected) && v1alpha1.IsGroupApproverType(approver.Type) {
 			groupMembers := []v1alpha1.GroupMemberState{}
 			groupResponse := ""
 			hasApprovals := false
@@ -579,7 +886,7 @@ func updateApprovalState(ctx context.Context, approvaltaskClientSet versioned.In
 			for _, user := range approver.Users {
 				// Skip users who have already been processed as individual approvers
 				// This prevents duplicate entries when a user is both an individual approver and group member
-				if processedUserApprovers[user.Name] {
+				if processedUserApprovers[config.Normalize(user.Name)] {
 					continue
 				}
 				
@@ -594,9 +901,13 @@ func updateApprovalState(ctx context.Context, approvaltaskClientSet versioned.In
 
 				if userResponse != "" {
 					groupMembers = append(groupMembers, v1alpha1.GroupMemberState{
-						Name:     user.Name,
-						Response: userResponse,
-						Message:  user.Message, // Inherit message from user level
+						Name:      user.Name,
+						Response:  userResponse,
+						Message:   user.Message, // Inherit message from user level
+						Automated: v1alpha1.IsServiceAccount(user.Name),
+
+						ImpersonatedBy: user.ImpersonatedBy,
+						Responder:      user.Responder.DeepCopy(),
 					})
 				}
 			}
@@ -609,9 +920,9 @@ func updateApprovalState(ctx context.Context, approvaltaskClientSet versioned.In
 			}
 
 			if groupResponse != "" {
-				currentApprovers[approver.Name] = v1alpha1.ApproverState{
+				currentApprovers[approverKey{Type: v1alpha1.DefaultedApproverType(approver.Type), Name: approver.Name}] = v1alpha1.ApproverState{
 					Name:         approver.Name,
-					Type:         "Group",
+					Type:         v1alpha1.DefaultedApproverType(approver.Type),
 					Response:     groupResponse,
 					Message:      approver.Message,
 					GroupMembers: groupMembers,
@@ -620,27 +931,29 @@ func updateApprovalState(ctx context.Context, approvaltaskClientSet versioned.In
 		}
 	}
 
-	if len(currentApprovers) != 0 {
+	// The stored state is recomputed even without responses, so that a state
+	// written by anyone but the controller does not stick
+	if len(currentApprovers) != 0 || approvalTask.Status.State != approvalState(*approvalTask, config) {
 		// Filter the ApprovedBy to only include those that are still true
 		filteredApprovedBy := []v1alpha1.ApproverState{}
 		for _, approver := range currentApprovers {
 			filteredApprovedBy = append(filteredApprovedBy, approver)
 		}
+		sortResponses(filteredApprovedBy)
 
 		// Update the ApprovedBy list
 		approvalTask.Status.ApproversResponse = filteredApprovedBy
 
 		// Update the approvals count fields
 		approvalTask.Status.ApprovalsRequired = approvalTask.Spec.NumberOfApprovalsRequired
-		approvalTask.Status.ApprovalsReceived = countApprovalsReceived(*approvalTask)
-
-		// Update the approvalState
-		// Reject scenario: Check if there is one false and if found mark the approvalstate to false
-		// Approve scenario: Check if the input value from the user is true and is equal to the approvalsRequired
-		if approvalTaskHasFalseInput(*approvalTask) {
-			approvalTask.Status.State = rejectedState
-		} else if approvalTaskHasTrueInput(*approvalTask) {
-			approvalTask.Status.State = approvedState
+		approvalTask.Status.ApprovalsReceived = countApprovalsReceived(*approvalTask, config)
+
+		// Update the approvalState from the spec and the external responses
+		approvalTask.Status.State = approvalState(*approvalTask, config)
+
+		// Nothing to write when the status did not change
+		if equality.Semantic.DeepEqual(previousStatus, &approvalTask.Status) {
+			return *approvalTask, nil
 		}
 
 		// Update the status finally
@@ -25,8 +25,10 @@ import (
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
 	approvaltaskv1alpha1 "github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/fake"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
 	"github.com/stretchr/testify/assert"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -105,6 +107,25 @@ func TestPropagateApprovalTaskLabelsAndAnnotations(t *testing.T) {
 	assert.Equal(t, len(run.Labels), 1)
 }
 
+func TestPropagateApprovalTaskResults(t *testing.T) {
+	run := &v1beta1.CustomRun{}
+	run.Status.Results = []v1beta1.CustomRunResult{{Name: "jiraIssueKey", Value: "OLD-1"}}
+
+	approvalTask := &v1alpha1.ApprovalTask{}
+	approvalTask.Status.Annotations = map[string]string{
+		v1alpha1.ResultAnnotationPrefix + "jiraIssueKey":  "OPS-42",
+		v1alpha1.ResultAnnotationPrefix + "changeRequest": "CHG0001",
+		"teams.openshift-pipelines.org/notified":          "pending",
+	}
+
+	propagateApprovalTaskResults(run, approvalTask)
+
+	assert.Equal(t, []v1beta1.CustomRunResult{
+		{Name: "jiraIssueKey", Value: "OPS-42"},
+		{Name: "changeRequest", Value: "CHG0001"},
+	}, run.Status.Results)
+}
+
 func TestCreateApprovalTask(t *testing.T) {
 	run := &v1beta1.CustomRun{
 		ObjectMeta: metav1.ObjectMeta{
@@ -338,6 +359,434 @@ func TestUpdateApprovalTaskTrueState(t *testing.T) {
 	assert.Equal(t, len(at1.Status.ApproversResponse), 2)
 }
 
+func TestUpdateApprovalTaskWithChangeRequest(t *testing.T) {
+	run := &v1beta1.CustomRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bar",
+			Namespace: "foo",
+		},
+		Spec: v1beta1.CustomRunSpec{
+			Params: []v1beta1.Param{
+				{
+					Name:  "approvers",
+					Value: *v1beta1.NewArrayOrString("foo", "changerequest:CHG0030001"),
+				},
+				{
+					Name:  "numberOfApprovalsRequired",
+					Value: *v1beta1.NewArrayOrString("2"),
+				},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		response string
+		want     string
+	}{
+		{response: "approved", want: "approved"},
+		{response: "rejected", want: "rejected"},
+	} {
+		t.Run(tc.response, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+
+			approvalTask, err := createApprovalTask(context.TODO(), client, run)
+			if err != nil {
+				t.Fatalf("createApprovalTask returned an error: %v", err)
+			}
+			assert.Equal(t, v1alpha1.ApproverDetails{Name: "CHG0030001", Input: "pending", Type: v1alpha1.ChangeRequestApproverType}, approvalTask.Spec.Approvers[1])
+
+			// The change request answers through the status only
+			approvalTask.Spec.Approvers[0].Input = "approve"
+			approvalTask.Status.ApproversResponse = []v1alpha1.ApproverState{
+				{Name: "CHG0030001", Type: v1alpha1.ChangeRequestApproverType, Response: tc.response},
+			}
+
+			at, err := updateApprovalState(context.TODO(), client, &approvalTask)
+			if err != nil {
+				t.Fatalf("updateApprovalTask returned an error: %v", err)
+			}
+
+			assert.Equal(t, tc.want, at.Status.State)
+			assert.Equal(t, 2, len(at.Status.ApproversResponse))
+			assert.Contains(t, at.Status.ApproversResponse, v1alpha1.ApproverState{Name: "CHG0030001", Type: v1alpha1.ChangeRequestApproverType, Response: tc.response})
+		})
+	}
+}
+
+func TestUpdateApprovalTaskWithRBACApprover(t *testing.T) {
+	run := &v1beta1.CustomRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bar",
+			Namespace: "foo",
+		},
+		Spec: v1beta1.CustomRunSpec{
+			Params: []v1beta1.Param{
+				{
+					Name:  "approvers",
+					Value: *v1beta1.NewArrayOrString("rbac:", "rbac:release-gate"),
+				},
+				{
+					Name:  "numberOfApprovalsRequired",
+					Value: *v1beta1.NewArrayOrString("1"),
+				},
+			},
+		},
+	}
+
+	client := fake.NewSimpleClientset()
+	approvalTask, err := createApprovalTask(context.TODO(), client, run)
+	if err != nil {
+		t.Fatalf("createApprovalTask returned an error: %v", err)
+	}
+	assert.Equal(t, v1alpha1.ApproverDetails{Name: v1alpha1.AnyApprovalTask, Input: "pending", Type: v1alpha1.RBACApproverType}, approvalTask.Spec.Approvers[0])
+	assert.Equal(t, v1alpha1.ApproverDetails{Name: "release-gate", Input: "pending", Type: v1alpha1.RBACApproverType}, approvalTask.Spec.Approvers[1])
+
+	// A member answers like the member of a group
+	approvalTask.Spec.Approvers[1].Input = "approve"
+	approvalTask.Spec.Approvers[1].Users = []v1alpha1.UserDetails{{Name: "alice", Input: "approve"}}
+
+	at, err := updateApprovalState(context.TODO(), client, &approvalTask)
+	if err != nil {
+		t.Fatalf("updateApprovalTask returned an error: %v", err)
+	}
+
+	assert.Equal(t, "approved", at.Status.State)
+	assert.Equal(t, 1, len(at.Status.ApproversResponse))
+	assert.Equal(t, v1alpha1.RBACApproverType, at.Status.ApproversResponse[0].Type)
+	assert.Equal(t, "release-gate", at.Status.ApproversResponse[0].Name)
+	assert.Equal(t, "alice", at.Status.ApproversResponse[0].GroupMembers[0].Name)
+}
+
+func TestUpdateApprovalTaskWithServiceAccountApprover(t *testing.T) {
+	run := &v1beta1.CustomRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bar",
+			Namespace: "foo",
+		},
+		Spec: v1beta1.CustomRunSpec{
+			Params: []v1beta1.Param{
+				{
+					Name:  "approvers",
+					Value: *v1beta1.NewArrayOrString("serviceaccount:canary:analyzer", "group:release", "alice"),
+				},
+				{
+					Name:  "numberOfApprovalsRequired",
+					Value: *v1beta1.NewArrayOrString("2"),
+				},
+			},
+		},
+	}
+
+	for _, exclude := range []bool{false, true} {
+		client := fake.NewSimpleClientset()
+		ctx := withExcludeAutomatedApprovals(context.TODO(), exclude)
+		approvalTask, err := createApprovalTask(ctx, client, run)
+		if err != nil {
+			t.Fatalf("createApprovalTask returned an error: %v", err)
+		}
+		assert.Equal(t, v1alpha1.ApproverDetails{Name: "system:serviceaccount:canary:analyzer", Input: "pending", Type: v1alpha1.ServiceAccountApproverType}, approvalTask.Spec.Approvers[0])
+		assert.Equal(t, exclude, approvalTask.Status.ExcludeAutomatedApprovals)
+
+		// The ServiceAccount approves, and another one as a member of the group
+		approvalTask.Spec.Approvers[0].Input = "approve"
+		approvalTask.Spec.Approvers[1].Input = "approve"
+		approvalTask.Spec.Approvers[1].Users = []v1alpha1.UserDetails{{Name: "system:serviceaccount:canary:smoke-tests", Input: "approve"}}
+
+		at, err := updateApprovalState(context.TODO(), client, &approvalTask)
+		if err != nil {
+			t.Fatalf("updateApprovalTask returned an error: %v", err)
+		}
+
+		for _, response := range at.Status.ApproversResponse {
+			switch response.Name {
+			case "system:serviceaccount:canary:analyzer":
+				assert.Equal(t, v1alpha1.ServiceAccountApproverType, response.Type)
+				assert.True(t, response.Automated)
+			case "release":
+				assert.False(t, response.Automated)
+				assert.True(t, response.GroupMembers[0].Automated)
+			}
+		}
+		if exclude {
+			assert.Equal(t, "pending", at.Status.State)
+			assert.Equal(t, 0, at.Status.ApprovalsReceived)
+		} else {
+			assert.Equal(t, "approved", at.Status.State)
+			assert.Equal(t, 2, at.Status.ApprovalsReceived)
+		}
+	}
+}
+
+func TestUpdateApprovalTaskWithImpersonatedApprovers(t *testing.T) {
+	run := &v1beta1.CustomRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bar",
+			Namespace: "foo",
+		},
+		Spec: v1beta1.CustomRunSpec{
+			Params: []v1beta1.Param{
+				{
+					Name:  "approvers",
+					Value: *v1beta1.NewArrayOrString("alice", "group:release"),
+				},
+				{
+					Name:  "numberOfApprovalsRequired",
+					Value: *v1beta1.NewArrayOrString("2"),
+				},
+			},
+		},
+	}
+
+	client := fake.NewSimpleClientset()
+	approvalTask, err := createApprovalTask(context.TODO(), client, run)
+	if err != nil {
+		t.Fatalf("createApprovalTask returned an error: %v", err)
+	}
+
+	// An admin approves as alice, and an integration as bob of the group
+	approvalTask.SetApproverInput("alice", nil, "approve", "")
+	approvalTask.SetImpersonatedBy(func(name string) bool { return name == "alice" }, v1alpha1.MemberOfGroups(nil), "admin")
+	approvalTask.SetApproverInput("bob", []string{"release"}, "approve", "")
+	approvalTask.SetImpersonatedBy(func(name string) bool { return name == "bob" }, v1alpha1.MemberOfGroups([]string{"release"}), v1alpha1.ControllerImpersonator)
+
+	at, err := updateApprovalState(context.TODO(), client, &approvalTask)
+	if err != nil {
+		t.Fatalf("updateApprovalTask returned an error: %v", err)
+	}
+
+	assert.Equal(t, "approved", at.Status.State)
+	for _, response := range at.Status.ApproversResponse {
+		switch response.Name {
+		case "alice":
+			assert.Equal(t, "admin", response.ImpersonatedBy)
+		case "release":
+			assert.Equal(t, v1alpha1.ControllerImpersonator, response.GroupMembers[0].ImpersonatedBy)
+		default:
+			t.Errorf("unexpected response of %s", response.Name)
+		}
+	}
+}
+
+func TestUpdateApprovalTaskWithResponders(t *testing.T) {
+	run := &v1beta1.CustomRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bar",
+			Namespace: "foo",
+		},
+		Spec: v1beta1.CustomRunSpec{
+			Params: []v1beta1.Param{
+				{
+					Name:  "approvers",
+					Value: *v1beta1.NewArrayOrString("alice", "group:release"),
+				},
+			},
+		},
+	}
+
+	client := fake.NewSimpleClientset()
+	approvalTask, err := createApprovalTask(context.TODO(), client, run)
+	if err != nil {
+		t.Fatalf("createApprovalTask returned an error: %v", err)
+	}
+
+	// The mutating webhook stamped the response of bob
+	responder := &v1alpha1.Responder{Username: "bob", UID: "42", Groups: []string{"release"}, Time: metav1.Unix(1700000000, 0)}
+	approvalTask.SetApproverInput("bob", []string{"release"}, "approve", "")
+	approvalTask.Spec.Approvers[1].Users[0].Responder = responder
+
+	at, err := updateApprovalState(context.TODO(), client, &approvalTask)
+	if err != nil {
+		t.Fatalf("updateApprovalTask returned an error: %v", err)
+	}
+
+	if len(at.Status.ApproversResponse) != 1 {
+		t.Fatalf("expected 1 response, got %d", len(at.Status.ApproversResponse))
+	}
+	assert.Equal(t, responder, at.Status.ApproversResponse[0].GroupMembers[0].Responder)
+}
+
+func TestUpdateApprovalStateRecomputesState(t *testing.T) {
+	run := &v1beta1.CustomRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bar",
+			Namespace: "foo",
+		},
+		Spec: v1beta1.CustomRunSpec{
+			Params: []v1beta1.Param{
+				{
+					Name:  "approvers",
+					Value: *v1beta1.NewArrayOrString("alice", "bob"),
+				},
+				{
+					Name:  "numberOfApprovalsRequired",
+					Value: *v1beta1.NewArrayOrString("2"),
+				},
+			},
+		},
+	}
+
+	client := fake.NewSimpleClientset()
+	approvalTask, err := createApprovalTask(context.TODO(), client, run)
+	if err != nil {
+		t.Fatalf("createApprovalTask returned an error: %v", err)
+	}
+
+	// A forged state is reset, without responses
+	approvalTask.Status.State = "approved"
+	at, err := updateApprovalState(context.TODO(), client, approvalTask.DeepCopy())
+	if err != nil {
+		t.Fatalf("updateApprovalTask returned an error: %v", err)
+	}
+	assert.Equal(t, "pending", at.Status.State)
+
+	// and with fewer approvals than required
+	approvalTask.Spec.Approvers[0].Input = "approve"
+	at, err = updateApprovalState(context.TODO(), client, approvalTask.DeepCopy())
+	if err != nil {
+		t.Fatalf("updateApprovalTask returned an error: %v", err)
+	}
+	assert.Equal(t, "pending", at.Status.State)
+	assert.Equal(t, 1, at.Status.ApprovalsReceived)
+}
+
+func TestUpdateApprovalStateIsStable(t *testing.T) {
+	run := &v1beta1.CustomRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "bar",
+			Namespace: "foo",
+		},
+		Spec: v1beta1.CustomRunSpec{
+			Params: []v1beta1.Param{
+				{
+					Name:  "approvers",
+					Value: *v1beta1.NewArrayOrString("bob", "alice", "changerequest:CHG0030001"),
+				},
+				{
+					Name:  "numberOfApprovalsRequired",
+					Value: *v1beta1.NewArrayOrString("3"),
+				},
+			},
+		},
+	}
+
+	client := fake.NewSimpleClientset()
+	approvalTask, err := createApprovalTask(context.TODO(), client, run)
+	if err != nil {
+		t.Fatalf("createApprovalTask returned an error: %v", err)
+	}
+	approvalTask.Spec.Approvers[0].Input = "approve"
+	approvalTask.Spec.Approvers[1].Input = "approve"
+	approvalTask.Status.ApproversResponse = []v1alpha1.ApproverState{
+		{Name: "CHG0030001", Type: v1alpha1.ChangeRequestApproverType, Response: "approved"},
+	}
+
+	at, err := updateApprovalState(context.TODO(), client, approvalTask.DeepCopy())
+	if err != nil {
+		t.Fatalf("updateApprovalTask returned an error: %v", err)
+	}
+	var names []string
+	for _, response := range at.Status.ApproversResponse {
+		names = append(names, response.Name)
+	}
+	assert.Equal(t, []string{"CHG0030001", "alice", "bob"}, names)
+
+	// Reconciling the same responses again writes nothing
+	client.ClearActions()
+	for i := 0; i < 10; i++ {
+		again, err := updateApprovalState(context.TODO(), client, at.DeepCopy())
+		if err != nil {
+			t.Fatalf("updateApprovalTask returned an error: %v", err)
+		}
+		assert.Equal(t, at.Status, again.Status)
+	}
+	assert.Empty(t, client.Actions())
+}
+
+func TestApproversHash(t *testing.T) {
+	approvalTask := v1alpha1.ApprovalTask{
+		Spec: v1alpha1.ApprovalTaskSpec{
+			Approvers: []v1alpha1.ApproverDetails{
+				{Name: "alice", Input: "pending", Type: "User"},
+				{Name: "CHG0030001", Input: "pending", Type: v1alpha1.ChangeRequestApproverType},
+				{Name: "CHG0030002", Input: "pending", Type: v1alpha1.ChangeRequestApproverType},
+			},
+		},
+	}
+	specHash, err := Compute(approvalTask.Spec.Approvers)
+	if err != nil {
+		t.Fatalf("Compute returned an error: %v", err)
+	}
+
+	// Without external responses, the hash is the one stored on creation
+	hash, err := approversHash(approvalTask)
+	if err != nil {
+		t.Fatalf("approversHash returned an error: %v", err)
+	}
+	assert.Equal(t, specHash, hash)
+
+	// An external response changes it, whatever the order of the responses
+	approvalTask.Status.ApproversResponse = []v1alpha1.ApproverState{
+		{Name: "CHG0030001", Type: v1alpha1.ChangeRequestApproverType, Response: "approved"},
+		{Name: "alice", Type: "User", Response: "approved"},
+		{Name: "CHG0030002", Type: v1alpha1.ChangeRequestApproverType, Response: "approved"},
+	}
+	hash, err = approversHash(approvalTask)
+	if err != nil {
+		t.Fatalf("approversHash returned an error: %v", err)
+	}
+	assert.NotEqual(t, specHash, hash)
+
+	responses := approvalTask.Status.ApproversResponse
+	responses[0], responses[2] = responses[2], responses[0]
+	reordered, err := approversHash(approvalTask)
+	if err != nil {
+		t.Fatalf("approversHash returned an error: %v", err)
+	}
+	assert.Equal(t, hash, reordered)
+}
+
+func TestUpdateApprovalTaskWithAliases(t *testing.T) {
+	config, err := identity.ParseConfig(map[string]string{"caseInsensitive": "true", "stripEmailDomains": "corp.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, td := range []struct {
+		name     string
+		config   *identity.Config
+		state    string
+		received int
+	}{
+		{name: "usernames compared as they are", state: "approved", received: 2},
+		{name: "usernames normalised", config: config, state: "pending", received: 1},
+	} {
+		t.Run(td.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			approvalTask := &v1alpha1.ApprovalTask{
+				ObjectMeta: metav1.ObjectMeta{Name: "aliases", Namespace: "foo"},
+				Spec: v1alpha1.ApprovalTaskSpec{
+					Approvers: []v1alpha1.ApproverDetails{
+						{Name: "alice", Input: "approve", Type: "User"},
+						{Name: "dev-team", Input: "approve", Type: "Group", Users: []v1alpha1.UserDetails{{Name: "Alice@corp.com", Input: "approve"}}},
+					},
+					NumberOfApprovalsRequired: 2,
+				},
+				Status: v1alpha1.ApprovalTaskStatus{State: "pending"},
+			}
+			if _, err := client.OpenshiftpipelinesV1alpha1().ApprovalTasks("foo").Create(context.TODO(), approvalTask, metav1.CreateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+
+			at, err := updateApprovalState(identity.WithConfig(context.TODO(), td.config), client, approvalTask)
+			if err != nil {
+				t.Fatalf("updateApprovalTask returned an error: %v", err)
+			}
+			assert.Equal(t, td.state, at.Status.State)
+			assert.Equal(t, td.received, at.Status.ApprovalsReceived)
+		})
+	}
+}
+
 func TestUpdateApprovalTaskWithNoApprovalsProvided(t *testing.T) {
 	run := &v1beta1.CustomRun{
 		ObjectMeta: metav1.ObjectMeta{
@@ -443,7 +892,7 @@ func TestApprovalTaskHasTrueInputWithAllApprovals(t *testing.T) {
 		Status: v1alpha1.ApprovalTaskStatus{},
 	}
 
-	got := approvalTaskHasTrueInput(approvaltask)
+	got := approvalTaskHasTrueInput(approvaltask, nil)
 	assert.Equal(t, true, got)
 }
 
@@ -473,7 +922,7 @@ func TestApprovalTaskHasTrueInputWithSomeApprovals(t *testing.T) {
 		Status: v1alpha1.ApprovalTaskStatus{},
 	}
 
-	got := approvalTaskHasTrueInput(approvaltask)
+	got := approvalTaskHasTrueInput(approvaltask, nil)
 	assert.Equal(t, false, got)
 }
 
@@ -507,7 +956,7 @@ func TestApprovalTaskHasTrueInputWithGroup(t *testing.T) {
 		},
 	}
 
-	result := approvalTaskHasTrueInput(approvalTask)
+	result := approvalTaskHasTrueInput(approvalTask, nil)
 	assert.True(t, result, "Should return true when group has 2 approvals and requirement is 2")
 }
 
@@ -618,6 +1067,67 @@ func TestValidateApproverParameter(t *testing.T) {
 			expectError: true,
 			errorMsg:    "approvers[0]: invalid group format 'group:' - group name cannot be empty after 'group:'",
 		},
+		{
+			name:        "valid change request",
+			paramValue:  "changerequest:CHG0030001",
+			paramIndex:  0,
+			expectError: false,
+		},
+		{
+			name:        "new change request",
+			paramValue:  "changerequest:new",
+			paramIndex:  0,
+			expectError: false,
+		},
+		{
+			name:        "empty change request number",
+			paramValue:  "changerequest:",
+			paramIndex:  1,
+			expectError: true,
+			errorMsg:    "approvers[1]: invalid change request format 'changerequest:' - use 'changerequest:<number>' or 'changerequest:new'",
+		},
+		{
+			name:        "rbac approver for any approval task",
+			paramValue:  "rbac:",
+			paramIndex:  0,
+			expectError: false,
+		},
+		{
+			name:        "rbac approver scoped to a resourceName",
+			paramValue:  "rbac:release-gate",
+			paramIndex:  0,
+			expectError: false,
+		},
+		{
+			name:        "rbac approver with an invalid resourceName",
+			paramValue:  "rbac:Release Gate",
+			paramIndex:  2,
+			expectError: true,
+		},
+		{
+			name:        "serviceaccount approver",
+			paramValue:  "serviceaccount:canary:analyzer",
+			paramIndex:  0,
+			expectError: false,
+		},
+		{
+			name:        "serviceaccount approver given by its username",
+			paramValue:  "system:serviceaccount:canary:analyzer",
+			paramIndex:  0,
+			expectError: false,
+		},
+		{
+			name:        "serviceaccount approver without a namespace",
+			paramValue:  "serviceaccount:analyzer",
+			paramIndex:  1,
+			expectError: true,
+		},
+		{
+			name:        "serviceaccount approver with an invalid namespace",
+			paramValue:  "system:serviceaccount:Canary:analyzer",
+			paramIndex:  1,
+			expectError: true,
+		},
 		{
 			name:        "group name with spaces",
 			paramValue:  "group:approver group",
@@ -1127,6 +1637,15 @@ func TestValidateApproversParam(t *testing.T) {
 			expectedCount: 1,
 			expectErrors:  true,
 		},
+		{
+			name: "more than one change request",
+			param: v1beta1.Param{
+				Name:  "approvers",
+				Value: *v1beta1.NewArrayOrString("user1", "changerequest:CHG0030001", "changerequest:new"),
+			},
+			expectedCount: 3,
+			expectErrors:  true,
+		},
 		{
 			name: "object approver",
 			param: v1beta1.Param{
@@ -1370,7 +1889,7 @@ func TestCountApprovalsReceived(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			count := countApprovalsReceived(tt.approvalTask)
+			count := countApprovalsReceived(tt.approvalTask, nil)
 			assert.Equal(t, tt.expectedCount, count, "Approval count should match")
 		})
 	}
@@ -1506,7 +2025,7 @@ func TestGetOrCreateApprovalTask(t *testing.T) {
 		_, err := client.OpenshiftpipelinesV1alpha1().ApprovalTasks("test-ns").Create(ctx, existingTask, metav1.CreateOptions{})
 		assert.NoError(t, err)
 
-		task, err := getOrCreateApprovalTask(ctx, client, run)
+		task, err := getOrCreateApprovalTask(ctx, client, run, false)
 		assert.NoError(t, err)
 		assert.NotNil(t, task)
 		assert.Equal(t, "test-run", task.Name)
@@ -1534,12 +2053,39 @@ func TestGetOrCreateApprovalTask(t *testing.T) {
 		}
 
 		client := fake.NewSimpleClientset()
-		task, err := getOrCreateApprovalTask(ctx, client, run)
+		task, err := getOrCreateApprovalTask(ctx, client, run, false)
 		assert.NoError(t, err)
 		assert.NotNil(t, task)
 		assert.Equal(t, "test-run", task.Name)
 	})
 
+	t.Run("do not recreate a deleted approval task", func(t *testing.T) {
+		run := &v1beta1.CustomRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-run",
+				Namespace: "test-ns",
+			},
+			Spec: v1beta1.CustomRunSpec{
+				CustomRef: &v1beta1.TaskRef{
+					APIVersion: approvaltaskv1alpha1.SchemeGroupVersion.String(),
+					Kind:       approvaltask.ControllerName,
+				},
+				Params: []v1beta1.Param{
+					{
+						Name:  "approvers",
+						Value: *v1beta1.NewArrayOrString("user1"),
+					},
+				},
+			},
+		}
+
+		client := fake.NewSimpleClientset()
+		_, err := getOrCreateApprovalTask(ctx, client, run, true)
+		assert.ErrorIs(t, err, errApprovalTaskDeleted)
+		_, err = client.OpenshiftpipelinesV1alpha1().ApprovalTasks("test-ns").Get(ctx, "test-run", metav1.GetOptions{})
+		assert.True(t, errors.IsNotFound(err))
+	})
+
 	t.Run("handle custom spec", func(t *testing.T) {
 		specJSON := `{"approvers":[{"name":"user1","type":"User"}]}`
 		run := &v1beta1.CustomRun{
@@ -1561,7 +2107,7 @@ func TestGetOrCreateApprovalTask(t *testing.T) {
 		}
 
 		client := fake.NewSimpleClientset()
-		task, err := getOrCreateApprovalTask(ctx, client, run)
+		task, err := getOrCreateApprovalTask(ctx, client, run, false)
 		assert.NoError(t, err)
 		assert.NotNil(t, task)
 		assert.Equal(t, 1, len(task.Spec.Approvers))
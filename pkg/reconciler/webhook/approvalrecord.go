@@ -0,0 +1,66 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+const (
+	// RecordKind is the kind of the archives of the ApprovalTasks
+	RecordKind = "ApprovalRecord"
+)
+
+// admitApprovalRecord only lets the controller create ApprovalRecords, and
+// nobody change their spec, the labels identifying their ApprovalTask or
+// their attestation.
+func (r *reconciler) admitApprovalRecord(_ context.Context, request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	switch request.Operation {
+	case admissionv1.Create:
+		if request.UserInfo.Username != r.controllerUsername {
+			return deny(v1alpha1.DenialReasonPolicyDenied, "ApprovalRecords are only created by the controller")
+		}
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	case admissionv1.Update:
+	default:
+		return deny(v1alpha1.DenialReasonPolicyDenied, "unsupported operation: %s", request.Operation)
+	}
+
+	newObj, err := r.decodeApprovalRecord(request.Object.Raw)
+	if err != nil {
+		return deny(v1alpha1.DenialReasonInvalidInput, "cannot decode incoming new object: %v", err)
+	}
+	oldObj, err := r.decodeApprovalRecord(request.OldObject.Raw)
+	if err != nil {
+		return deny(v1alpha1.DenialReasonInvalidInput, "cannot decode incoming old object: %v", err)
+	}
+
+	if !reflect.DeepEqual(oldObj.Spec, newObj.Spec) {
+		return deny(v1alpha1.DenialReasonFinalState, "ApprovalRecord %s is immutable", request.Name)
+	}
+	for _, key := range []string{v1alpha1.ApprovalTaskLabelKey, v1alpha1.ApprovalRecordDecisionLabelKey} {
+		if oldObj.Labels[key] != newObj.Labels[key] {
+			return deny(v1alpha1.DenialReasonFinalState, "label %s of ApprovalRecord %s is immutable", key, request.Name)
+		}
+	}
+	if key := v1alpha1.ApprovalRecordAttestationAnnotationKey; oldObj.Annotations[key] != newObj.Annotations[key] {
+		return deny(v1alpha1.DenialReasonFinalState, "annotation %s of ApprovalRecord %s is immutable", key, request.Name)
+	}
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+func (r *reconciler) decodeApprovalRecord(raw []byte) (*v1alpha1.ApprovalRecord, error) {
+	var record v1alpha1.ApprovalRecord
+	decoder := json.NewDecoder(bytes.NewBuffer(raw))
+	if r.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(&record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
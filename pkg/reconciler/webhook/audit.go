@@ -0,0 +1,124 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/audit"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline"
+	admissionv1 "k8s.io/api/admission/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/webhook"
+)
+
+// auditFailureMessage is the reason an approval is denied with when it cannot
+// be recorded: an approval which is not in the audit log must not count.
+const auditFailureMessage = "unable to record the approval in the audit log"
+
+// auditDeferredKey marks the admissions of the subresources, which are
+// audited once the update they admit is made, see deferAudit.
+type auditDeferredKey struct{}
+
+// deferAudit returns a context whose admissions are not audited, the caller
+// auditing them once the change is made.
+func deferAudit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, auditDeferredKey{}, true)
+}
+
+// isDryRun returns true for the requests which are not persisted, whose
+// admission has no side effect.
+func isDryRun(request *admissionv1.AdmissionRequest) bool {
+	return request.DryRun != nil && *request.DryRun
+}
+
+// audit records the approval action of the request, if any, in the audit log
+// of the context. An allowed action which cannot be recorded is denied.
+// Dry-run requests, and the ones of a context from deferAudit, are not
+// recorded.
+func (r *reconciler) audit(ctx context.Context, request *admissionv1.AdmissionRequest, response *admissionv1.AdmissionResponse) *admissionv1.AdmissionResponse {
+	logger := audit.FromContext(ctx)
+	if logger == nil || isDryRun(request) || ctx.Value(auditDeferredKey{}) != nil ||
+		request.Operation != admissionv1.Update || request.Kind.Kind != Kind {
+		return response
+	}
+	newObj, err := r.decodeNewObject(request.Object.Raw)
+	if err != nil {
+		return response
+	}
+	oldObj, err := r.decodeOldObject(request.OldObject.Raw)
+	if err != nil {
+		return response
+	}
+	record, ok := approvalAction(oldObj, newObj, identity.FromContext(ctx).Matcher(request.UserInfo.Username))
+	if !ok {
+		return response
+	}
+
+	record.Time = time.Now().UTC()
+	record.User = request.UserInfo.Username
+	record.UID = request.UserInfo.UID
+	record.Groups = request.UserInfo.Groups
+	record.Impersonator = r.impersonator(request.UserInfo)
+	record.ApprovalTask = audit.Reference{
+		Namespace: request.Namespace,
+		Name:      request.Name,
+		UID:       string(oldObj.UID),
+	}
+	record.PipelineRun = oldObj.Labels[pipeline.PipelineRunLabelKey]
+	record.Allowed = response.Allowed
+	if response.Result != nil {
+		record.Reason = response.Result.Message
+	}
+
+	if err := logger.Log(ctx, record); err != nil {
+		logging.FromContext(ctx).Errorw("Failed to record approval in the audit log", "approvaltask", request.Name, "error", err)
+		if response.Allowed {
+			return webhook.MakeErrorStatus(auditFailureMessage)
+		}
+	}
+	return response
+}
+
+// approvalAction returns the record of the input the user gave, either as an
+// approver or as a member of a group approver, listed under one of the
+// usernames isUser returns true for. Changes to the input of a group as a
+// whole are attributed to the user, who must belong to the group for the
+// change to be allowed. The Impersonator of the record is the impersonatedBy
+// of the input.
+func approvalAction(oldObj, newObj *v1alpha1.ApprovalTask, isUser func(string) bool) (audit.Record, bool) {
+	for i, approver := range newObj.Spec.Approvers {
+		var old v1alpha1.ApproverDetails
+		if i < len(oldObj.Spec.Approvers) {
+			old = oldObj.Spec.Approvers[i]
+		}
+		if !v1alpha1.IsGroupApproverType(approver.Type) {
+			if isUser(approver.Name) && (approver.Input != old.Input || approver.Message != old.Message) {
+				return audit.Record{Input: approver.Input, Message: approver.Message, Signature: approver.Signature, Impersonator: approver.ImpersonatedBy}, true
+			}
+			continue
+		}
+		for _, member := range approver.Users {
+			if !isUser(member.Name) {
+				continue
+			}
+			if previous, found := findUser(old.Users, member.Name); !found || previous.Input != member.Input || previous.Message != member.Message {
+				return audit.Record{Input: member.Input, Message: member.Message, Group: approver.Name, Signature: member.Signature, Impersonator: member.ImpersonatedBy}, true
+			}
+		}
+		if approver.Input != old.Input {
+			return audit.Record{Input: approver.Input, Message: approver.Message, Group: approver.Name, Signature: approver.Signature, Impersonator: approver.ImpersonatedBy}, true
+		}
+	}
+	return audit.Record{}, false
+}
+
+func findUser(users []v1alpha1.UserDetails, name string) (v1alpha1.UserDetails, bool) {
+	for _, user := range users {
+		if user.Name == name {
+			return user, true
+		}
+	}
+	return v1alpha1.UserDetails{}, false
+}
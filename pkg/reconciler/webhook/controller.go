@@ -2,12 +2,27 @@ package webhook
 
 import (
 	"context"
+	"os"
 
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	approvaltaskclient "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/client"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/groups"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	mwhinformer "knative.dev/pkg/client/injection/kube/informers/admissionregistration/v1/mutatingwebhookconfiguration"
 	vwhinformer "knative.dev/pkg/client/injection/kube/informers/admissionregistration/v1/validatingwebhookconfiguration"
+	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection/clients/dynamicclient"
 	secretinformer "knative.dev/pkg/injection/clients/namespacedkube/informers/core/v1/secret"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
@@ -15,7 +30,7 @@ import (
 	"knative.dev/pkg/webhook"
 )
 
-func NewAdmissionController(ctx context.Context,
+func NewAdmissionController(ctx context.Context, cmw configmap.Watcher,
 	name, path string,
 	wc func(context.Context) context.Context,
 	disallowUnknownFields bool,
@@ -26,6 +41,29 @@ func NewAdmissionController(ctx context.Context,
 	secretInformer := secretinformer.Get(ctx)
 	options := webhook.GetOptions(ctx)
 
+	logger := logging.FromContext(ctx)
+	groupResolver, err := groups.NewResolver(dynamicclient.Get(ctx), client, system.Namespace(), clock.RealClock{})
+	if err != nil {
+		logger.Errorf("Invalid %s, group members are cached for %s: %v", groups.CacheTTLEnv, groups.DefaultCacheTTL, err)
+	}
+
+	impersonationPolicy, err := parseImpersonationPolicy(os.Getenv(ImpersonationPolicyEnv))
+	if err != nil {
+		logger.Fatalf("Invalid %s: %v", ImpersonationPolicyEnv, err)
+	}
+	controllerServiceAccount := os.Getenv(ControllerServiceAccountEnv)
+	if controllerServiceAccount == "" {
+		controllerServiceAccount = DefaultControllerServiceAccount
+	}
+	trustedImpersonators := splitList(os.Getenv(TrustedImpersonatorsEnv))
+
+	// The configurations are watched rather than read for every request
+	identityStore := identity.NewStore(logger.Named("config-store"))
+	identityStore.WatchConfigs(cmw)
+	authenticationInformers := newAuthenticationInformers(client)
+	authenticationConfigMaps := authenticationInformers.Core().V1().ConfigMaps().Lister().ConfigMaps(authenticationConfigMapNamespace)
+	authenticationInformers.Start(ctx.Done())
+
 	key := types.NamespacedName{
 		Namespace: system.Namespace(),
 		Name:      name,
@@ -47,12 +85,26 @@ func NewAdmissionController(ctx context.Context,
 		disallowUnknownFields: disallowUnknownFields,
 		secretName:            options.SecretName,
 
+		controllerUsername:     v1alpha1.ServiceAccountUsername(system.Namespace(), controllerServiceAccount),
+		requireSignedResponses: os.Getenv(RequireSignedResponsesEnv) == "true",
+
+		impersonationPolicy:    impersonationPolicy,
+		impersonationExtraKeys: splitList(os.Getenv(ImpersonationExtraKeysEnv)),
+		trustedImpersonators:   trustedImpersonators,
+
+		identityStore:            identityStore,
+		authenticationConfigMaps: authenticationConfigMaps,
+
+		approvalTaskClient: approvaltaskclient.Get(ctx),
+		dynamicClient:      dynamicclient.Get(ctx),
+		groupResolver:      groupResolver,
+
 		client:       client,
 		vwhlister:    vwhInformer.Lister(),
 		secretlister: secretInformer.Lister(),
+		recorder:     createRecorder(ctx, "manual-approval-webhook"),
 	}
 
-	logger := logging.FromContext(ctx)
 	cont := controller.NewContext(ctx, c, controller.ControllerOptions{WorkQueueName: "ValidatingWebhook", Logger: logger})
 
 	// Reconcile when the named ValidatingWebhookConfiguration changes.
@@ -75,5 +127,102 @@ func NewAdmissionController(ctx context.Context,
 		logger.Panicf("couldn't register Secret informer event handler: %w", err)
 	}
 
+	// Serve the approve and reject subresources through the APIService.
+	go func() {
+		if err := c.serveSubresources(ctx); err != nil {
+			logger.Errorw("Failed to serve the subresources", zap.Error(err))
+		}
+	}()
+
+	return cont
+}
+
+// NewMutatingAdmissionController returns the controller reconciling the
+// MutatingWebhookConfiguration name, which stamps the ApprovalTasks with the
+// identity of the approvers and defaults their approvers.
+func NewMutatingAdmissionController(ctx context.Context, cmw configmap.Watcher,
+	name, path string,
+	wc func(context.Context) context.Context,
+) *controller.Impl {
+
+	client := kubeclient.Get(ctx)
+	mwhInformer := mwhinformer.Get(ctx)
+	secretInformer := secretinformer.Get(ctx)
+	options := webhook.GetOptions(ctx)
+	logger := logging.FromContext(ctx)
+
+	identityStore := identity.NewStore(logger.Named("config-store"))
+	identityStore.WatchConfigs(cmw)
+
+	key := types.NamespacedName{
+		Namespace: system.Namespace(),
+		Name:      name,
+	}
+
+	c := &mutatingReconciler{
+		LeaderAwareFuncs: pkgreconciler.LeaderAwareFuncs{
+			// Have this reconciler enqueue our singleton whenever it becomes leader.
+			PromoteFunc: func(bkt pkgreconciler.Bucket, enq func(pkgreconciler.Bucket, types.NamespacedName)) error {
+				enq(bkt, key)
+				return nil
+			},
+		},
+
+		key:  key,
+		path: path,
+
+		withContext: wc,
+
+		client:       client,
+		mwhlister:    mwhInformer.Lister(),
+		secretlister: secretInformer.Lister(),
+		secretName:   options.SecretName,
+
+		identityStore: identityStore,
+	}
+
+	cont := controller.NewContext(ctx, c, controller.ControllerOptions{WorkQueueName: "MutatingWebhook", Logger: logger})
+
+	// Reconcile when the named MutatingWebhookConfiguration changes.
+	if _, err := mwhInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterWithName(name),
+		Handler:    controller.HandleAll(cont.Enqueue),
+	}); err != nil {
+		logger.Panicf("couldn't register MutatingWebhookConfiguration informer event handler: %w", err)
+	}
+
+	// Reconcile when the cert bundle changes.
+	if _, err := secretInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterWithNameAndNamespace(system.Namespace(), c.secretName),
+		Handler:    controller.HandleAll(cont.Enqueue),
+	}); err != nil {
+		logger.Panicf("couldn't register Secret informer event handler: %w", err)
+	}
+
 	return cont
 }
+
+// createRecorder returns the event recorder of ctx, or a new one recording
+// the Events of the webhook, e.g. denied approvals, to the API server.
+func createRecorder(ctx context.Context, agentName string) record.EventRecorder {
+	logger := logging.FromContext(ctx)
+
+	recorder := controller.GetEventRecorder(ctx)
+	if recorder == nil {
+		eventBroadcaster := record.NewBroadcaster()
+		watches := []watch.Interface{
+			eventBroadcaster.StartLogging(logger.Named("event-broadcaster").Infof),
+			eventBroadcaster.StartRecordingToSink(
+				&typedcorev1.EventSinkImpl{Interface: kubeclient.Get(ctx).CoreV1().Events("")}),
+		}
+		recorder = eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: agentName})
+		go func() {
+			<-ctx.Done()
+			for _, w := range watches {
+				w.Stop()
+			}
+		}()
+	}
+
+	return recorder
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"slices"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/webhook"
+)
+
+// EventReasonBreakGlassDeletion is the reason of the Events recorded when a
+// pending ApprovalTask is deleted with the break-glass role
+const EventReasonBreakGlassDeletion = "BreakGlassDeletion"
+
+// garbageCollectors are the users deleting ApprovalTasks with their
+// CustomRun or their namespace, the kube-controller-manager when it does not
+// use a ServiceAccount per controller
+var garbageCollectors = []string{
+	v1alpha1.ServiceAccountUsername("kube-system", "generic-garbage-collector"),
+	v1alpha1.ServiceAccountUsername("kube-system", "namespace-controller"),
+	"system:kube-controller-manager",
+}
+
+// admitDelete admits the deletion of an ApprovalTask. A pending ApprovalTask
+// may only be deleted by the controller, by garbage collection or by users
+// granted the DeletePendingVerb, as deleting it would reset the inputs of
+// the approvers or dodge a rejection.
+func (r *reconciler) admitDelete(ctx context.Context, request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	oldObj, err := decodeApprovalTask(request.OldObject.Raw)
+	if err != nil {
+		return webhook.MakeErrorStatus("cannot decode incoming old object: %v", err)
+	}
+	user := request.UserInfo.Username
+	if oldObj.Status.State == "approved" || oldObj.Status.State == "rejected" ||
+		user == r.controllerUsername || slices.Contains(garbageCollectors, user) {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	allowed, err := r.canDeletePending(ctx, request)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Failed to check the break-glass role", "approvaltask", request.Name, "error", err)
+		return webhook.MakeErrorStatus("unable to check the break-glass role")
+	}
+	if !allowed {
+		return deny(v1alpha1.DenialReasonPolicyDenied, "pending %s %s can only be deleted by the controller or with the %s verb", Kind, request.Name, v1alpha1.DeletePendingVerb)
+	}
+	if r.recorder != nil {
+		r.recorder.Eventf(&corev1.ObjectReference{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       Kind,
+			Namespace:  request.Namespace,
+			Name:       request.Name,
+			UID:        oldObj.UID,
+		}, corev1.EventTypeWarning, EventReasonBreakGlassDeletion, "pending %s %s deleted by %q", Kind, request.Name, user)
+	}
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+// canDeletePending runs a SubjectAccessReview checking whether the user of
+// the request is granted the DeletePendingVerb on the ApprovalTask.
+func (r *reconciler) canDeletePending(ctx context.Context, request *admissionv1.AdmissionRequest) (bool, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(request.UserInfo.Extra))
+	for key, value := range request.UserInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	result, err := r.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: request.Namespace,
+				Verb:      v1alpha1.DeletePendingVerb,
+				Group:     v1alpha1.SchemeGroupVersion.Group,
+				Resource:  "approvaltasks",
+				Name:      request.Name,
+			},
+			User:   request.UserInfo.Username,
+			Groups: request.UserInfo.Groups,
+			UID:    request.UserInfo.UID,
+			Extra:  extra,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"net/http"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/webhook"
+)
+
+// denialCodes are the HTTP codes of the denial reasons, so that
+// apierrors.IsForbidden, IsConflict and IsInvalid hold for them too.
+var denialCodes = map[metav1.StatusReason]int32{
+	v1alpha1.DenialReasonNotAnApprover:  http.StatusForbidden,
+	v1alpha1.DenialReasonAlreadyDecided: http.StatusConflict,
+	v1alpha1.DenialReasonFinalState:     http.StatusConflict,
+	v1alpha1.DenialReasonInvalidInput:   http.StatusUnprocessableEntity,
+	v1alpha1.DenialReasonForeignChange:  http.StatusForbidden,
+	v1alpha1.DenialReasonPolicyDenied:   http.StatusForbidden,
+}
+
+// deny returns a response denying the request for reason, one of the
+// v1alpha1 DenialReasons, with the message built from format.
+func deny(reason metav1.StatusReason, format string, a ...interface{}) *admissionv1.AdmissionResponse {
+	response := webhook.MakeErrorStatus(format, a...)
+	response.Result.Reason = reason
+	response.Result.Code = denialCodes[reason]
+	return response
+}
+
+// withDetails names the ApprovalTask of the request in the Status of a
+// denial with a reason, the way the API server does for its own errors.
+func withDetails(request *admissionv1.AdmissionRequest, response *admissionv1.AdmissionResponse) *admissionv1.AdmissionResponse {
+	if response.Allowed || response.Result == nil || response.Result.Reason == "" || response.Result.Details != nil {
+		return response
+	}
+	response.Result.Details = &metav1.StatusDetails{
+		Name:  request.Name,
+		Group: Group,
+		Kind:  request.Resource.Resource,
+	}
+	return response
+}
+
+// denialReason is the reason of a denial for the metrics, Other when it has
+// none, e.g. when the webhook failed to check the change.
+func denialReason(response *admissionv1.AdmissionResponse) string {
+	if response.Result == nil || response.Result.Reason == "" {
+		return "Other"
+	}
+	return string(response.Result.Reason)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+)
+
+const (
+	// ImpersonationPolicyEnv is the environment variable setting what the
+	// webhook does with the responses made through impersonation, one of
+	// allow, record or deny
+	ImpersonationPolicyEnv = "IMPERSONATION_POLICY"
+
+	// ImpersonationExtraKeysEnv is the environment variable listing, comma
+	// separated, the user extras naming the impersonator in addition to
+	// v1alpha1.ImpersonatorExtraKey, e.g. the ones an authenticating proxy
+	// sets
+	ImpersonationExtraKeysEnv = "IMPERSONATION_EXTRA_KEYS"
+
+	// TrustedImpersonatorsEnv is the environment variable listing, comma
+	// separated, the impersonators whose responses are allowed by the deny
+	// policy. The impersonator is named by the caller, so none is trusted by
+	// default: listing one trusts everyone allowed to set the extra to it.
+	TrustedImpersonatorsEnv = "TRUSTED_IMPERSONATORS"
+)
+
+// impersonationPolicy is what the webhook does with the responses made
+// through impersonation. It only applies to the requests naming their
+// impersonator, impersonation is only controlled by the RBAC granting it.
+type impersonationPolicy string
+
+const (
+	// allowImpersonation allows them, recording the impersonator in the
+	// audit log and checking the impersonatedBy the response records, if any
+	allowImpersonation impersonationPolicy = "allow"
+	// recordImpersonation also requires the response to record the
+	// impersonator in impersonatedBy, so that it shows in the status
+	recordImpersonation impersonationPolicy = "record"
+	// denyImpersonation denies them, but for the trusted impersonators.
+	// Impersonators who do not name themselves are not denied
+	denyImpersonation impersonationPolicy = "deny"
+)
+
+// parseImpersonationPolicy returns the policy named value, allow when it is
+// empty.
+func parseImpersonationPolicy(value string) (impersonationPolicy, error) {
+	switch policy := impersonationPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case allowImpersonation, recordImpersonation, denyImpersonation:
+		return policy, nil
+	case "":
+		return allowImpersonation, nil
+	}
+	return "", fmt.Errorf("unknown impersonation policy %q, expected allow, record or deny", value)
+}
+
+// splitList returns the non-empty items of the comma separated value.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// impersonator returns who impersonated the user, from the extras of the
+// user, empty when the request does not name an impersonator. The API server
+// does not tell the webhook about impersonation otherwise, so a request
+// impersonating the user without the extras looks like one of the user.
+func (r *reconciler) impersonator(user authenticationv1.UserInfo) string {
+	for _, key := range append([]string{v1alpha1.ImpersonatorExtraKey}, r.impersonationExtraKeys...) {
+		if values := user.Extra[key]; len(values) > 0 {
+			return strings.Join(values, ",")
+		}
+	}
+	return ""
+}
+
+// hasImpersonationHeaders returns true for the requests carrying
+// Impersonate- headers. The API server handles them and proxies the request
+// as the impersonated user, so they are never meant for the webhook.
+func hasImpersonationHeaders(header http.Header) bool {
+	for key := range header {
+		if strings.HasPrefix(http.CanonicalHeaderKey(key), "Impersonate-") {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyImpersonation applies the impersonation policy to the response the
+// user gives in the request, and checks that impersonatedBy is only set, to
+// the impersonator, on the entries of the user. It returns nil when the
+// response is allowed.
+func (r *reconciler) verifyImpersonation(request *admissionv1.AdmissionRequest, oldObj, newObj *v1alpha1.ApprovalTask, isUser func(string) bool, isMember func(v1alpha1.ApproverDetails) bool) *admissionv1.AdmissionResponse {
+	impersonator := r.impersonator(request.UserInfo)
+	for _, change := range impersonatedByChanges(oldObj, newObj, isUser, isMember) {
+		if !change.own {
+			return deny(v1alpha1.DenialReasonForeignChange, "impersonatedBy can only be set on the response of the user")
+		}
+		if change.value != "" && change.value != impersonator {
+			return deny(v1alpha1.DenialReasonInvalidInput, "impersonatedBy %q does not match the impersonator of the request %q", change.value, impersonator)
+		}
+	}
+
+	action, ok := approvalAction(oldObj, newObj, isUser)
+	if !ok {
+		return nil
+	}
+	if impersonator == "" {
+		if action.Impersonator != "" {
+			return deny(v1alpha1.DenialReasonInvalidInput, "impersonatedBy %q is set but the request is not impersonated", action.Impersonator)
+		}
+		return nil
+	}
+	if action.Impersonator != "" && action.Impersonator != impersonator {
+		return deny(v1alpha1.DenialReasonInvalidInput, "impersonatedBy %q does not match the impersonator of the request %q", action.Impersonator, impersonator)
+	}
+	switch r.impersonationPolicy {
+	case recordImpersonation:
+		if action.Impersonator == "" {
+			return deny(v1alpha1.DenialReasonPolicyDenied, "responses made through impersonation must set impersonatedBy to %q", impersonator)
+		}
+	case denyImpersonation:
+		if !slices.Contains(r.trustedImpersonators, impersonator) {
+			return deny(v1alpha1.DenialReasonPolicyDenied, "responses made through impersonation by %q are not allowed", impersonator)
+		}
+	}
+	return nil
+}
+
+// impersonatedByChange is an approver entry whose impersonatedBy changed to
+// value, own when the entry is one of the user.
+type impersonatedByChange struct {
+	value string
+	own   bool
+}
+
+func impersonatedByChanges(oldObj, newObj *v1alpha1.ApprovalTask, isUser func(string) bool, isMember func(v1alpha1.ApproverDetails) bool) []impersonatedByChange {
+	var changes []impersonatedByChange
+	for i, approver := range newObj.Spec.Approvers {
+		var old v1alpha1.ApproverDetails
+		if i < len(oldObj.Spec.Approvers) {
+			old = oldObj.Spec.Approvers[i]
+		}
+		if !v1alpha1.IsGroupApproverType(approver.Type) {
+			if approver.ImpersonatedBy != old.ImpersonatedBy {
+				changes = append(changes, impersonatedByChange{value: approver.ImpersonatedBy, own: isUser(approver.Name)})
+			}
+			continue
+		}
+		if approver.ImpersonatedBy != old.ImpersonatedBy {
+			changes = append(changes, impersonatedByChange{value: approver.ImpersonatedBy, own: isMember(approver)})
+		}
+		for _, member := range approver.Users {
+			if previous, _ := findUser(old.Users, member.Name); member.ImpersonatedBy != previous.ImpersonatedBy {
+				changes = append(changes, impersonatedByChange{value: member.ImpersonatedBy, own: isUser(member.Name)})
+			}
+		}
+	}
+	return changes
+}
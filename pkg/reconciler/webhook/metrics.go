@@ -0,0 +1,59 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"knative.dev/pkg/observability/attributekey"
+)
+
+const scopeName = "github.com/openshift-pipelines/manual-approval-gate/pkg/reconciler/webhook"
+
+var (
+	// NamespaceAttr is the namespace of the ApprovalTask
+	NamespaceAttr = attributekey.String("namespace")
+	// ReasonAttr is the reason of the denial, one of the v1alpha1
+	// DenialReasons or Other
+	ReasonAttr = attributekey.String("reason")
+)
+
+var deniedCounter metric.Int64Counter
+
+func init() {
+	resetPackageMetrics()
+}
+
+// resetPackageMetrics creates the instruments with the global meter provider,
+// which sharedmain configures from config-observability.
+func resetPackageMetrics() {
+	var err error
+	meter := otel.GetMeterProvider().Meter(scopeName)
+
+	if deniedCounter, err = meter.Int64Counter(
+		"approvaltask.admission.denied",
+		metric.WithDescription("The number of changes to ApprovalTasks denied by the admission webhook."),
+	); err != nil {
+		panic(err)
+	}
+}
+
+func recordDenied(ctx context.Context, namespace, reason string) {
+	deniedCounter.Add(ctx, 1, metric.WithAttributes(NamespaceAttr.With(namespace), ReasonAttr.With(reason)))
+}
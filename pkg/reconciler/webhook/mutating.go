@@ -0,0 +1,306 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
+	"go.uber.org/zap"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	admissionlisters "k8s.io/client-go/listers/admissionregistration/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"knative.dev/pkg/apis/duck"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/kmp"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/ptr"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+	"knative.dev/pkg/webhook"
+	certresources "knative.dev/pkg/webhook/certificates/resources"
+)
+
+// mutatingReconciler reconciles the MutatingWebhookConfiguration of the
+// webhook and admits the ApprovalTasks, stamping the approver entries the
+// request changes with the identity of the user, see stampResponders, and
+// defaulting their type.
+type mutatingReconciler struct {
+	webhook.StatelessAdmissionImpl
+	pkgreconciler.LeaderAwareFuncs
+
+	key  types.NamespacedName
+	path string
+
+	withContext func(context.Context) context.Context
+
+	client       kubernetes.Interface
+	mwhlister    admissionlisters.MutatingWebhookConfigurationLister
+	secretlister corelisters.SecretLister
+	secretName   string
+
+	// self is the username of the webhook, whose updates for the
+	// subresources are stamped already
+	self selfIdentity
+
+	// identityStore keeps the identity configuration the entries of the
+	// user are found with
+	identityStore *identity.Store
+}
+
+var _ controller.Reconciler = (*mutatingReconciler)(nil)
+var _ pkgreconciler.LeaderAware = (*mutatingReconciler)(nil)
+var _ webhook.AdmissionController = (*mutatingReconciler)(nil)
+var _ webhook.StatelessAdmissionController = (*mutatingReconciler)(nil)
+
+// Reconcile implements controller.Reconciler
+func (r *mutatingReconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+
+	if !r.IsLeaderFor(r.key) {
+		return controller.NewSkipKey(key)
+	}
+
+	secret, err := r.secretlister.Secrets(system.Namespace()).Get(r.secretName)
+	if err != nil {
+		logger.Errorw("Error fetching secret", zap.Error(err))
+		return err
+	}
+	caCert, ok := secret.Data[certresources.CACert]
+	if !ok {
+		return fmt.Errorf("secret %q is missing %q key", r.secretName, certresources.CACert)
+	}
+	return r.reconcileMutatingWebhook(ctx, caCert)
+}
+
+func (r *mutatingReconciler) reconcileMutatingWebhook(ctx context.Context, caCert []byte) error {
+	logger := logging.FromContext(ctx)
+	rules := []admissionregistrationv1.RuleWithOperations{{
+		Operations: []admissionregistrationv1.OperationType{
+			admissionregistrationv1.Create,
+			admissionregistrationv1.Update,
+		},
+		Rule: admissionregistrationv1.Rule{
+			APIGroups:   []string{Group},
+			APIVersions: []string{Version},
+			Resources:   []string{"approvaltask", "approvaltasks"},
+		},
+	}}
+
+	configuredWebhook, err := r.mwhlister.Get(r.key.Name)
+	if err != nil {
+		return err
+	}
+
+	webhook := configuredWebhook.DeepCopy()
+	webhook.OwnerReferences = nil
+	for i, wh := range webhook.Webhooks {
+		if wh.Name != webhook.Name {
+			continue
+		}
+		webhook.Webhooks[i].Rules = rules
+		webhook.Webhooks[i].ClientConfig.CABundle = caCert
+		if webhook.Webhooks[i].ClientConfig.Service == nil {
+			return fmt.Errorf("missing service reference for webhook: %s", wh.Name)
+		}
+		webhook.Webhooks[i].ClientConfig.Service.Path = ptr.String(r.Path())
+	}
+
+	if ok, err := kmp.SafeEqual(configuredWebhook, webhook); err != nil {
+		return fmt.Errorf("error diffing webhooks: %w", err)
+	} else if !ok {
+		logger.Info("Updating mutating webhook")
+		mwhclient := r.client.AdmissionregistrationV1().MutatingWebhookConfigurations()
+		if _, err := mwhclient.Update(ctx, webhook, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update webhook: %w", err)
+		}
+	} else {
+		logger.Info("Mutating webhook is valid")
+	}
+	return nil
+}
+
+// Path implements AdmissionController
+func (r *mutatingReconciler) Path() string {
+	return r.path
+}
+
+// Admit implements AdmissionController
+func (r *mutatingReconciler) Admit(ctx context.Context, request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if r.withContext != nil {
+		ctx = r.withContext(ctx)
+	}
+	if request.Kind.Group != Group || request.Kind.Kind != Kind {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	newObj, err := decodeApprovalTask(request.Object.Raw)
+	if err != nil {
+		return webhook.MakeErrorStatus("cannot decode incoming new object: %v", err)
+	}
+	var oldObj *v1alpha1.ApprovalTask
+	if request.Operation == admissionv1.Update {
+		if oldObj, err = decodeApprovalTask(request.OldObject.Raw); err != nil {
+			return webhook.MakeErrorStatus("cannot decode incoming old object: %v", err)
+		}
+	}
+
+	mutated := newObj.DeepCopy()
+	setApproverDefaults(mutated)
+	// The webhook stamped the entries of the user when it served the
+	// subresource
+	if oldObj == nil || request.UserInfo.Username != r.self.username(ctx, r.client) {
+		isUser := r.identityStore.Load().Matcher(request.UserInfo.Username)
+		stampResponders(oldObj, mutated, request.UserInfo, isUser, listedMember(request.UserInfo, isUser), metav1.NewTime(time.Now().UTC().Truncate(time.Second)))
+	}
+
+	patch, err := duck.CreatePatch(newObj, mutated)
+	if err != nil {
+		return webhook.MakeErrorStatus("cannot create patch: %v", err)
+	}
+	if len(patch) == 0 {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+	patchBytes, err := patch.MarshalJSON()
+	if err != nil {
+		return webhook.MakeErrorStatus("cannot marshal patch: %v", err)
+	}
+	patchType := admissionv1.PatchTypeJSONPatch
+	return &admissionv1.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patchBytes,
+		PatchType: &patchType,
+	}
+}
+
+// decodeApprovalTask decodes the ApprovalTask of an admission request.
+func decodeApprovalTask(raw []byte) (*v1alpha1.ApprovalTask, error) {
+	var at v1alpha1.ApprovalTask
+	if err := json.Unmarshal(raw, &at); err != nil {
+		return nil, err
+	}
+	return &at, nil
+}
+
+// setApproverDefaults sets the defaults of the approvers, so that stored
+// ApprovalTasks do not depend on the legacy empty type.
+func setApproverDefaults(at *v1alpha1.ApprovalTask) {
+	for i, approver := range at.Spec.Approvers {
+		at.Spec.Approvers[i].Type = v1alpha1.DefaultedApproverType(approver.Type)
+	}
+}
+
+// stampResponders stamps the approver entries of user, per isUser and
+// isMember, whose input or message changed since oldObj with the identity of
+// user, and sets the stamps of the other entries back to the ones of oldObj,
+// so that a stamp always comes from the API server. The changes to the
+// entries of other approvers are left unstamped for the validating webhook
+// to deny. oldObj is nil on create, where no entry is stamped.
+func stampResponders(oldObj, newObj *v1alpha1.ApprovalTask, user authenticationv1.UserInfo, isUser func(string) bool, isMember func(v1alpha1.ApproverDetails) bool, now metav1.Time) {
+	responder := &v1alpha1.Responder{
+		Username: user.Username,
+		UID:      user.UID,
+		Groups:   user.Groups,
+		Time:     now,
+	}
+	for i, approver := range newObj.Spec.Approvers {
+		var old v1alpha1.ApproverDetails
+		if oldObj != nil && i < len(oldObj.Spec.Approvers) {
+			old = oldObj.Spec.Approvers[i]
+		}
+		own := isUser(approver.Name)
+		if v1alpha1.IsGroupApproverType(approver.Type) {
+			own = isMember(approver)
+		}
+		if oldObj != nil && own && (approver.Input != old.Input || approver.Message != old.Message) {
+			newObj.Spec.Approvers[i].Responder = responder.DeepCopy()
+		} else {
+			newObj.Spec.Approvers[i].Responder = old.Responder.DeepCopy()
+		}
+		for j, member := range approver.Users {
+			previous, _ := findUser(old.Users, member.Name)
+			if oldObj != nil && isUser(member.Name) && (member.Input != previous.Input || member.Message != previous.Message) {
+				newObj.Spec.Approvers[i].Users[j].Responder = responder.DeepCopy()
+			} else {
+				newObj.Spec.Approvers[i].Users[j].Responder = previous.Responder.DeepCopy()
+			}
+		}
+	}
+}
+
+// listedMember returns true for the group approvers user is a member of per
+// their groups, or is listed in the members of. The mutating webhook does not
+// resolve the members of the groups: a member it does not know is not
+// stamped, and their change is denied by the validating webhook.
+func listedMember(user authenticationv1.UserInfo, isUser func(string) bool) func(v1alpha1.ApproverDetails) bool {
+	return func(approver v1alpha1.ApproverDetails) bool {
+		return slices.Contains(user.Groups, approver.Name) || slices.ContainsFunc(approver.Users, func(member v1alpha1.UserDetails) bool {
+			return isUser(member.Name)
+		})
+	}
+}
+
+// responderSkew bounds how far the time of a stamp may be from the time it
+// is verified, the time between the mutating and the validating webhooks and
+// the skew between their clocks.
+const responderSkew = time.Minute
+
+// verifyResponders checks that the stamps which changed since oldObj are the
+// ones of the user, i.e. were set by stampResponders, so that they cannot be
+// forged when the mutating webhook is bypassed: their username, UID and
+// groups are the ones of user, and their time is within responderSkew of now.
+// It returns nil when they are.
+func verifyResponders(oldObj, newObj *v1alpha1.ApprovalTask, user authenticationv1.UserInfo, now time.Time) *admissionv1.AdmissionResponse {
+	verify := func(old, responder *v1alpha1.Responder) bool {
+		if equality.Semantic.DeepEqual(old, responder) {
+			return true
+		}
+		return responder != nil &&
+			responder.Username == user.Username &&
+			responder.UID == user.UID &&
+			slices.Equal(responder.Groups, user.Groups) &&
+			!responder.Time.Time.Before(now.Add(-responderSkew)) &&
+			!responder.Time.Time.After(now.Add(responderSkew))
+	}
+	for i, approver := range newObj.Spec.Approvers {
+		var old v1alpha1.ApproverDetails
+		if i < len(oldObj.Spec.Approvers) {
+			old = oldObj.Spec.Approvers[i]
+		}
+		if !verify(old.Responder, approver.Responder) {
+			return deny(v1alpha1.DenialReasonInvalidInput, "responder of %s can only be set by the webhook", approver.Name)
+		}
+		for _, member := range approver.Users {
+			previous, _ := findUser(old.Users, member.Name)
+			if !verify(previous.Responder, member.Responder) {
+				return deny(v1alpha1.DenialReasonInvalidInput, "responder of %s can only be set by the webhook", member.Name)
+			}
+		}
+	}
+	return nil
+}
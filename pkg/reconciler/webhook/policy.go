@@ -0,0 +1,68 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/policy"
+	admissionv1 "k8s.io/api/admission/v1"
+	"knative.dev/pkg/logging"
+)
+
+// authorize asks the external policy, if any, about an update of an
+// ApprovalTask by a user the built-in checks allowed. Both have to allow it.
+// The creations, deletions and the changes of the system users are left to
+// the built-in checks, so that a policy outage never blocks the controller,
+// the garbage collection or the webhook.
+func (r *reconciler) authorize(ctx context.Context, request *admissionv1.AdmissionRequest, response *admissionv1.AdmissionResponse) *admissionv1.AdmissionResponse {
+	hook := policy.FromContext(ctx)
+	if hook == nil || !response.Allowed || !r.isUserApprovalUpdate(ctx, request) {
+		return response
+	}
+	decision, err := hook.Decide(ctx, policy.Input{
+		Operation: string(request.Operation),
+		Namespace: request.Namespace,
+		Name:      request.Name,
+		UserInfo:  request.UserInfo,
+		Object:    json.RawMessage(request.Object.Raw),
+		OldObject: json.RawMessage(request.OldObject.Raw),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Failed to get the policy decision", "approvaltask", request.Name, "namespace", request.Namespace, "failurePolicy", hook.FailurePolicy, "error", err)
+	}
+	if decision.Allow {
+		return response
+	}
+	if decision.Reason == "" {
+		return deny(v1alpha1.DenialReasonPolicyDenied, "denied by policy")
+	}
+	return deny(v1alpha1.DenialReasonPolicyDenied, "denied by policy: %s", decision.Reason)
+}
+
+// isUserApprovalUpdate returns true for the updates of ApprovalTasks made by
+// other users than the controller, the garbage collectors and the webhook.
+func (r *reconciler) isUserApprovalUpdate(ctx context.Context, request *admissionv1.AdmissionRequest) bool {
+	if request.Operation != admissionv1.Update || request.Kind.Group != Group || request.Kind.Kind != Kind {
+		return false
+	}
+	user := request.UserInfo.Username
+	return user != r.controllerUsername && !slices.Contains(garbageCollectors, user) && user != r.identity(ctx)
+}
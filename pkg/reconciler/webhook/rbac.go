@@ -0,0 +1,122 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/groups"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
+	admissionv1 "k8s.io/api/admission/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// groupMembership returns a function telling whether the user of the request
+// is a member of a group approver of the ApprovalTask. The user is a member
+// of a Group approver when they belong to the group, were listed in its
+// users or are a member of the group in the directories of the groupResolver,
+// and of an RBAC approver when a SubjectAccessReview grants them the approve
+// verb on the ApprovalTask. Users and members are compared with the identity
+// configuration of the context. The directories and the SubjectAccessReviews
+// are only asked about users who are not a User approver themselves.
+func (r *reconciler) groupMembership(ctx context.Context, request *admissionv1.AdmissionRequest, approvers []v1alpha1.ApproverDetails) (func(v1alpha1.ApproverDetails) bool, error) {
+	isUser := identity.FromContext(ctx).Matcher(request.UserInfo.Username)
+	allowed := make(map[string]bool)
+	resolved := make(map[string]bool)
+	direct := slices.ContainsFunc(approvers, func(approver v1alpha1.ApproverDetails) bool {
+		return v1alpha1.IsUserApproverType(approver.Type) && isUser(approver.Name)
+	})
+	for _, approver := range approvers {
+		if direct {
+			break
+		}
+		switch v1alpha1.DefaultedApproverType(approver.Type) {
+		case "Group":
+			if _, checked := resolved[approver.Name]; checked || r.groupResolver == nil || slices.Contains(request.UserInfo.Groups, approver.Name) {
+				continue
+			}
+			ok, err := groups.IsMember(ctx, r.groupResolver, approver.Name, isUser)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve the members of group %q: %w", approver.Name, err)
+			}
+			resolved[approver.Name] = ok
+		case v1alpha1.RBACApproverType:
+			if _, checked := allowed[approver.Name]; checked {
+				continue
+			}
+			ok, err := r.canApprove(ctx, request, approver.Name)
+			if err != nil {
+				return nil, err
+			}
+			allowed[approver.Name] = ok
+		}
+	}
+
+	return func(approver v1alpha1.ApproverDetails) bool {
+		switch v1alpha1.DefaultedApproverType(approver.Type) {
+		case "Group":
+			if slices.Contains(request.UserInfo.Groups, approver.Name) || resolved[approver.Name] {
+				return true
+			}
+			for _, user := range approver.Users {
+				if isUser(user.Name) {
+					return true
+				}
+			}
+		case v1alpha1.RBACApproverType:
+			return allowed[approver.Name]
+		}
+		return false
+	}, nil
+}
+
+// canApprove runs a SubjectAccessReview checking whether the user of the
+// request may approve ApprovalTasks of the namespace, restricted to the
+// resourceName of the RBAC approver unless it is AnyApprovalTask.
+func (r *reconciler) canApprove(ctx context.Context, request *admissionv1.AdmissionRequest, resourceName string) (bool, error) {
+	if resourceName == v1alpha1.AnyApprovalTask {
+		resourceName = ""
+	}
+	extra := make(map[string]authorizationv1.ExtraValue, len(request.UserInfo.Extra))
+	for key, value := range request.UserInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: request.Namespace,
+				Verb:      v1alpha1.ApproveVerb,
+				Group:     v1alpha1.SchemeGroupVersion.Group,
+				Resource:  "approvaltasks",
+				Name:      resourceName,
+			},
+			User:   request.UserInfo.Username,
+			Groups: request.UserInfo.Groups,
+			UID:    request.UserInfo.UID,
+			Extra:  extra,
+		},
+	}
+	result, err := r.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to review the access of %q: %w", request.UserInfo.Username, err)
+	}
+	return result.Status.Allowed, nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// authenticationConfigMap is the ConfigMap in which the API server
+	// publishes how it authenticates the requests it proxies to aggregated
+	// API servers
+	authenticationConfigMap          = "extension-apiserver-authentication"
+	authenticationConfigMapNamespace = "kube-system"
+)
+
+// newAuthenticationInformers returns the informers of the ConfigMap the API
+// server publishes its requestheader configuration in, the only one of its
+// namespace the webhook may read.
+func newAuthenticationInformers(client kubernetes.Interface) informers.SharedInformerFactory {
+	return informers.NewSharedInformerFactoryWithOptions(client, 0,
+		informers.WithNamespace(authenticationConfigMapNamespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("metadata.name", authenticationConfigMap).String()
+		}))
+}
+
+// requestHeader is the requestheader configuration of the API server, i.e.
+// the CA and names of its proxy client certificates and the headers carrying
+// the user it proxies the request for.
+type requestHeader struct {
+	clientCAs     *x509.CertPool
+	allowedNames  []string
+	usernameKeys  []string
+	uidKeys       []string
+	groupKeys     []string
+	extraPrefixes []string
+}
+
+// requestHeaderConfig reads the requestheader configuration of the API
+// server from the informer, so that rotated CAs are picked up.
+func (r *reconciler) requestHeaderConfig() (*requestHeader, error) {
+	cm, err := r.authenticationConfigMaps.Get(authenticationConfigMap)
+	if err != nil {
+		return nil, err
+	}
+	ca := cm.Data["requestheader-client-ca-file"]
+	if ca == "" {
+		return nil, fmt.Errorf("%s/%s has no requestheader-client-ca-file", authenticationConfigMapNamespace, authenticationConfigMap)
+	}
+	config := &requestHeader{clientCAs: x509.NewCertPool()}
+	if !config.clientCAs.AppendCertsFromPEM([]byte(ca)) {
+		return nil, fmt.Errorf("%s/%s has no valid requestheader-client-ca-file", authenticationConfigMapNamespace, authenticationConfigMap)
+	}
+	for key, list := range map[string]*[]string{
+		"requestheader-allowed-names":        &config.allowedNames,
+		"requestheader-username-headers":     &config.usernameKeys,
+		"requestheader-uid-headers":          &config.uidKeys,
+		"requestheader-group-headers":        &config.groupKeys,
+		"requestheader-extra-headers-prefix": &config.extraPrefixes,
+	} {
+		if value := cm.Data[key]; value != "" {
+			if err := json.Unmarshal([]byte(value), list); err != nil {
+				return nil, fmt.Errorf("invalid %s in %s/%s: %w", key, authenticationConfigMapNamespace, authenticationConfigMap, err)
+			}
+		}
+	}
+	return config, nil
+}
+
+// authenticate returns the user the API server proxied the request for,
+// after checking that the request comes from the API server, i.e. that its
+// client certificate is signed by the requestheader CA.
+func (h *requestHeader) authenticate(req *http.Request) (authenticationv1.UserInfo, error) {
+	if req.TLS == nil || len(req.TLS.PeerCertificates) == 0 {
+		return authenticationv1.UserInfo{}, fmt.Errorf("no client certificate")
+	}
+	cert := req.TLS.PeerCertificates[0]
+	intermediates := x509.NewCertPool()
+	for _, c := range req.TLS.PeerCertificates[1:] {
+		intermediates.AddCert(c)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         h.clientCAs,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return authenticationv1.UserInfo{}, fmt.Errorf("invalid client certificate: %w", err)
+	}
+	if len(h.allowedNames) > 0 && !slices.Contains(h.allowedNames, cert.Subject.CommonName) {
+		return authenticationv1.UserInfo{}, fmt.Errorf("client certificate %q is not allowed", cert.Subject.CommonName)
+	}
+
+	user := authenticationv1.UserInfo{
+		Username: firstHeader(req.Header, h.usernameKeys),
+		UID:      firstHeader(req.Header, h.uidKeys),
+	}
+	if user.Username == "" {
+		return authenticationv1.UserInfo{}, fmt.Errorf("no user in the request")
+	}
+	for _, key := range h.groupKeys {
+		user.Groups = append(user.Groups, req.Header.Values(key)...)
+	}
+	for key, values := range req.Header {
+		for _, prefix := range h.extraPrefixes {
+			if !strings.HasPrefix(strings.ToLower(key), strings.ToLower(prefix)) {
+				continue
+			}
+			extraKey, err := url.PathUnescape(strings.ToLower(key[len(prefix):]))
+			if err != nil {
+				continue
+			}
+			if user.Extra == nil {
+				user.Extra = map[string]authenticationv1.ExtraValue{}
+			}
+			user.Extra[extraKey] = append(user.Extra[extraKey], values...)
+		}
+	}
+	return user, nil
+}
+
+func firstHeader(header http.Header, keys []string) string {
+	for _, key := range keys {
+		if value := header.Get(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/signature"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+	"knative.dev/pkg/webhook"
+)
+
+const (
+	// ApproverKeysConfigMap is the ConfigMap, in the namespace of the
+	// webhook, holding the public keys of the approvers
+	ApproverKeysConfigMap = "manual-approval-gate-approver-keys"
+
+	// RequireSignedResponsesEnv is the environment variable which, when
+	// true, requires every approver to sign their response
+	RequireSignedResponsesEnv = "REQUIRE_SIGNED_RESPONSES"
+)
+
+// verifySignature checks the signature of the response the user gives in the
+// request. A response must be signed when signatures are required or when
+// the user registered a key, under any of their usernames, and a signature
+// given must always be valid. It returns nil when the response is allowed.
+func (r *reconciler) verifySignature(ctx context.Context, request *admissionv1.AdmissionRequest, oldObj, newObj *v1alpha1.ApprovalTask) *admissionv1.AdmissionResponse {
+	user := request.UserInfo.Username
+	isUser := identity.FromContext(ctx).Matcher(user)
+	action, ok := approvalAction(oldObj, newObj, isUser)
+	if !ok {
+		return nil
+	}
+
+	keys, err := r.approverKeys(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Errorw("Failed to read the keys of the approvers", "configmap", ApproverKeysConfigMap, "error", err)
+		return webhook.MakeErrorStatus("unable to read the keys of the approvers")
+	}
+	userKeys := keysOf(keys, isUser)
+	if !r.requireSignedResponses && len(userKeys) == 0 && action.Signature == "" {
+		return nil
+	}
+
+	response := signature.Response{
+		Namespace: request.Namespace,
+		Name:      request.Name,
+		UID:       string(oldObj.UID),
+		User:      user,
+		Input:     action.Input,
+		Message:   action.Message,
+	}
+	if err := signature.Verify(response, action.Signature, userKeys); err != nil {
+		return deny(v1alpha1.DenialReasonInvalidInput, "invalid signature: %v", err)
+	}
+	return nil
+}
+
+// keysOf returns the keys registered for the usernames isUser returns true
+// for.
+func keysOf(keys signature.Keys, isUser func(string) bool) []string {
+	var userKeys []string
+	for username, registered := range keys {
+		if isUser(username) {
+			userKeys = append(userKeys, registered...)
+		}
+	}
+	return userKeys
+}
+
+// approverKeys reads the public keys of the approvers, none when the
+// ConfigMap does not exist.
+func (r *reconciler) approverKeys(ctx context.Context) (signature.Keys, error) {
+	cm, err := r.client.CoreV1().ConfigMaps(system.Namespace()).Get(ctx, ApproverKeysConfigMap, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return signature.Keys{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return signature.ParseKeys(cm.Data)
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+)
+
+const (
+	// ControllerServiceAccountEnv is the environment variable naming the
+	// ServiceAccount of the controller, in the namespace of the webhook,
+	// which alone may update the status of the ApprovalTasks
+	ControllerServiceAccountEnv = "CONTROLLER_SERVICE_ACCOUNT"
+
+	// DefaultControllerServiceAccount is the ServiceAccount of the controller
+	// when ControllerServiceAccountEnv is not set
+	DefaultControllerServiceAccount = "manual-approval-gate-controller"
+)
+
+// admitStatus admits the updates of the status subresource of the
+// ApprovalTasks, which only the controller may make: anyone else could set
+// the state of an ApprovalTask, or the responses of its external approvers.
+func (r *reconciler) admitStatus(request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	if request.UserInfo.Username != r.controllerUsername {
+		return deny(v1alpha1.DenialReasonForeignChange, "the status of %s can only be updated by the controller", Kind)
+	}
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
@@ -0,0 +1,501 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+	certresources "knative.dev/pkg/webhook/certificates/resources"
+)
+
+const (
+	// SubresourceGroup is the API group of the approve and reject
+	// subresources of the ApprovalTasks, which the webhook serves as an
+	// aggregated API server
+	SubresourceGroup = v1alpha1.SubresourceGroupName
+
+	// APIServiceName is the APIService registering SubresourceGroup
+	APIServiceName = Version + "." + SubresourceGroup
+
+	// SubresourcesPortEnv is the environment variable setting the port the
+	// subresources are served on
+	SubresourcesPortEnv = "SUBRESOURCES_PORT"
+
+	// DefaultSubresourcesPort is the port the subresources are served on
+	// when SubresourcesPortEnv is not set
+	DefaultSubresourcesPort = "8444"
+
+	// maxSubresourceBody is the size limit of the body of a subresource request
+	maxSubresourceBody = 1 << 20
+)
+
+// subresources maps the subresources to the input they set
+var subresources = map[string]string{
+	"approve": "approve",
+	"reject":  "reject",
+}
+
+// serveSubresources serves the approve and reject subresources until ctx is
+// done. The API server proxies the requests through the APIService, with the
+// certificate of the webhook.
+func (r *reconciler) serveSubresources(ctx context.Context) error {
+	port := os.Getenv(SubresourcesPortEnv)
+	if port == "" {
+		port = DefaultSubresourcesPort
+	}
+
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           http.HandlerFunc(r.serveSubresourceHTTP),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(_ net.Listener) context.Context { return ctx },
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			// The client certificate is verified against the requestheader
+			// CA of the API server when the request is served
+			ClientAuth: tls.RequestClientCert,
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				secret, err := r.secretlister.Secrets(system.Namespace()).Get(r.secretName)
+				if err != nil {
+					return nil, err
+				}
+				cert, err := tls.X509KeyPair(secret.Data[certresources.ServerCert], secret.Data[certresources.ServerKey])
+				if err != nil {
+					return nil, err
+				}
+				return &cert, nil
+			},
+		},
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logging.FromContext(ctx).Errorf("Error shutting down subresource server: %v", err)
+		}
+	}()
+
+	if err := server.ListenAndServeTLS("", ""); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// serveSubresourceHTTP serves the discovery of SubresourceGroup and the
+// requests to the subresources, i.e.
+// POST /apis/<group>/<version>/namespaces/<namespace>/approvaltasks/<name>/{approve,reject}
+func (r *reconciler) serveSubresourceHTTP(w http.ResponseWriter, req *http.Request) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "apis":
+		writeJSON(w, http.StatusOK, &metav1.APIGroupList{
+			TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"},
+			Groups:   []metav1.APIGroup{subresourceAPIGroup()},
+		})
+	case len(parts) == 2 && parts[0] == "apis" && parts[1] == SubresourceGroup:
+		group := subresourceAPIGroup()
+		group.TypeMeta = metav1.TypeMeta{Kind: "APIGroup", APIVersion: "v1"}
+		writeJSON(w, http.StatusOK, &group)
+	case len(parts) == 3 && parts[0] == "apis" && parts[1] == SubresourceGroup && parts[2] == Version:
+		list := &metav1.APIResourceList{
+			TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+			GroupVersion: SubresourceGroup + "/" + Version,
+		}
+		for _, name := range []string{"approve", "reject"} {
+			list.APIResources = append(list.APIResources, metav1.APIResource{
+				Name:       "approvaltasks/" + name,
+				Namespaced: true,
+				Kind:       Kind,
+				Verbs:      metav1.Verbs{"create"},
+			})
+		}
+		writeJSON(w, http.StatusOK, list)
+	case len(parts) == 8 && parts[0] == "apis" && parts[1] == SubresourceGroup && parts[2] == Version &&
+		parts[3] == "namespaces" && parts[5] == "approvaltasks" && subresources[parts[7]] != "":
+		if req.Method != http.MethodPost {
+			writeStatus(w, apierrors.NewMethodNotSupported(schema.GroupResource{Group: SubresourceGroup, Resource: "approvaltasks/" + parts[7]}, req.Method))
+			return
+		}
+		r.respond(req.Context(), w, req, parts[4], parts[6], parts[7])
+	default:
+		writeStatus(w, apierrors.NewNotFound(schema.GroupResource{Group: SubresourceGroup}, req.URL.Path))
+	}
+}
+
+func subresourceAPIGroup() metav1.APIGroup {
+	version := metav1.GroupVersionForDiscovery{GroupVersion: SubresourceGroup + "/" + Version, Version: Version}
+	return metav1.APIGroup{
+		Name:             SubresourceGroup,
+		Versions:         []metav1.GroupVersionForDiscovery{version},
+		PreferredVersion: version,
+	}
+}
+
+// respond records the response of the user the API server proxied the
+// request for. The change is built from the identity of the user rather than
+// taken from them, is admitted like an update of the user, and is then made
+// by the webhook itself, so that users need no update on approvaltasks.
+func (r *reconciler) respond(ctx context.Context, w http.ResponseWriter, req *http.Request, namespace, name, subresource string) {
+	logger := logging.FromContext(ctx).With("approvaltask", namespace+"/"+name, "subresource", subresource)
+	resource := schema.GroupResource{Group: SubresourceGroup, Resource: "approvaltasks/" + subresource}
+
+	requestHeader, err := r.requestHeaderConfig()
+	if err != nil {
+		logger.Errorw("Failed to read the requestheader configuration", "error", err)
+		writeStatus(w, apierrors.NewInternalError(fmt.Errorf("unable to authenticate the request")))
+		return
+	}
+	user, err := requestHeader.authenticate(req)
+	if err != nil {
+		writeStatus(w, apierrors.NewUnauthorized(err.Error()))
+		return
+	}
+	if hasImpersonationHeaders(req.Header) {
+		writeStatus(w, apierrors.NewBadRequest("impersonation headers are handled by the API server, not by the subresources"))
+		return
+	}
+	if allowed, err := r.canRespond(ctx, user, namespace, name, subresource); err != nil {
+		logger.Errorw("Failed to authorize the request", "user", user.Username, "error", err)
+		writeStatus(w, apierrors.NewInternalError(fmt.Errorf("unable to authorize the request")))
+		return
+	} else if !allowed {
+		writeStatus(w, apierrors.NewForbidden(resource, name, fmt.Errorf("user %q cannot create %s", user.Username, resource)))
+		return
+	}
+
+	var body v1alpha1.SubresourceRequest
+	if err := json.NewDecoder(io.LimitReader(req.Body, maxSubresourceBody)).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+		writeStatus(w, apierrors.NewBadRequest(fmt.Sprintf("invalid body: %v", err)))
+		return
+	}
+
+	approvalTasks := r.approvalTaskClient.OpenshiftpipelinesV1alpha1().ApprovalTasks(namespace)
+	oldObj, err := approvalTasks.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		writeStatus(w, err)
+		return
+	}
+
+	ctx = identity.WithConfig(ctx, r.identityStore.Load())
+
+	request := &admissionv1.AdmissionRequest{
+		UID:       uuid.NewUUID(),
+		Kind:      metav1.GroupVersionKind{Group: Group, Version: Version, Kind: Kind},
+		Resource:  metav1.GroupVersionResource{Group: Group, Version: Version, Resource: "approvaltasks"},
+		Name:      name,
+		Namespace: namespace,
+		Operation: admissionv1.Update,
+		UserInfo:  user,
+	}
+	isMember, err := r.groupMembership(ctx, request, oldObj.Spec.Approvers)
+	if err != nil {
+		logger.Errorw("Failed to check the group approvers", "error", err)
+		writeStatus(w, apierrors.NewInternalError(fmt.Errorf("unable to check the group approvers")))
+		return
+	}
+	isUser := identity.FromContext(ctx).Matcher(user.Username)
+	newObj := oldObj.DeepCopy()
+	newObj.SetApproverInputMatching(user.Username, isUser, isMember, subresources[subresource], body.Message)
+	if body.Signature != "" {
+		setSignature(newObj, isUser, isMember, body.Signature)
+	}
+	if impersonator := r.impersonator(user); impersonator != "" {
+		newObj.SetImpersonatedBy(isUser, isMember, impersonator)
+	}
+	// The mutating webhook leaves the updates of the webhook as they are
+	stampResponders(oldObj, newObj, user, isUser, isMember, metav1.NewTime(time.Now().UTC().Truncate(time.Second)))
+
+	if request.OldObject.Raw, err = json.Marshal(oldObj); err != nil {
+		writeStatus(w, apierrors.NewInternalError(err))
+		return
+	}
+	if request.Object.Raw, err = json.Marshal(newObj); err != nil {
+		writeStatus(w, apierrors.NewInternalError(err))
+		return
+	}
+	// The response is audited once it is made, or denied
+	response := r.Admit(deferAudit(ctx), request)
+	auditCtx := ctx
+	if r.withContext != nil {
+		auditCtx = r.withContext(ctx)
+	}
+	if !response.Allowed {
+		r.audit(auditCtx, request, response)
+		message := "denied"
+		if response.Result != nil {
+			message = response.Result.Message
+		}
+		denied := apierrors.NewForbidden(resource, name, errors.New(message))
+		// Keep the reason of the denial, so that clients can react to it
+		if response.Result != nil && response.Result.Reason != "" {
+			denied.ErrStatus.Reason = response.Result.Reason
+			denied.ErrStatus.Code = response.Result.Code
+		}
+		writeStatus(w, denied)
+		return
+	}
+
+	// The resourceVersion of oldObj makes the update fail with a conflict
+	// when the ApprovalTask changed since it was admitted
+	updated, err := approvalTasks.Update(ctx, newObj, metav1.UpdateOptions{})
+	if err != nil {
+		writeStatus(w, err)
+		return
+	}
+	if audited := r.audit(auditCtx, request, response); !audited.Allowed {
+		// The response is made already, the failure is left to the logs
+		logger.Errorw("Recorded a response missing from the audit log", "user", user.Username)
+	}
+	updated.TypeMeta = metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: Kind}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// setSignature sets the signature on every approver entry the input of the
+// user was set on.
+func setSignature(at *v1alpha1.ApprovalTask, isUser func(string) bool, isMember func(v1alpha1.ApproverDetails) bool, sig string) {
+	for i, approver := range at.Spec.Approvers {
+		if v1alpha1.IsGroupApproverType(approver.Type) {
+			if !isMember(approver) {
+				continue
+			}
+			at.Spec.Approvers[i].Signature = sig
+			for j, user := range approver.Users {
+				if isUser(user.Name) {
+					at.Spec.Approvers[i].Users[j].Signature = sig
+				}
+			}
+		} else if v1alpha1.IsUserApproverType(approver.Type) && isUser(approver.Name) {
+			at.Spec.Approvers[i].Signature = sig
+		}
+	}
+}
+
+// canRespond runs a SubjectAccessReview checking whether the user may create
+// the subresource. The API server authorized the request already, this keeps
+// the subresources safe when they are reached without it.
+func (r *reconciler) canRespond(ctx context.Context, user authenticationv1.UserInfo, namespace, name, subresource string) (bool, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	result, err := r.client.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        "create",
+				Group:       SubresourceGroup,
+				Version:     Version,
+				Resource:    "approvaltasks",
+				Subresource: subresource,
+				Name:        name,
+			},
+			User:   user.Username,
+			Groups: user.Groups,
+			UID:    user.UID,
+			Extra:  extra,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return result.Status.Allowed, nil
+}
+
+// isSubresourceUpdate returns true for the updates the webhook makes for the
+// subresources, which were admitted, and audited, as the user who called
+// them, and are checked with admitSubresourceUpdate.
+func (r *reconciler) isSubresourceUpdate(ctx context.Context, request *admissionv1.AdmissionRequest) bool {
+	if request.Operation != admissionv1.Update || request.Kind.Group != Group || request.Kind.Kind != Kind {
+		return false
+	}
+	self := r.identity(ctx)
+	return self != "" && request.UserInfo.Username == self
+}
+
+// admitSubresourceUpdate checks that an update the webhook makes for a
+// subresource only changes the entries of the user who called it, so that
+// the webhook cannot be used to change anything else. The user is the one
+// the changed entries are stamped with, the stamps being fresh and all of
+// the same user.
+func (r *reconciler) admitSubresourceUpdate(request *admissionv1.AdmissionRequest, now time.Time) *admissionv1.AdmissionResponse {
+	newObj, err := r.decodeNewObject(request.Object.Raw)
+	if err != nil {
+		return deny(v1alpha1.DenialReasonInvalidInput, "cannot decode incoming new object: %v", err)
+	}
+	oldObj, err := r.decodeOldObject(request.OldObject.Raw)
+	if err != nil {
+		return deny(v1alpha1.DenialReasonInvalidInput, "cannot decode incoming old object: %v", err)
+	}
+
+	// Only the approvers change
+	if len(oldObj.Spec.Approvers) != len(newObj.Spec.Approvers) {
+		return deny(v1alpha1.DenialReasonForeignChange, "the subresources cannot add or remove approvers")
+	}
+	spec := newObj.Spec.DeepCopy()
+	spec.Approvers = oldObj.Spec.Approvers
+	if !equality.Semantic.DeepEqual(&oldObj.Spec, spec) ||
+		!equality.Semantic.DeepEqual(oldObj.Labels, newObj.Labels) ||
+		!equality.Semantic.DeepEqual(oldObj.Annotations, newObj.Annotations) {
+		return deny(v1alpha1.DenialReasonForeignChange, "the subresources can only change the response of the user")
+	}
+
+	// of the user the changed entries are stamped with, per approver
+	stamped := make(map[string]bool)
+	var responder *v1alpha1.Responder
+	for i, approver := range newObj.Spec.Approvers {
+		old := oldObj.Spec.Approvers[i]
+		if approver.Name != old.Name || approver.Type != old.Type {
+			return deny(v1alpha1.DenialReasonForeignChange, "the subresources cannot rename or retype approvers")
+		}
+		for _, member := range old.Users {
+			if _, found := findUser(approver.Users, member.Name); !found {
+				return deny(v1alpha1.DenialReasonForeignChange, "the subresources cannot remove members of approvers")
+			}
+		}
+		if !equality.Semantic.DeepEqual(old.Responder, approver.Responder) {
+			stamped[approver.Type+"/"+approver.Name] = true
+			if responder == nil {
+				responder = approver.Responder
+			}
+		}
+		for _, member := range approver.Users {
+			previous, _ := findUser(old.Users, member.Name)
+			if !equality.Semantic.DeepEqual(previous.Responder, member.Responder) && responder == nil {
+				responder = member.Responder
+			}
+		}
+	}
+	if responder == nil {
+		return deny(v1alpha1.DenialReasonForeignChange, "the subresources can only change the stamped response of the user")
+	}
+	user := authenticationv1.UserInfo{Username: responder.Username, UID: responder.UID, Groups: responder.Groups}
+	if denied := verifyResponders(oldObj, newObj, user, now); denied != nil {
+		return deny(v1alpha1.DenialReasonForeignChange, "the subresources can only change the response of a single user")
+	}
+	isUser := r.identityStore.Load().Matcher(user.Username)
+	isMember := func(approver v1alpha1.ApproverDetails) bool {
+		return stamped[approver.Type+"/"+approver.Name]
+	}
+	if !CheckOtherUsersForInvalidChanges(oldObj.Spec.Approvers, newObj.Spec.Approvers, isUser, isMember) {
+		return deny(v1alpha1.DenialReasonForeignChange, "the subresources can only change the response of %s", user.Username)
+	}
+	for _, change := range impersonatedByChanges(oldObj, newObj, isUser, isMember) {
+		if !change.own {
+			return deny(v1alpha1.DenialReasonForeignChange, "the subresources can only change the response of %s", user.Username)
+		}
+	}
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+// identity returns the username the webhook authenticates as.
+func (r *reconciler) identity(ctx context.Context) string {
+	return r.self.username(ctx, r.client)
+}
+
+// selfIdentity is the username the webhook authenticates as, found with a
+// SelfSubjectReview the first time it is needed.
+type selfIdentity struct {
+	mu   sync.Mutex
+	self string
+}
+
+func (s *selfIdentity) username(ctx context.Context, client kubernetes.Interface) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.self == "" {
+		review, err := client.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+		if err != nil {
+			logging.FromContext(ctx).Errorw("Failed to find the identity of the webhook", "error", err)
+			return ""
+		}
+		s.self = review.Status.UserInfo.Username
+	}
+	return s.self
+}
+
+// reconcileAPIService sets the CA bundle of the APIService of the
+// subresources to the one of the certificate of the webhook.
+func (r *reconciler) reconcileAPIService(ctx context.Context, caCert []byte) error {
+	if r.dynamicClient == nil {
+		return nil
+	}
+	apiServices := r.dynamicClient.Resource(schema.GroupVersionResource{Group: "apiregistration.k8s.io", Version: "v1", Resource: "apiservices"})
+	apiService, err := apiServices.Get(ctx, APIServiceName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// The subresources are optional
+		return nil
+	} else if err != nil {
+		return err
+	}
+	caBundle := base64.StdEncoding.EncodeToString(caCert)
+	if current, _, _ := unstructured.NestedString(apiService.Object, "spec", "caBundle"); current == caBundle {
+		return nil
+	}
+	patch, err := json.Marshal(map[string]any{"spec": map[string]any{"caBundle": caBundle}})
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("Updating APIService")
+	_, err = apiServices.Patch(ctx, APIServiceName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj runtime.Object) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(obj)
+}
+
+func writeStatus(w http.ResponseWriter, err error) {
+	status := apierrors.NewInternalError(err).ErrStatus
+	var statusErr apierrors.APIStatus
+	if errors.As(err, &statusErr) {
+		status = statusErr.Status()
+	}
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	writeJSON(w, int(status.Code), &status)
+}
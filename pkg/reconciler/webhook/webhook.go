@@ -6,17 +6,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/groups"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	admissionv1 "k8s.io/api/admission/v1"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	admissionlisters "k8s.io/client-go/listers/admissionregistration/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/kmp"
 	"knative.dev/pkg/logging"
@@ -31,6 +44,13 @@ const (
 	Group   = "openshift-pipelines.org"
 	Version = "v1alpha1"
 	Kind    = "ApprovalTask"
+
+	// TracerName is the name of the tracer of the admission webhook
+	TracerName = "ApprovalTaskAdmission"
+
+	// EventReasonDenied is the reason of the Events recorded when a change
+	// to an ApprovalTask is denied
+	EventReasonDenied = "ApprovalDenied"
 )
 
 // reconciler implements the AdmissionController for resources
@@ -49,6 +69,40 @@ type reconciler struct {
 
 	disallowUnknownFields bool
 	secretName            string
+
+	// controllerUsername is the username of the controller, which alone may
+	// update the status of the ApprovalTasks
+	controllerUsername string
+
+	// requireSignedResponses requires every approver to sign their response
+	requireSignedResponses bool
+
+	// impersonationPolicy is what is done with the responses made through
+	// impersonation, impersonationExtraKeys the extras naming the
+	// impersonator and trustedImpersonators the ones the deny policy allows
+	impersonationPolicy    impersonationPolicy
+	impersonationExtraKeys []string
+	trustedImpersonators   []string
+
+	// approvalTaskClient and dynamicClient update the ApprovalTasks for the
+	// subresources, and the CA bundle of their APIService
+	approvalTaskClient versioned.Interface
+	dynamicClient      dynamic.Interface
+
+	// groupResolver resolves the members of the Group approvers whose group
+	// is not in the identity of the user
+	groupResolver groups.DirectoryProvider
+
+	// self is the username of the webhook, see identity
+	self selfIdentity
+
+	// identityStore keeps the identity configuration of the namespace of
+	// the webhook, and authenticationConfigMaps the requestheader
+	// configuration of the API server
+	identityStore            *identity.Store
+	authenticationConfigMaps corelisters.ConfigMapNamespaceLister
+
+	recorder record.EventRecorder
 }
 
 var _ controller.Reconciler = (*reconciler)(nil)
@@ -77,17 +131,89 @@ func (r *reconciler) Reconcile(ctx context.Context, key string) error {
 	}
 
 	// Reconcile the webhook configuration.
-	return r.reconcileValidatingWebhook(ctx, caCert)
+	if err := r.reconcileValidatingWebhook(ctx, caCert); err != nil {
+		return err
+	}
+	return r.reconcileAPIService(ctx, caCert)
 }
 
+// Admit implements webhook.StatelessAdmissionController. Each admission is
+// traced as a child span of the request, linked to the trace of the
+// PipelineRun the ApprovalTask belongs to, and approval actions are recorded
+// in the audit log. Usernames are compared with the identity configuration
+// read for the admission.
 func (r *reconciler) Admit(ctx context.Context, request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
 	if r.withContext != nil {
 		ctx = r.withContext(ctx)
 	}
 
+	opts := []trace.SpanStartOption{trace.WithAttributes(
+		attribute.String("approvaltask", request.Name),
+		attribute.String("namespace", request.Namespace),
+		attribute.String("operation", string(request.Operation)),
+		attribute.String("user", request.UserInfo.Username),
+	)}
+	var meta metav1.PartialObjectMetadata
+	if err := json.Unmarshal(request.Object.Raw, &meta); err == nil {
+		if spanContext := tracing.SpanContextFromAnnotations(meta.Annotations); spanContext.IsValid() {
+			opts = append(opts, trace.WithLinks(trace.Link{SpanContext: spanContext}))
+		}
+	}
+	ctx, span := otel.Tracer(TracerName).Start(ctx, "ApprovalTask:Admit", opts...)
+	defer span.End()
+
+	var response *admissionv1.AdmissionResponse
+	if request.SubResource == "status" {
+		response = r.admitStatus(request)
+	} else if request.Operation == admissionv1.Delete && request.Kind.Kind == Kind {
+		response = r.admitDelete(ctx, request)
+	} else if r.isSubresourceUpdate(ctx, request) {
+		// Admitted, and audited, when the subresource was called, as long
+		// as it only changes the response of the user
+		response = r.admitSubresourceUpdate(request, time.Now())
+	} else {
+		ctx = identity.WithConfig(ctx, r.identityStore.Load())
+		response = r.audit(ctx, request, r.authorize(ctx, request, r.admit(ctx, request)))
+	}
+	response = withDetails(request, response)
+	span.SetAttributes(attribute.Bool("allowed", response.Allowed))
+	if !response.Allowed {
+		recordDenied(ctx, request.Namespace, denialReason(response))
+	}
+	if !response.Allowed && response.Result != nil {
+		span.SetAttributes(attribute.String("denied.reason", response.Result.Message))
+		if !isDryRun(request) {
+			r.recordDenial(request, meta, response.Result.Message)
+		}
+	}
+	return response
+}
+
+// recordDenial records a Warning Event on the ApprovalTask, so that approvers
+// can see why their change was denied without the logs of the webhook.
+func (r *reconciler) recordDenial(request *admissionv1.AdmissionRequest, meta metav1.PartialObjectMetadata, message string) {
+	if r.recorder == nil || request.Name == "" {
+		return
+	}
+	ref := &corev1.ObjectReference{
+		APIVersion: v1alpha1.SchemeGroupVersion.String(),
+		Kind:       request.Kind.Kind,
+		Namespace:  request.Namespace,
+		Name:       request.Name,
+		UID:        meta.UID,
+	}
+	r.recorder.Eventf(ref, corev1.EventTypeWarning, EventReasonDenied,
+		"%s of %s %s by %q denied: %s", strings.ToLower(string(request.Operation)), request.Kind.Kind, request.Name, request.UserInfo.Username, message)
+}
+
+func (r *reconciler) admit(ctx context.Context, request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
 	logger := logging.FromContext(ctx)
 	kind := request.Kind
 
+	if kind.Group == Group && kind.Kind == RecordKind {
+		return r.admitApprovalRecord(ctx, request)
+	}
+
 	newBytes := request.Object.Raw
 	gvk := schema.GroupVersionKind{
 		Group:   kind.Group,
@@ -107,13 +233,13 @@ func (r *reconciler) Admit(ctx context.Context, request *admissionv1.AdmissionRe
 
 	// Validate structural requirements 
 	if err := validateApprovalTask(newObj, ctx); err != nil {
-		return webhook.MakeErrorStatus("validation failed: %v", err)
+		return deny(v1alpha1.DenialReasonInvalidInput, "validation failed: %v", err)
 	}
 
 	if request.Operation == "CREATE" {
 		// For CREATE operations, ensure all approver inputs are set to "pending"
 		if err := validateApproverInputsForCreate(newObj); err != nil {
-			return webhook.MakeErrorStatus("validation failed: %v", err)
+			return deny(v1alpha1.DenialReasonInvalidInput, "validation failed: %v", err)
 		}
 		return &admissionv1.AdmissionResponse{
 			Allowed: true,
@@ -131,45 +257,41 @@ func (r *reconciler) Admit(ctx context.Context, request *admissionv1.AdmissionRe
 	}
 
 	// Check if approval is required by the approver
-	if !isApprovalRequired(*oldObj) {
-		return &admissionv1.AdmissionResponse{
-			Allowed: false,
-			Result: &metav1.Status{
-				Message: "ApprovalTask has already reached it's final state",
-			},
-		}
+	config := identity.FromContext(ctx)
+	if !isApprovalRequired(*oldObj, config) {
+		return deny(v1alpha1.DenialReasonFinalState, "ApprovalTask has already reached it's final state")
 	}
 
-	// Check if username is mentioned in the approval task
-	if !ifUserExists(oldObj.Spec.Approvers, request) {
-		return &admissionv1.AdmissionResponse{
-			Allowed: false,
-			Result: &metav1.Status{
-				Message: "User does not exist in the approval list",
-			},
-		}
+	// Resolve the group approvers, Group or RBAC, the user is a member of
+	isMember, err := r.groupMembership(ctx, request, oldObj.Spec.Approvers)
+	if err != nil {
+		logger.Errorw("Failed to check the group approvers", "approvaltask", request.Name, "error", err)
+		return webhook.MakeErrorStatus("unable to check the group approvers")
+	}
+
+	// Check if username, or one of its aliases, is mentioned in the approval task
+	isUser := config.Matcher(request.UserInfo.Username)
+	if !ifUserExists(oldObj.Spec.Approvers, isUser, isMember) {
+		return deny(v1alpha1.DenialReasonNotAnApprover, "User does not exist in the approval list")
 	}
 
 	// Check if user is updating the input for his name only
 	var userApprovalChanged bool
 	errMsg := fmt.Errorf("User can only update their own approval input")
+	reason := v1alpha1.DenialReasonForeignChange
 
 	// First check if user is trying to re-approve/re-reject their own already-decided task
-	if alreadyDecidedMsg := checkIfUserAlreadyDecided(oldObj, newObj, request); alreadyDecidedMsg != "" {
-		return &admissionv1.AdmissionResponse{
-			Allowed: false,
-			Result: &metav1.Status{
-				Message: alreadyDecidedMsg,
-			},
-		}
+	if alreadyDecidedMsg := checkIfUserAlreadyDecided(oldObj, newObj, isUser, isMember); alreadyDecidedMsg != "" {
+		return deny(v1alpha1.DenialReasonAlreadyDecided, "%s", alreadyDecidedMsg)
 	}
 
-	changed, err := IsUserApprovalChanged(oldObj.Spec.Approvers, newObj.Spec.Approvers, request)
+	changed, err := IsUserApprovalChanged(oldObj.Spec.Approvers, newObj.Spec.Approvers, isUser, isMember)
 	if err != nil {
 		userApprovalChanged = false
 		errMsg = fmt.Errorf("Invalid input change: %v", err)
+		reason = v1alpha1.DenialReasonInvalidInput
 	} else if changed {
-		if CheckOtherUsersForInvalidChanges(oldObj.Spec.Approvers, newObj.Spec.Approvers, request) {
+		if CheckOtherUsersForInvalidChanges(oldObj.Spec.Approvers, newObj.Spec.Approvers, isUser, isMember) {
 			userApprovalChanged = true
 		} else {
 			userApprovalChanged = false
@@ -179,12 +301,19 @@ func (r *reconciler) Admit(ctx context.Context, request *admissionv1.AdmissionRe
 	}
 
 	if !userApprovalChanged {
-		return &admissionv1.AdmissionResponse{
-			Allowed: false,
-			Result: &metav1.Status{
-				Message: errMsg.Error(),
-			},
-		}
+		return deny(reason, "%s", errMsg.Error())
+	}
+
+	if denied := verifyResponders(oldObj, newObj, request.UserInfo, time.Now()); denied != nil {
+		return denied
+	}
+
+	if denied := r.verifyImpersonation(request, oldObj, newObj, isUser, isMember); denied != nil {
+		return denied
+	}
+
+	if denied := r.verifySignature(ctx, request, oldObj, newObj); denied != nil {
+		return denied
 	}
 
 	return &admissionv1.AdmissionResponse{
@@ -199,6 +328,7 @@ func (ac *reconciler) reconcileValidatingWebhook(ctx context.Context, caCert []b
 			Operations: []admissionregistrationv1.OperationType{
 				admissionregistrationv1.Create,
 				admissionregistrationv1.Update,
+				admissionregistrationv1.Delete,
 			},
 			Rule: admissionregistrationv1.Rule{
 				APIGroups:   []string{"openshift-pipelines.org"},
@@ -206,6 +336,27 @@ func (ac *reconciler) reconcileValidatingWebhook(ctx context.Context, caCert []b
 				Resources:   []string{"approvaltask", "approvaltasks"},
 			},
 		},
+		{
+			Operations: []admissionregistrationv1.OperationType{
+				admissionregistrationv1.Update,
+			},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{"openshift-pipelines.org"},
+				APIVersions: []string{"v1alpha1"},
+				Resources:   []string{"approvaltasks/status"},
+			},
+		},
+		{
+			Operations: []admissionregistrationv1.OperationType{
+				admissionregistrationv1.Create,
+				admissionregistrationv1.Update,
+			},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{"openshift-pipelines.org"},
+				APIVersions: []string{"v1alpha1"},
+				Resources:   []string{"approvalrecords"},
+			},
+		},
 	}
 
 	configuredWebhook, err := ac.vwhlister.Get(ac.key.Name)
@@ -248,35 +399,26 @@ func (ac *reconciler) Path() string {
 	return ac.path
 }
 
-func ifUserExists(approvals []v1alpha1.ApproverDetails, request *admissionv1.AdmissionRequest) bool {
+func ifUserExists(approvals []v1alpha1.ApproverDetails, isUser func(string) bool, isMember func(v1alpha1.ApproverDetails) bool) bool {
 	if len(approvals) == 0 {
 		return true
 	}
 	for _, approval := range approvals {
 		switch v1alpha1.DefaultedApproverType(approval.Type) {
-		case "User":
-			if approval.Name == request.UserInfo.Username {
+		case "User", v1alpha1.ServiceAccountApproverType:
+			if isUser(approval.Name) {
 				return true
 			}
-		case "Group":
-			// Check if user is in the group by checking the group name against user's groups
-			for _, userGroup := range request.UserInfo.Groups {
-				if approval.Name == userGroup {
-					return true
-				}
-			}
-			// Also check if user is explicitly listed in the group's users
-			for _, user := range approval.Users {
-				if user.Name == request.UserInfo.Username {
-					return true
-				}
+		case "Group", v1alpha1.RBACApproverType:
+			if isMember(approval) {
+				return true
 			}
 		}
 	}
 	return false
 }
 
-func isApprovalRequired(approvaltask v1alpha1.ApprovalTask) bool {
+func isApprovalRequired(approvaltask v1alpha1.ApprovalTask, config *identity.Config) bool {
 	// If the task has reached a final state, no more approvals are needed
 	if approvaltask.Status.State == "rejected" || approvaltask.Status.State == "approved" {
 		return false
@@ -290,17 +432,25 @@ func isApprovalRequired(approvaltask v1alpha1.ApprovalTask) bool {
 			continue
 		}
 		
-		if v1alpha1.DefaultedApproverType(approver.Type) == "User" {
-			approvedUsers[approver.Name] = true
-		} else if v1alpha1.DefaultedApproverType(approver.Type) == "Group" {
+		if v1alpha1.IsUserApproverType(approver.Type) {
+			if approvaltask.CountsTowardQuorum(approver.Name) {
+				approvedUsers[config.Normalize(approver.Name)] = true
+			}
+		} else if v1alpha1.IsGroupApproverType(approver.Type) {
 			for _, user := range approver.Users {
-				if user.Input == "approve" {
-					approvedUsers[user.Name] = true
+				if user.Input == "approve" && approvaltask.CountsTowardQuorum(user.Name) {
+					approvedUsers[config.Normalize(user.Name)] = true
 				}
 			}
 		}
 	}
 	
+	for _, response := range approvaltask.ExternalResponses() {
+		if response.Response == "approved" {
+			approvedUsers[response.Type+":"+response.Name] = true
+		}
+	}
+	
 	// If we have enough approvals, the task should be approved (final state)
 	if len(approvedUsers) >= approvaltask.Spec.NumberOfApprovalsRequired {
 		return false
@@ -329,33 +479,17 @@ func hasOnlyInputChanged(oldObjApprover, newObjApprover v1alpha1.ApproverDetails
 	return false, nil
 }
 
-// IsUserApprovalChanged checks if there is a valid input change for the current user.
-func IsUserApprovalChanged(oldObjApprovers, newObjApprovers []v1alpha1.ApproverDetails, request *admissionv1.AdmissionRequest) (bool, error) {
-	currentUser := request.UserInfo.Username
+// IsUserApprovalChanged checks if there is a valid input change for the current user,
+// listed under one of the usernames isUser returns true for.
+func IsUserApprovalChanged(oldObjApprovers, newObjApprovers []v1alpha1.ApproverDetails, isUser func(string) bool, isMember func(v1alpha1.ApproverDetails) bool) (bool, error) {
 	for i, approver := range oldObjApprovers {
-		if approver.Name == currentUser && v1alpha1.DefaultedApproverType(approver.Type) == "User" {
+		if isUser(approver.Name) && v1alpha1.IsUserApproverType(approver.Type) {
 			return hasOnlyInputChanged(approver, newObjApprovers[i])
 		}
 
-		if v1alpha1.DefaultedApproverType(approver.Type) == "Group" {
+		if v1alpha1.IsGroupApproverType(approver.Type) {
 			// Check if current user is a member of this group
-			isUserInGroup := false
-
-			// Check if user is in the group by checking the group name against user's groups
-			for _, userGroup := range request.UserInfo.Groups {
-				if approver.Name == userGroup {
-					isUserInGroup = true
-					break
-				}
-			}
-
-			// Also check if user is explicitly listed in the group's users
-			for _, user := range approver.Users {
-				if user.Name == currentUser {
-					isUserInGroup = true
-					break
-				}
-			}
+			isUserInGroup := isMember(approver)
 
 			if isUserInGroup {
 				// Allow changes to group-level input if user is in the group
@@ -373,7 +507,7 @@ func IsUserApprovalChanged(oldObjApprovers, newObjApprovers []v1alpha1.ApproverD
 				newUserFound := false
 
 				for _, user := range approver.Users {
-					if user.Name == currentUser {
+					if isUser(user.Name) {
 						oldUserFound = true
 						break
 					}
@@ -381,7 +515,7 @@ func IsUserApprovalChanged(oldObjApprovers, newObjApprovers []v1alpha1.ApproverD
 
 				if i < len(newObjApprovers) {
 					for _, user := range newObjApprovers[i].Users {
-						if user.Name == currentUser {
+						if isUser(user.Name) {
 							newUserFound = true
 							break
 						}
@@ -393,7 +527,7 @@ func IsUserApprovalChanged(oldObjApprovers, newObjApprovers []v1alpha1.ApproverD
 					// Validate the input they're setting for themselves
 					if i < len(newObjApprovers) {
 						for _, user := range newObjApprovers[i].Users {
-							if user.Name == currentUser {
+							if isUser(user.Name) {
 								if err := hasValidInputValue(user.Input); err != nil {
 									return false, err
 								}
@@ -409,7 +543,7 @@ func IsUserApprovalChanged(oldObjApprovers, newObjApprovers []v1alpha1.ApproverD
 				var oldUserInput string
 				userFoundInOld := false
 				for _, user := range approver.Users {
-					if user.Name == currentUser {
+					if isUser(user.Name) {
 						oldUserInput = user.Input
 						userFoundInOld = true
 						break
@@ -421,7 +555,7 @@ func IsUserApprovalChanged(oldObjApprovers, newObjApprovers []v1alpha1.ApproverD
 				userFoundInNew := false
 				if i < len(newObjApprovers) {
 					for _, user := range newObjApprovers[i].Users {
-						if user.Name == currentUser {
+						if isUser(user.Name) {
 							newUserInput = user.Input
 							userFoundInNew = true
 							break
@@ -443,15 +577,14 @@ func IsUserApprovalChanged(oldObjApprovers, newObjApprovers []v1alpha1.ApproverD
 }
 
 // checkIfUserAlreadyDecided checks if a user is trying to re-approve/re-reject a task they've already decided on
-func checkIfUserAlreadyDecided(oldObj *v1alpha1.ApprovalTask, newObj *v1alpha1.ApprovalTask, request *admissionv1.AdmissionRequest) string {
-	currentUser := request.UserInfo.Username
+func checkIfUserAlreadyDecided(oldObj *v1alpha1.ApprovalTask, newObj *v1alpha1.ApprovalTask, isUser func(string) bool, isMember func(v1alpha1.ApproverDetails) bool) string {
 	
 	// Get user's desired new input from the incoming object
 	desiredInput := ""
 	
 	// First check if user is an individual approver
 	for _, approver := range newObj.Spec.Approvers {
-		if v1alpha1.DefaultedApproverType(approver.Type) == "User" && approver.Name == currentUser {
+		if v1alpha1.IsUserApproverType(approver.Type) && isUser(approver.Name) {
 			desiredInput = approver.Input
 			break
 		}
@@ -460,10 +593,10 @@ func checkIfUserAlreadyDecided(oldObj *v1alpha1.ApprovalTask, newObj *v1alpha1.A
 	// If not found as individual user, check if user is in any group
 	if desiredInput == "" {
 		for _, approver := range newObj.Spec.Approvers {
-			if v1alpha1.DefaultedApproverType(approver.Type) == "Group" {
+			if v1alpha1.IsGroupApproverType(approver.Type) {
 				// Check if user is explicitly in the group's users list
 				for _, user := range approver.Users {
-					if user.Name == currentUser {
+					if isUser(user.Name) {
 						desiredInput = user.Input
 						break
 					}
@@ -473,13 +606,8 @@ func checkIfUserAlreadyDecided(oldObj *v1alpha1.ApprovalTask, newObj *v1alpha1.A
 				}
 				
 				// Check if user is in the group via RBAC (group-level input)
-				for _, userGroup := range request.UserInfo.Groups {
-					if approver.Name == userGroup {
-						desiredInput = approver.Input
-						break
-					}
-				}
-				if desiredInput != "" {
+				if isMember(approver) {
+					desiredInput = approver.Input
 					break
 				}
 			}
@@ -488,7 +616,7 @@ func checkIfUserAlreadyDecided(oldObj *v1alpha1.ApprovalTask, newObj *v1alpha1.A
 	
 	// Check status.approversResponse to see if user has already made a decision
 	for _, approverResponse := range oldObj.Status.ApproversResponse {
-		if v1alpha1.DefaultedApproverType(approverResponse.Type) == "User" && approverResponse.Name == currentUser {
+		if v1alpha1.IsUserApproverType(approverResponse.Type) && isUser(approverResponse.Name) {
 			// Block duplicate approvals and any action after rejection
 			if approverResponse.Response == "approved" && desiredInput == "approve" {
 				return "User has already approved"
@@ -498,9 +626,9 @@ func checkIfUserAlreadyDecided(oldObj *v1alpha1.ApprovalTask, newObj *v1alpha1.A
 		}
 		
 		// Check if user is in any group that has responded
-		if v1alpha1.DefaultedApproverType(approverResponse.Type) == "Group" {
+		if v1alpha1.IsGroupApproverType(approverResponse.Type) {
 			for _, member := range approverResponse.GroupMembers {
-				if member.Name == currentUser {
+				if isUser(member.Name) {
 					// Block duplicate approvals and any action after rejection
 					if member.Response == "approved" && desiredInput == "approve" {
 						return "User has already approved"
@@ -515,64 +643,73 @@ func checkIfUserAlreadyDecided(oldObj *v1alpha1.ApprovalTask, newObj *v1alpha1.A
 	return "" // No issue found
 }
 
-// CheckOtherUsersForInvalidChanges validates that no other approvers inputs have been changed
-func CheckOtherUsersForInvalidChanges(oldObjApprovers, newObjApprover []v1alpha1.ApproverDetails, request *admissionv1.AdmissionRequest) bool {
-	currentUser := request.UserInfo.Username
+// approverResponse returns the response of an approver, as the one of a
+// member of a group.
+func approverResponse(approver v1alpha1.ApproverDetails) v1alpha1.UserDetails {
+	return v1alpha1.UserDetails{
+		Input:     approver.Input,
+		Message:   approver.Message,
+		Signature: approver.Signature,
+		Responder: approver.Responder,
+	}
+}
+
+// sameResponse tells whether the input, message, signature and responder of
+// a response are unchanged, which only its own approver may change.
+func sameResponse(old, new v1alpha1.UserDetails) bool {
+	return old.Input == new.Input && old.Message == new.Message && old.Signature == new.Signature &&
+		equality.Semantic.DeepEqual(old.Responder, new.Responder)
+}
+
+// CheckOtherUsersForInvalidChanges validates that no other approvers responses have been changed
+func CheckOtherUsersForInvalidChanges(oldObjApprovers, newObjApprover []v1alpha1.ApproverDetails, isUser func(string) bool, isMember func(v1alpha1.ApproverDetails) bool) bool {
 	for i, approver := range oldObjApprovers {
-		if v1alpha1.DefaultedApproverType(approver.Type) == "User" && approver.Name != currentUser {
-			if oldObjApprovers[i].Input != newObjApprover[i].Input {
+		if v1alpha1.IsUserApproverType(approver.Type) && !isUser(approver.Name) {
+			if !sameResponse(approverResponse(oldObjApprovers[i]), approverResponse(newObjApprover[i])) {
 				return false
 			}
 		}
 
-		if v1alpha1.DefaultedApproverType(approver.Type) == "Group" {
-			// Check if current user is a member of this group
-			isUserInGroup := false
-
-			// Check if user is in the group by checking the group name against user's groups
-			for _, userGroup := range request.UserInfo.Groups {
-				if approver.Name == userGroup {
-					isUserInGroup = true
-					break
-				}
+		// External approvers are answered by the controller through the status only
+		if v1alpha1.IsExternalApproverType(approver.Type) {
+			if i >= len(newObjApprover) || approver.Input != newObjApprover[i].Input || approver.Message != newObjApprover[i].Message ||
+				approver.Name != newObjApprover[i].Name || approver.Type != newObjApprover[i].Type {
+				return false
 			}
+		}
 
-			// Also check if user is explicitly listed in the group's users
-			for _, user := range approver.Users {
-				if user.Name == currentUser {
-					isUserInGroup = true
-					break
-				}
-			}
+		if v1alpha1.IsGroupApproverType(approver.Type) {
+			// Check if current user is a member of this group
+			isUserInGroup := isMember(approver)
 
-			// If current user is not in this group, they shouldn't be able to change the group-level input
+			// If current user is not in this group, they shouldn't be able to change the group-level response
 			if !isUserInGroup {
-				if i < len(newObjApprover) && approver.Input != newObjApprover[i].Input {
+				if i < len(newObjApprover) && !sameResponse(approverResponse(approver), approverResponse(newObjApprover[i])) {
 					return false
 				}
 			}
 
-			// Check that only current user's input has changed in group users
+			// Check that only current user's response has changed in group users
 			// Build maps of existing users for easier comparison
-			oldUsers := make(map[string]string) // name -> input
-			newUsers := make(map[string]string) // name -> input
+			oldUsers := make(map[string]v1alpha1.UserDetails)
+			newUsers := make(map[string]v1alpha1.UserDetails)
 
 			for _, user := range approver.Users {
-				oldUsers[user.Name] = user.Input
+				oldUsers[user.Name] = user
 			}
 
 			if i < len(newObjApprover) {
 				for _, user := range newObjApprover[i].Users {
-					newUsers[user.Name] = user.Input
+					newUsers[user.Name] = user
 				}
 			}
 
-			// Check that existing users (other than current user) haven't changed their input
-			for userName, oldInput := range oldUsers {
-				if userName != currentUser {
-					if newInput, exists := newUsers[userName]; exists {
-						if oldInput != newInput {
-							return false // Someone else's input changed
+			// Check that existing users (other than current user) haven't changed their response
+			for userName, oldUser := range oldUsers {
+				if !isUser(userName) {
+					if newUser, exists := newUsers[userName]; exists {
+						if !sameResponse(oldUser, newUser) {
+							return false // Someone else's response changed
 						}
 					}
 				}
@@ -582,7 +719,7 @@ func CheckOtherUsersForInvalidChanges(oldObjApprovers, newObjApprover []v1alpha1
 			for userName := range newUsers {
 				if _, existedBefore := oldUsers[userName]; !existedBefore {
 					// Someone new was added - only allow if it's the current user and they're a group member
-					if userName != currentUser {
+					if !isUser(userName) {
 						return false // Someone other than current user was added
 					}
 					if !isUserInGroup {
@@ -593,6 +730,15 @@ func CheckOtherUsersForInvalidChanges(oldObjApprovers, newObjApprover []v1alpha1
 		}
 	}
 
+	// No approver can be turned into an external one, whose change request
+	// would then decide the ApprovalTask
+	for i, approver := range newObjApprover {
+		if v1alpha1.IsExternalApproverType(approver.Type) &&
+			(i >= len(oldObjApprovers) || !v1alpha1.IsExternalApproverType(oldObjApprovers[i].Type) || oldObjApprovers[i].Name != approver.Name) {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -642,8 +788,8 @@ func validateApprovalTaskSpec(spec *v1alpha1.ApprovalTaskSpec, ctx context.Conte
 func validateApprover(approver v1alpha1.ApproverDetails, fieldPath string) error {
 	// Validate approver type first to determine validation rules
 	approverType := v1alpha1.DefaultedApproverType(approver.Type)
-	if approverType != "User" && approverType != "Group" {
-		return fmt.Errorf("%s.type: must be either 'User' or 'Group', got '%s'", fieldPath, approver.Type)
+	if approverType != "User" && approverType != "Group" && approverType != v1alpha1.RBACApproverType && approverType != v1alpha1.ChangeRequestApproverType && approverType != v1alpha1.ServiceAccountApproverType {
+		return fmt.Errorf("%s.type: must be one of 'User', 'Group', '%s', '%s' or '%s', got '%s'", fieldPath, v1alpha1.RBACApproverType, v1alpha1.ChangeRequestApproverType, v1alpha1.ServiceAccountApproverType, approver.Type)
 	}
 
 	// Validate name format based on type (includes empty check via validateNameFormat)
@@ -651,10 +797,25 @@ func validateApprover(approver v1alpha1.ApproverDetails, fieldPath string) error
 		if err := validateUserName(approver.Name); err != nil {
 			return fmt.Errorf("%s.name: %w", fieldPath, err)
 		}
+	} else if approverType == v1alpha1.ServiceAccountApproverType {
+		if err := validateServiceAccountName(approver.Name); err != nil {
+			return fmt.Errorf("%s.name: %w", fieldPath, err)
+		}
 	} else if approverType == "Group" {
 		if err := validateGroupName(approver.Name); err != nil {
 			return fmt.Errorf("%s.name: %w", fieldPath, err)
 		}
+	} else if approverType == v1alpha1.RBACApproverType {
+		if err := validateRBACName(approver.Name); err != nil {
+			return fmt.Errorf("%s.name: %w", fieldPath, err)
+		}
+	} else if approverType == v1alpha1.ChangeRequestApproverType {
+		if err := validateNameFormat(approver.Name, "change request number"); err != nil {
+			return fmt.Errorf("%s.name: %w", fieldPath, err)
+		}
+		if approver.Input != "pending" {
+			return fmt.Errorf("%s.input: must be 'pending' for a %s approver, its response is recorded in the status", fieldPath, v1alpha1.ChangeRequestApproverType)
+		}
 	}
 
 	// Validate input value
@@ -664,7 +825,7 @@ func validateApprover(approver v1alpha1.ApproverDetails, fieldPath string) error
 	}
 
 	// Validate users for group type
-	if approverType == "Group" {
+	if v1alpha1.IsGroupApproverType(approverType) {
 		
 		// Track duplicate users within the group
 		groupUsers := make(map[string]int) // username -> index
@@ -734,6 +895,26 @@ func validateGroupName(name string) error {
 	return nil
 }
 
+// validateRBACName validates the resourceName an RBAC approver is scoped to
+func validateRBACName(name string) error {
+	if name == v1alpha1.AnyApprovalTask {
+		return nil
+	}
+	if err := validateNameFormat(name, "RBAC resourceName"); err != nil {
+		return err
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return fmt.Errorf("invalid RBAC resourceName: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// validateServiceAccountName validates the username of a ServiceAccount approver
+func validateServiceAccountName(name string) error {
+	_, _, err := v1alpha1.ParseServiceAccountUsername(name)
+	return err
+}
+
 // webhookContains checks if a slice contains a string
 func webhookContains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -782,7 +963,7 @@ func validateApproverInputsForCreate(approvalTask *v1alpha1.ApprovalTask) error
 		}
 		
 		// For group approvers, also validate that all users within the group have pending input
-		if v1alpha1.DefaultedApproverType(approver.Type) == "Group" {
+		if v1alpha1.IsGroupApproverType(approver.Type) {
 			for j, user := range approver.Users {
 				if user.Input != "pending" {
 					return fmt.Errorf("approvers[%d].users[%d].input: must be 'pending' for new ApprovalTask, got '%s'", i, j, user.Input)
@@ -0,0 +1,1096 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/audit"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/identity"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/policy"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekube "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	testControllerUsername = "system:serviceaccount:manual-approval-gate:manual-approval-gate-controller"
+	testWebhookUsername    = "system:serviceaccount:manual-approval-gate:manual-approval-webhook"
+)
+
+// newTestReconciler returns a reconciler of the webhook running in the
+// manual-approval-gate namespace.
+func newTestReconciler(t *testing.T, objects ...runtime.Object) *reconciler {
+	t.Setenv("SYSTEM_NAMESPACE", "manual-approval-gate")
+	return &reconciler{
+		client:             fakekube.NewSimpleClientset(objects...),
+		controllerUsername: testControllerUsername,
+		self:               selfIdentity{self: testWebhookUsername},
+	}
+}
+
+func admissionRequest(t *testing.T, operation admissionv1.Operation, username string, oldObj, newObj *v1alpha1.ApprovalTask) *admissionv1.AdmissionRequest {
+	request := &admissionv1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: Group, Version: Version, Kind: Kind},
+		Operation: operation,
+		Namespace: "foo",
+		Name:      "at-1",
+		UserInfo:  authenticationv1.UserInfo{Username: username},
+	}
+	for obj, raw := range map[*v1alpha1.ApprovalTask]*runtime.RawExtension{oldObj: &request.OldObject, newObj: &request.Object} {
+		if obj == nil {
+			continue
+		}
+		b, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw.Raw = b
+	}
+	return request
+}
+
+func approvalTask(approvers ...v1alpha1.ApproverDetails) *v1alpha1.ApprovalTask {
+	return &v1alpha1.ApprovalTask{
+		ObjectMeta: metav1.ObjectMeta{Name: "at-1", Namespace: "foo"},
+		Spec: v1alpha1.ApprovalTaskSpec{
+			Approvers:                 approvers,
+			NumberOfApprovalsRequired: 1,
+		},
+	}
+}
+
+func isUserNamed(username string) func(string) bool {
+	return func(name string) bool { return name == username }
+}
+
+func isNoMember(v1alpha1.ApproverDetails) bool { return false }
+
+func TestParseImpersonationPolicy(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    impersonationPolicy
+		wantErr bool
+	}{
+		{value: "", want: allowImpersonation},
+		{value: "record", want: recordImpersonation},
+		{value: " Deny ", want: denyImpersonation},
+		{value: "denied", wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := parseImpersonationPolicy(tc.value)
+		assert.Equal(t, tc.wantErr, err != nil, tc.value)
+		assert.Equal(t, tc.want, got, tc.value)
+	}
+}
+
+func TestVerifyImpersonation(t *testing.T) {
+	controllerExtra := map[string]authenticationv1.ExtraValue{v1alpha1.ImpersonatorExtraKey: {v1alpha1.ControllerImpersonator}}
+
+	tests := []struct {
+		name       string
+		policy     impersonationPolicy
+		trusted    []string
+		extra      map[string]authenticationv1.ExtraValue
+		recorded   string
+		wantReason metav1.StatusReason
+	}{{
+		name:   "not impersonated",
+		policy: denyImpersonation,
+	}, {
+		name:   "allowed",
+		policy: allowImpersonation,
+		extra:  controllerExtra,
+	}, {
+		name:       "record without impersonatedBy",
+		policy:     recordImpersonation,
+		extra:      controllerExtra,
+		wantReason: v1alpha1.DenialReasonPolicyDenied,
+	}, {
+		name:     "record with impersonatedBy",
+		policy:   recordImpersonation,
+		extra:    controllerExtra,
+		recorded: v1alpha1.ControllerImpersonator,
+	}, {
+		name:       "the controller is not trusted by default",
+		policy:     denyImpersonation,
+		extra:      controllerExtra,
+		wantReason: v1alpha1.DenialReasonPolicyDenied,
+	}, {
+		name:    "trusted impersonator",
+		policy:  denyImpersonation,
+		trusted: []string{v1alpha1.ControllerImpersonator},
+		extra:   controllerExtra,
+	}, {
+		name:       "impersonatedBy of another impersonator",
+		policy:     allowImpersonation,
+		extra:      controllerExtra,
+		recorded:   "admin",
+		wantReason: v1alpha1.DenialReasonInvalidInput,
+	}, {
+		name:       "impersonatedBy without impersonation",
+		policy:     allowImpersonation,
+		recorded:   "admin",
+		wantReason: v1alpha1.DenialReasonInvalidInput,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &reconciler{impersonationPolicy: tc.policy, trustedImpersonators: tc.trusted}
+			oldObj := approvalTask(v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "pending"})
+			newObj := oldObj.DeepCopy()
+			newObj.Spec.Approvers[0].Input = "approve"
+			newObj.Spec.Approvers[0].ImpersonatedBy = tc.recorded
+			request := &admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: "alice", Extra: tc.extra}}
+
+			resp := r.verifyImpersonation(request, oldObj, newObj, isUserNamed("alice"), isNoMember)
+			if tc.wantReason == "" {
+				assert.Nil(t, resp)
+				return
+			}
+			if assert.NotNil(t, resp) {
+				assert.False(t, resp.Allowed)
+				assert.Equal(t, tc.wantReason, resp.Result.Reason)
+			}
+		})
+	}
+}
+
+func TestAdmitExternalPolicy(t *testing.T) {
+	pending := approvalTask(v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "pending"})
+	pending.Status.State = "pending"
+	approved := pending.DeepCopy()
+	approved.Spec.Approvers[0].Input = "approve"
+	stamped := approved.DeepCopy()
+	stamped.Spec.Approvers[0].Responder = &v1alpha1.Responder{Username: "alice", Time: metav1.Now()}
+
+	allow := policy.AuthorizerFunc(func(context.Context, policy.Input) (policy.Decision, error) {
+		return policy.Decision{Allow: true}, nil
+	})
+	deny := policy.AuthorizerFunc(func(context.Context, policy.Input) (policy.Decision, error) {
+		return policy.Decision{Reason: "outside of the change window"}, nil
+	})
+	unavailable := policy.AuthorizerFunc(func(context.Context, policy.Input) (policy.Decision, error) {
+		return policy.Decision{}, errors.New("connection refused")
+	})
+
+	tests := []struct {
+		name          string
+		authorizer    policy.AuthorizerFunc
+		failurePolicy policy.FailurePolicy
+		request       func(t *testing.T) *admissionv1.AdmissionRequest
+		wantAsked     bool
+		wantReason    metav1.StatusReason
+		wantMessage   string
+	}{{
+		name:       "both allow the response",
+		authorizer: allow,
+		request: func(t *testing.T) *admissionv1.AdmissionRequest {
+			return admissionRequest(t, admissionv1.Update, "alice", pending, approved)
+		},
+		wantAsked: true,
+	}, {
+		name:       "the policy denies the response",
+		authorizer: deny,
+		request: func(t *testing.T) *admissionv1.AdmissionRequest {
+			return admissionRequest(t, admissionv1.Update, "alice", pending, approved)
+		},
+		wantAsked:   true,
+		wantReason:  v1alpha1.DenialReasonPolicyDenied,
+		wantMessage: "denied by policy: outside of the change window",
+	}, {
+		name:       "the built-in checks deny before the policy is asked",
+		authorizer: allow,
+		request: func(t *testing.T) *admissionv1.AdmissionRequest {
+			return admissionRequest(t, admissionv1.Update, "bob", pending, approved)
+		},
+		wantReason: v1alpha1.DenialReasonNotAnApprover,
+	}, {
+		name:          "an outage denies the response when failing closed",
+		authorizer:    unavailable,
+		failurePolicy: policy.FailClosed,
+		request: func(t *testing.T) *admissionv1.AdmissionRequest {
+			return admissionRequest(t, admissionv1.Update, "alice", pending, approved)
+		},
+		wantAsked:   true,
+		wantReason:  v1alpha1.DenialReasonPolicyDenied,
+		wantMessage: "denied by policy: the policy decision is unavailable",
+	}, {
+		name:          "an outage allows the response when failing open",
+		authorizer:    unavailable,
+		failurePolicy: policy.FailOpen,
+		request: func(t *testing.T) *admissionv1.AdmissionRequest {
+			return admissionRequest(t, admissionv1.Update, "alice", pending, approved)
+		},
+		wantAsked: true,
+	}, {
+		name:          "creations are not sent to the policy",
+		authorizer:    unavailable,
+		failurePolicy: policy.FailClosed,
+		request: func(t *testing.T) *admissionv1.AdmissionRequest {
+			return admissionRequest(t, admissionv1.Create, testControllerUsername, nil, pending)
+		},
+	}, {
+		name:          "deletions by the garbage collector are not sent to the policy",
+		authorizer:    unavailable,
+		failurePolicy: policy.FailClosed,
+		request: func(t *testing.T) *admissionv1.AdmissionRequest {
+			return admissionRequest(t, admissionv1.Delete, "system:serviceaccount:kube-system:generic-garbage-collector", pending, nil)
+		},
+	}, {
+		name:          "updates of the webhook are not sent to the policy",
+		authorizer:    unavailable,
+		failurePolicy: policy.FailClosed,
+		request: func(t *testing.T) *admissionv1.AdmissionRequest {
+			return admissionRequest(t, admissionv1.Update, testWebhookUsername, pending, stamped)
+		},
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestReconciler(t)
+			var asked bool
+			hook := &policy.Hook{
+				Authorizer: policy.AuthorizerFunc(func(ctx context.Context, input policy.Input) (policy.Decision, error) {
+					asked = true
+					return tc.authorizer(ctx, input)
+				}),
+				FailurePolicy: tc.failurePolicy,
+			}
+
+			resp := r.Admit(policy.WithHook(context.Background(), hook), tc.request(t))
+			assert.Equal(t, tc.wantAsked, asked)
+			if tc.wantReason == "" {
+				assert.True(t, resp.Allowed, resp.Result)
+				return
+			}
+			assert.False(t, resp.Allowed)
+			assert.Equal(t, tc.wantReason, resp.Result.Reason)
+			if tc.wantMessage != "" {
+				assert.Equal(t, tc.wantMessage, resp.Result.Message)
+			}
+		})
+	}
+}
+
+func TestAdmitSideEffects(t *testing.T) {
+	pending := approvalTask(v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "pending"})
+	pending.Status.State = "pending"
+	approved := pending.DeepCopy()
+	approved.Spec.Approvers[0].Input = "approve"
+	// Denied, as the request is not impersonated
+	invalid := approved.DeepCopy()
+	invalid.Spec.Approvers[0].ImpersonatedBy = "admin"
+
+	tests := []struct {
+		name       string
+		newObj     *v1alpha1.ApprovalTask
+		dryRun     bool
+		deferred   bool
+		wantRecord bool
+		wantEvent  bool
+	}{{
+		name:       "approval",
+		newObj:     approved,
+		wantRecord: true,
+	}, {
+		name:   "dry-run approval",
+		newObj: approved,
+		dryRun: true,
+	}, {
+		name:     "approval of a subresource",
+		newObj:   approved,
+		deferred: true,
+	}, {
+		name:       "denial",
+		newObj:     invalid,
+		wantRecord: true,
+		wantEvent:  true,
+	}, {
+		name:   "dry-run denial",
+		newObj: invalid,
+		dryRun: true,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestReconciler(t)
+			recorder := record.NewFakeRecorder(10)
+			r.recorder = recorder
+			var log bytes.Buffer
+			ctx := audit.WithLogger(context.Background(), audit.NewLogger(audit.NewWriterSink(&log), []byte("key"), &audit.MemoryHeadStore{}))
+			if tc.deferred {
+				ctx = deferAudit(ctx)
+			}
+			request := admissionRequest(t, admissionv1.Update, "alice", pending, tc.newObj)
+			request.DryRun = &tc.dryRun
+
+			r.Admit(ctx, request)
+			assert.Equal(t, tc.wantRecord, log.Len() > 0, log.String())
+			assert.Equal(t, tc.wantEvent, len(recorder.Events) > 0)
+		})
+	}
+}
+
+func TestCheckOtherUsersForInvalidChangesOfExternalApprovers(t *testing.T) {
+	approvers := func(changeRequest v1alpha1.ApproverDetails) []v1alpha1.ApproverDetails {
+		return []v1alpha1.ApproverDetails{
+			{Name: "alice", Type: "User", Input: "approve"},
+			changeRequest,
+		}
+	}
+	oldApprovers := approvers(v1alpha1.ApproverDetails{Name: "CHG0001", Type: v1alpha1.ChangeRequestApproverType, Input: "pending"})
+	oldApprovers[0].Input = "pending"
+
+	tests := []struct {
+		name         string
+		newApprovers []v1alpha1.ApproverDetails
+		want         bool
+	}{{
+		name:         "unchanged",
+		newApprovers: approvers(v1alpha1.ApproverDetails{Name: "CHG0001", Type: v1alpha1.ChangeRequestApproverType, Input: "pending"}),
+		want:         true,
+	}, {
+		name:         "renamed",
+		newApprovers: approvers(v1alpha1.ApproverDetails{Name: "CHG0002", Type: v1alpha1.ChangeRequestApproverType, Input: "pending"}),
+	}, {
+		name:         "retyped",
+		newApprovers: approvers(v1alpha1.ApproverDetails{Name: "CHG0001", Type: "User", Input: "pending"}),
+	}, {
+		name:         "answered",
+		newApprovers: approvers(v1alpha1.ApproverDetails{Name: "CHG0001", Type: v1alpha1.ChangeRequestApproverType, Input: "approve"}),
+	}, {
+		name: "added",
+		newApprovers: append(approvers(v1alpha1.ApproverDetails{Name: "CHG0001", Type: v1alpha1.ChangeRequestApproverType, Input: "pending"}),
+			v1alpha1.ApproverDetails{Name: "new", Type: v1alpha1.ChangeRequestApproverType, Input: "pending"}),
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, CheckOtherUsersForInvalidChanges(oldApprovers, tc.newApprovers, isUserNamed("alice"), isNoMember))
+		})
+	}
+
+	// An approver of the user cannot become external either
+	retyped := []v1alpha1.ApproverDetails{{Name: "alice", Type: v1alpha1.ChangeRequestApproverType, Input: "pending"}}
+	assert.False(t, CheckOtherUsersForInvalidChanges([]v1alpha1.ApproverDetails{{Name: "alice", Type: "User", Input: "pending"}}, retyped, isUserNamed("alice"), isNoMember))
+}
+
+func TestCheckOtherUsersForInvalidChangesOfResponses(t *testing.T) {
+	responder := &v1alpha1.Responder{Username: "bob", Time: metav1.NewTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))}
+	approvers := func(mutate func([]v1alpha1.ApproverDetails)) []v1alpha1.ApproverDetails {
+		approvers := []v1alpha1.ApproverDetails{
+			{Name: "alice", Type: "User", Input: "pending"},
+			{Name: "bob", Type: "User", Input: "approve", Message: "lgtm", Signature: "sig-bob", Responder: responder},
+			{Name: "dev", Type: "Group", Input: "pending", Message: "group", Users: []v1alpha1.UserDetails{
+				{Name: "carol", Input: "approve", Message: "ok", Signature: "sig-carol", Responder: responder},
+			}},
+		}
+		if mutate != nil {
+			mutate(approvers)
+		}
+		return approvers
+	}
+
+	tests := []struct {
+		name   string
+		mutate func([]v1alpha1.ApproverDetails)
+		want   bool
+	}{{
+		name: "own response",
+		mutate: func(a []v1alpha1.ApproverDetails) {
+			a[0].Input, a[0].Message, a[0].Signature = "approve", "mine", "sig-alice"
+		},
+		want: true,
+	}, {
+		name:   "message of another approver",
+		mutate: func(a []v1alpha1.ApproverDetails) { a[1].Message = "rewritten" },
+	}, {
+		name:   "signature of another approver",
+		mutate: func(a []v1alpha1.ApproverDetails) { a[1].Signature = "" },
+	}, {
+		name:   "responder of another approver",
+		mutate: func(a []v1alpha1.ApproverDetails) { a[1].Responder = &v1alpha1.Responder{Username: "alice"} },
+	}, {
+		name:   "message of a member of a group",
+		mutate: func(a []v1alpha1.ApproverDetails) { a[2].Users[0].Message = "rewritten" },
+	}, {
+		name:   "signature of a member of a group",
+		mutate: func(a []v1alpha1.ApproverDetails) { a[2].Users[0].Signature = "forged" },
+	}, {
+		name:   "responder of a member of a group",
+		mutate: func(a []v1alpha1.ApproverDetails) { a[2].Users[0].Responder = nil },
+	}, {
+		name:   "message of a group the user is not a member of",
+		mutate: func(a []v1alpha1.ApproverDetails) { a[2].Message = "rewritten" },
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, CheckOtherUsersForInvalidChanges(approvers(nil), approvers(tc.mutate), isUserNamed("alice"), isNoMember))
+		})
+	}
+}
+
+// countingDirectory lists the members of its groups and counts the lookups.
+type countingDirectory struct {
+	members map[string][]string
+	calls   int
+}
+
+func (d *countingDirectory) Members(_ context.Context, group string) ([]string, error) {
+	d.calls++
+	return d.members[group], nil
+}
+
+func TestGroupMembershipIsOnlyResolvedForIndirectApprovers(t *testing.T) {
+	approvers := []v1alpha1.ApproverDetails{
+		{Name: "alice", Type: "User"},
+		{Name: "release", Type: "Group"},
+	}
+
+	tests := []struct {
+		username   string
+		wantMember bool
+		wantCalls  int
+	}{
+		{username: "alice", wantMember: false, wantCalls: 0},
+		{username: "bob", wantMember: true, wantCalls: 1},
+		{username: "carol", wantMember: false, wantCalls: 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.username, func(t *testing.T) {
+			directory := &countingDirectory{members: map[string][]string{"release": {"alice", "bob"}}}
+			r := &reconciler{groupResolver: directory}
+			request := &admissionv1.AdmissionRequest{UserInfo: authenticationv1.UserInfo{Username: tc.username}}
+
+			isMember, err := r.groupMembership(context.Background(), request, approvers)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantMember, isMember(approvers[1]))
+			assert.Equal(t, tc.wantCalls, directory.calls)
+		})
+	}
+}
+
+func TestStampResponders(t *testing.T) {
+	user := authenticationv1.UserInfo{Username: "alice", UID: "uid-alice", Groups: []string{"dev"}}
+	now := metav1.NewTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	stamp := &v1alpha1.Responder{Username: "alice", UID: "uid-alice", Groups: []string{"dev"}, Time: now}
+	previous := &v1alpha1.Responder{Username: "bob", UID: "uid-bob", Time: metav1.NewTime(now.Add(-time.Hour))}
+	forged := &v1alpha1.Responder{Username: "carol", Time: now}
+
+	tests := []struct {
+		name         string
+		oldObj       *v1alpha1.ApprovalTask
+		newObj       *v1alpha1.ApprovalTask
+		want         *v1alpha1.Responder
+		wantMember   *v1alpha1.Responder
+		checkMembers bool
+	}{{
+		name:   "create drops the stamps",
+		newObj: approvalTask(v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "pending", Responder: forged}),
+	}, {
+		name:   "changed input is stamped",
+		oldObj: approvalTask(v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "pending"}),
+		newObj: approvalTask(v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "approve", Responder: forged}),
+		want:   stamp,
+	}, {
+		name:   "changed message is stamped",
+		oldObj: approvalTask(v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "approve", Responder: previous}),
+		newObj: approvalTask(v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "approve", Message: "lgtm"}),
+		want:   stamp,
+	}, {
+		name:   "unchanged entry keeps its stamp",
+		oldObj: approvalTask(v1alpha1.ApproverDetails{Name: "bob", Type: "User", Input: "approve", Responder: previous}),
+		newObj: approvalTask(v1alpha1.ApproverDetails{Name: "bob", Type: "User", Input: "approve", Responder: forged}),
+		want:   previous,
+	}, {
+		name: "changed group member is stamped",
+		oldObj: approvalTask(v1alpha1.ApproverDetails{Name: "dev", Type: "Group", Input: "pending",
+			Users: []v1alpha1.UserDetails{{Name: "alice", Input: "pending"}}}),
+		newObj: approvalTask(v1alpha1.ApproverDetails{Name: "dev", Type: "Group", Input: "pending",
+			Users: []v1alpha1.UserDetails{{Name: "alice", Input: "approve", Responder: forged}}}),
+		checkMembers: true,
+		wantMember:   stamp,
+	}, {
+		name:   "changed message of another approver keeps its stamp",
+		oldObj: approvalTask(v1alpha1.ApproverDetails{Name: "bob", Type: "User", Input: "approve", Responder: previous}),
+		newObj: approvalTask(v1alpha1.ApproverDetails{Name: "bob", Type: "User", Input: "approve", Message: "edited by alice"}),
+		want:   previous,
+	}, {
+		name: "changed member of another approver keeps its stamp",
+		oldObj: approvalTask(v1alpha1.ApproverDetails{Name: "dev", Type: "Group", Input: "pending",
+			Users: []v1alpha1.UserDetails{{Name: "bob", Input: "approve", Responder: previous}}}),
+		newObj: approvalTask(v1alpha1.ApproverDetails{Name: "dev", Type: "Group", Input: "pending",
+			Users: []v1alpha1.UserDetails{{Name: "bob", Input: "approve", Message: "edited by alice"}}}),
+		checkMembers: true,
+		wantMember:   previous,
+	}, {
+		name:   "changed group of another approver is not stamped",
+		oldObj: approvalTask(v1alpha1.ApproverDetails{Name: "qa", Type: "Group", Input: "pending"}),
+		newObj: approvalTask(v1alpha1.ApproverDetails{Name: "qa", Type: "Group", Input: "approve"}),
+	}, {
+		name:   "changed group of the user is stamped",
+		oldObj: approvalTask(v1alpha1.ApproverDetails{Name: "dev", Type: "Group", Input: "pending"}),
+		newObj: approvalTask(v1alpha1.ApproverDetails{Name: "dev", Type: "Group", Input: "approve"}),
+		want:   stamp,
+	}, {
+		name:   "changed input under another username of the user is stamped",
+		oldObj: approvalTask(v1alpha1.ApproverDetails{Name: "Alice@corp.com", Type: "User", Input: "pending"}),
+		newObj: approvalTask(v1alpha1.ApproverDetails{Name: "Alice@corp.com", Type: "User", Input: "approve"}),
+		want:   stamp,
+	}}
+	config, err := identity.ParseConfig(map[string]string{"caseInsensitive": "true", "stripEmailDomains": "corp.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	isUser := config.Matcher(user.Username)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			stampResponders(tc.oldObj, tc.newObj, user, isUser, listedMember(user, isUser), now)
+			assert.Equal(t, tc.want, tc.newObj.Spec.Approvers[0].Responder)
+			if tc.checkMembers {
+				assert.Equal(t, tc.wantMember, tc.newObj.Spec.Approvers[0].Users[0].Responder)
+			}
+		})
+	}
+}
+
+func TestVerifyResponders(t *testing.T) {
+	user := authenticationv1.UserInfo{Username: "alice", UID: "uid-alice", Groups: []string{"dev"}}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	previous := &v1alpha1.Responder{Username: "bob", UID: "uid-bob", Time: metav1.NewTime(now.Add(-time.Hour))}
+	stamp := func(mutate func(*v1alpha1.Responder)) *v1alpha1.Responder {
+		responder := &v1alpha1.Responder{Username: "alice", UID: "uid-alice", Groups: []string{"dev"}, Time: metav1.NewTime(now)}
+		if mutate != nil {
+			mutate(responder)
+		}
+		return responder
+	}
+
+	tests := []struct {
+		name      string
+		old       *v1alpha1.Responder
+		responder *v1alpha1.Responder
+		wantDeny  bool
+	}{{
+		name:      "unchanged stamp",
+		old:       previous,
+		responder: previous,
+	}, {
+		name:      "stamp of the user",
+		responder: stamp(nil),
+	}, {
+		name:      "stamped shortly before",
+		responder: stamp(func(r *v1alpha1.Responder) { r.Time = metav1.NewTime(now.Add(-30 * time.Second)) }),
+	}, {
+		name:     "removed stamp",
+		old:      previous,
+		wantDeny: true,
+	}, {
+		name:      "other username",
+		responder: stamp(func(r *v1alpha1.Responder) { r.Username = "bob" }),
+		wantDeny:  true,
+	}, {
+		name:      "other UID",
+		responder: stamp(func(r *v1alpha1.Responder) { r.UID = "uid-bob" }),
+		wantDeny:  true,
+	}, {
+		name:      "other groups",
+		responder: stamp(func(r *v1alpha1.Responder) { r.Groups = []string{"dev", "admins"} }),
+		wantDeny:  true,
+	}, {
+		name:      "stale time",
+		responder: stamp(func(r *v1alpha1.Responder) { r.Time = metav1.NewTime(now.Add(-time.Hour)) }),
+		wantDeny:  true,
+	}, {
+		name:      "future time",
+		responder: stamp(func(r *v1alpha1.Responder) { r.Time = metav1.NewTime(now.Add(time.Hour)) }),
+		wantDeny:  true,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			oldObj := approvalTask(v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "pending", Responder: tc.old})
+			newObj := approvalTask(v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "approve", Responder: tc.responder})
+
+			denied := verifyResponders(oldObj, newObj, user, now)
+			assert.Equal(t, tc.wantDeny, denied != nil)
+			if denied != nil {
+				assert.Equal(t, v1alpha1.DenialReasonInvalidInput, denied.Result.Reason)
+			}
+		})
+	}
+
+	t.Run("group member", func(t *testing.T) {
+		oldObj := approvalTask(v1alpha1.ApproverDetails{Name: "dev", Type: "Group", Input: "pending",
+			Users: []v1alpha1.UserDetails{{Name: "alice", Input: "pending"}}})
+		newObj := approvalTask(v1alpha1.ApproverDetails{Name: "dev", Type: "Group", Input: "pending",
+			Users: []v1alpha1.UserDetails{{Name: "alice", Input: "approve", Responder: stamp(func(r *v1alpha1.Responder) { r.UID = "uid-bob" })}}})
+
+		assert.NotNil(t, verifyResponders(oldObj, newObj, user, now))
+	})
+}
+
+func TestAdmitSubresourceUpdate(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	stamp := func(username string) *v1alpha1.Responder {
+		return &v1alpha1.Responder{Username: username, Time: metav1.NewTime(now)}
+	}
+	pending := approvalTask(
+		v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "pending"},
+		v1alpha1.ApproverDetails{Name: "bob", Type: "User", Input: "pending"},
+		v1alpha1.ApproverDetails{Name: "dev", Type: "Group", Input: "pending",
+			Users: []v1alpha1.UserDetails{{Name: "carol", Input: "pending"}}},
+	)
+
+	tests := []struct {
+		name       string
+		mutate     func(*v1alpha1.ApprovalTask)
+		wantReason metav1.StatusReason
+	}{{
+		name: "response of the user",
+		mutate: func(at *v1alpha1.ApprovalTask) {
+			at.Spec.Approvers[0].Input = "approve"
+			at.Spec.Approvers[0].Responder = stamp("alice")
+		},
+	}, {
+		name: "response of a group member",
+		mutate: func(at *v1alpha1.ApprovalTask) {
+			at.Spec.Approvers[2].Input = "approve"
+			at.Spec.Approvers[2].Responder = stamp("dave")
+			at.Spec.Approvers[2].Users = append(at.Spec.Approvers[2].Users,
+				v1alpha1.UserDetails{Name: "dave", Input: "approve", Responder: stamp("dave")})
+		},
+	}, {
+		name: "response of another approver",
+		mutate: func(at *v1alpha1.ApprovalTask) {
+			at.Spec.Approvers[0].Input = "approve"
+			at.Spec.Approvers[0].Responder = stamp("alice")
+			at.Spec.Approvers[1].Input = "approve"
+		},
+		wantReason: v1alpha1.DenialReasonForeignChange,
+	}, {
+		name: "response of another group member",
+		mutate: func(at *v1alpha1.ApprovalTask) {
+			at.Spec.Approvers[0].Input = "approve"
+			at.Spec.Approvers[0].Responder = stamp("alice")
+			at.Spec.Approvers[2].Users[0].Input = "approve"
+		},
+		wantReason: v1alpha1.DenialReasonForeignChange,
+	}, {
+		name: "unstamped response",
+		mutate: func(at *v1alpha1.ApprovalTask) {
+			at.Spec.Approvers[1].Input = "approve"
+		},
+		wantReason: v1alpha1.DenialReasonForeignChange,
+	}, {
+		name: "responses of two users",
+		mutate: func(at *v1alpha1.ApprovalTask) {
+			at.Spec.Approvers[0].Input = "approve"
+			at.Spec.Approvers[0].Responder = stamp("alice")
+			at.Spec.Approvers[1].Input = "approve"
+			at.Spec.Approvers[1].Responder = stamp("bob")
+		},
+		wantReason: v1alpha1.DenialReasonForeignChange,
+	}, {
+		name: "removed approver",
+		mutate: func(at *v1alpha1.ApprovalTask) {
+			at.Spec.Approvers = at.Spec.Approvers[:2]
+			at.Spec.Approvers[0].Input = "approve"
+			at.Spec.Approvers[0].Responder = stamp("alice")
+		},
+		wantReason: v1alpha1.DenialReasonForeignChange,
+	}, {
+		name: "removed group member",
+		mutate: func(at *v1alpha1.ApprovalTask) {
+			at.Spec.Approvers[2].Input = "approve"
+			at.Spec.Approvers[2].Responder = stamp("dave")
+			at.Spec.Approvers[2].Users = []v1alpha1.UserDetails{{Name: "dave", Input: "approve", Responder: stamp("dave")}}
+		},
+		wantReason: v1alpha1.DenialReasonForeignChange,
+	}, {
+		name: "changed spec",
+		mutate: func(at *v1alpha1.ApprovalTask) {
+			at.Spec.Approvers[0].Input = "approve"
+			at.Spec.Approvers[0].Responder = stamp("alice")
+			at.Spec.NumberOfApprovalsRequired = 2
+		},
+		wantReason: v1alpha1.DenialReasonForeignChange,
+	}, {
+		name: "changed labels",
+		mutate: func(at *v1alpha1.ApprovalTask) {
+			at.Spec.Approvers[0].Input = "approve"
+			at.Spec.Approvers[0].Responder = stamp("alice")
+			at.Labels = map[string]string{"foo": "bar"}
+		},
+		wantReason: v1alpha1.DenialReasonForeignChange,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestReconciler(t)
+			updated := pending.DeepCopy()
+			tc.mutate(updated)
+
+			response := r.admitSubresourceUpdate(admissionRequest(t, admissionv1.Update, testWebhookUsername, pending, updated), now)
+			if tc.wantReason == "" {
+				assert.True(t, response.Allowed, response.Result)
+				return
+			}
+			assert.False(t, response.Allowed)
+			assert.Equal(t, tc.wantReason, response.Result.Reason)
+		})
+	}
+}
+
+func TestAdmitStatus(t *testing.T) {
+	pending := approvalTask(v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "pending"})
+	pending.Status.State = "pending"
+	approved := pending.DeepCopy()
+	approved.Status.State = "approved"
+
+	tests := []struct {
+		name        string
+		username    string
+		wantAllowed bool
+	}{{
+		name:        "controller",
+		username:    testControllerUsername,
+		wantAllowed: true,
+	}, {
+		name:     "approver",
+		username: "alice",
+	}, {
+		name:     "webhook",
+		username: testWebhookUsername,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestReconciler(t)
+			request := admissionRequest(t, admissionv1.Update, tc.username, pending, approved)
+			request.SubResource = "status"
+
+			response := r.Admit(context.Background(), request)
+			assert.Equal(t, tc.wantAllowed, response.Allowed)
+			if !tc.wantAllowed {
+				assert.Equal(t, v1alpha1.DenialReasonForeignChange, response.Result.Reason)
+			}
+		})
+	}
+}
+
+func TestAdmitDelete(t *testing.T) {
+	pending := approvalTask(v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "pending"})
+	pending.Status.State = "pending"
+	approved := pending.DeepCopy()
+	approved.Status.State = "approved"
+
+	tests := []struct {
+		name        string
+		oldObj      *v1alpha1.ApprovalTask
+		username    string
+		breakGlass  bool
+		wantAllowed bool
+		wantEvent   bool
+	}{{
+		name:     "pending by a user",
+		oldObj:   pending,
+		username: "alice",
+	}, {
+		name:        "pending by the garbage collector",
+		oldObj:      pending,
+		username:    "system:serviceaccount:kube-system:generic-garbage-collector",
+		wantAllowed: true,
+	}, {
+		name:        "pending by the controller",
+		oldObj:      pending,
+		username:    testControllerUsername,
+		wantAllowed: true,
+	}, {
+		name:        "pending with the break-glass verb",
+		oldObj:      pending,
+		username:    "admin",
+		breakGlass:  true,
+		wantAllowed: true,
+		wantEvent:   true,
+	}, {
+		name:        "approved by a user",
+		oldObj:      approved,
+		username:    "alice",
+		wantAllowed: true,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestReconciler(t)
+			recorder := record.NewFakeRecorder(10)
+			r.recorder = recorder
+			var reviewed *authorizationv1.SubjectAccessReview
+			r.client.(*fakekube.Clientset).PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				reviewed = action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+				reviewed.Status.Allowed = tc.breakGlass
+				return true, reviewed, nil
+			})
+
+			response := r.Admit(context.Background(), admissionRequest(t, admissionv1.Delete, tc.username, tc.oldObj, nil))
+			assert.Equal(t, tc.wantAllowed, response.Allowed)
+			if !tc.wantAllowed {
+				assert.Equal(t, v1alpha1.DenialReasonPolicyDenied, response.Result.Reason)
+			}
+			if reviewed != nil {
+				assert.Equal(t, v1alpha1.DeletePendingVerb, reviewed.Spec.ResourceAttributes.Verb)
+				assert.Equal(t, tc.username, reviewed.Spec.User)
+			}
+			var events []string
+			for len(recorder.Events) > 0 {
+				events = append(events, <-recorder.Events)
+			}
+			if tc.wantEvent {
+				assert.Len(t, events, 1)
+				assert.Contains(t, events[0], EventReasonBreakGlassDeletion)
+			} else {
+				for _, event := range events {
+					assert.False(t, strings.Contains(event, EventReasonBreakGlassDeletion), event)
+				}
+			}
+		})
+	}
+}
+
+func TestAdmitDenialReasons(t *testing.T) {
+	task := func(state string, approvers ...v1alpha1.ApproverDetails) *v1alpha1.ApprovalTask {
+		at := approvalTask(approvers...)
+		at.Spec.NumberOfApprovalsRequired = 2
+		at.Status.State = state
+		return at
+	}
+	alice := v1alpha1.ApproverDetails{Name: "alice", Type: "User", Input: "pending"}
+	bob := v1alpha1.ApproverDetails{Name: "bob", Type: "User", Input: "pending"}
+	with := func(approver v1alpha1.ApproverDetails, mutate func(*v1alpha1.ApproverDetails)) v1alpha1.ApproverDetails {
+		mutate(&approver)
+		return approver
+	}
+	approve := func(a *v1alpha1.ApproverDetails) { a.Input = "approve" }
+	reject := func(a *v1alpha1.ApproverDetails) { a.Input = "reject" }
+	decided := task("pending", with(alice, approve), bob)
+	decided.Status.ApproversResponse = []v1alpha1.ApproverState{{Name: "alice", Type: "User", Response: "approved"}}
+
+	tests := []struct {
+		name        string
+		operation   admissionv1.Operation
+		subresource string
+		username    string
+		oldObj      *v1alpha1.ApprovalTask
+		newObj      *v1alpha1.ApprovalTask
+		wantReason  metav1.StatusReason
+		wantCode    int32
+	}{{
+		name:       "not an approver",
+		operation:  admissionv1.Update,
+		username:   "carol",
+		oldObj:     task("pending", alice, bob),
+		newObj:     task("pending", with(alice, approve), bob),
+		wantReason: v1alpha1.DenialReasonNotAnApprover,
+		wantCode:   http.StatusForbidden,
+	}, {
+		name:       "change of another approver",
+		operation:  admissionv1.Update,
+		username:   "alice",
+		oldObj:     task("pending", alice, bob),
+		newObj:     task("pending", alice, with(bob, approve)),
+		wantReason: v1alpha1.DenialReasonForeignChange,
+		wantCode:   http.StatusForbidden,
+	}, {
+		name:        "status by an approver",
+		operation:   admissionv1.Update,
+		subresource: "status",
+		username:    "alice",
+		oldObj:      task("pending", alice, bob),
+		newObj:      task("approved", alice, bob),
+		wantReason:  v1alpha1.DenialReasonForeignChange,
+		wantCode:    http.StatusForbidden,
+	}, {
+		name:       "already decided",
+		operation:  admissionv1.Update,
+		username:   "alice",
+		oldObj:     decided,
+		newObj:     task("pending", with(alice, approve), bob),
+		wantReason: v1alpha1.DenialReasonAlreadyDecided,
+		wantCode:   http.StatusConflict,
+	}, {
+		name:       "final state",
+		operation:  admissionv1.Update,
+		username:   "alice",
+		oldObj:     task("rejected", alice, with(bob, reject)),
+		newObj:     task("rejected", with(alice, approve), with(bob, reject)),
+		wantReason: v1alpha1.DenialReasonFinalState,
+		wantCode:   http.StatusConflict,
+	}, {
+		// The controller rejects the ApprovalTask of a cancelled CustomRun
+		name:       "approve after cancellation",
+		operation:  admissionv1.Update,
+		username:   "bob",
+		oldObj:     task("rejected", with(alice, approve), bob),
+		newObj:     task("rejected", with(alice, approve), with(bob, approve)),
+		wantReason: v1alpha1.DenialReasonFinalState,
+		wantCode:   http.StatusConflict,
+	}, {
+		name:       "invalid input",
+		operation:  admissionv1.Update,
+		username:   "alice",
+		oldObj:     task("pending", alice, bob),
+		newObj:     task("pending", with(alice, func(a *v1alpha1.ApproverDetails) { a.Input = "maybe" }), bob),
+		wantReason: v1alpha1.DenialReasonInvalidInput,
+		wantCode:   http.StatusUnprocessableEntity,
+	}, {
+		name:       "delete of a pending task",
+		operation:  admissionv1.Delete,
+		username:   "alice",
+		oldObj:     task("pending", alice, bob),
+		wantReason: v1alpha1.DenialReasonPolicyDenied,
+		wantCode:   http.StatusForbidden,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestReconciler(t)
+			request := admissionRequest(t, tc.operation, tc.username, tc.oldObj, tc.newObj)
+			request.SubResource = tc.subresource
+			request.Resource = metav1.GroupVersionResource{Group: Group, Version: Version, Resource: "approvaltasks"}
+
+			response := r.Admit(context.Background(), request)
+			assert.False(t, response.Allowed)
+			assert.Equal(t, tc.wantReason, response.Result.Reason, response.Result.Message)
+			assert.Equal(t, tc.wantCode, response.Result.Code)
+			assert.Equal(t, &metav1.StatusDetails{Name: "at-1", Group: Group, Kind: "approvaltasks"}, response.Result.Details)
+		})
+	}
+
+	record := func(decision string) *v1alpha1.ApprovalRecord {
+		return &v1alpha1.ApprovalRecord{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "at-1",
+				Namespace: "foo",
+				Labels:    map[string]string{v1alpha1.ApprovalTaskLabelKey: "at-1", v1alpha1.ApprovalRecordDecisionLabelKey: decision},
+			},
+			Spec: v1alpha1.ApprovalRecordSpec{Decision: decision},
+		}
+	}
+	relabeled := record("rejected")
+	relabeled.Labels[v1alpha1.ApprovalRecordDecisionLabelKey] = "approved"
+	records := []struct {
+		name       string
+		operation  admissionv1.Operation
+		username   string
+		oldObj     *v1alpha1.ApprovalRecord
+		newObj     *v1alpha1.ApprovalRecord
+		wantReason metav1.StatusReason
+		wantCode   int32
+	}{{
+		name:       "record created by a user",
+		operation:  admissionv1.Create,
+		username:   "alice",
+		newObj:     record("approved"),
+		wantReason: v1alpha1.DenialReasonPolicyDenied,
+		wantCode:   http.StatusForbidden,
+	}, {
+		name:       "record spec changed",
+		operation:  admissionv1.Update,
+		username:   testControllerUsername,
+		oldObj:     record("rejected"),
+		newObj:     record("approved"),
+		wantReason: v1alpha1.DenialReasonFinalState,
+		wantCode:   http.StatusConflict,
+	}, {
+		name:       "record label changed",
+		operation:  admissionv1.Update,
+		username:   "alice",
+		oldObj:     record("rejected"),
+		newObj:     relabeled,
+		wantReason: v1alpha1.DenialReasonFinalState,
+		wantCode:   http.StatusConflict,
+	}}
+	for _, tc := range records {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestReconciler(t)
+			request := &admissionv1.AdmissionRequest{
+				Kind:      metav1.GroupVersionKind{Group: Group, Version: Version, Kind: RecordKind},
+				Resource:  metav1.GroupVersionResource{Group: Group, Version: Version, Resource: "approvalrecords"},
+				Operation: tc.operation,
+				Namespace: "foo",
+				Name:      "at-1",
+				UserInfo:  authenticationv1.UserInfo{Username: tc.username},
+			}
+			for obj, raw := range map[*v1alpha1.ApprovalRecord]*runtime.RawExtension{tc.oldObj: &request.OldObject, tc.newObj: &request.Object} {
+				if obj == nil {
+					continue
+				}
+				b, err := json.Marshal(obj)
+				if err != nil {
+					t.Fatal(err)
+				}
+				raw.Raw = b
+			}
+
+			response := r.Admit(context.Background(), request)
+			assert.False(t, response.Allowed)
+			assert.Equal(t, tc.wantReason, response.Result.Reason, response.Result.Message)
+			assert.Equal(t, tc.wantCode, response.Result.Code)
+			assert.Equal(t, &metav1.StatusDetails{Name: "at-1", Group: Group, Kind: "approvalrecords"}, response.Result.Details)
+		})
+	}
+
+	// The controller creates them
+	r := newTestReconciler(t)
+	request := &admissionv1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: Group, Version: Version, Kind: RecordKind},
+		Operation: admissionv1.Create,
+		UserInfo:  authenticationv1.UserInfo{Username: testControllerUsername},
+	}
+	assert.True(t, r.admitApprovalRecord(context.Background(), request).Allowed)
+}
+
+func TestDenyCodes(t *testing.T) {
+	tests := map[metav1.StatusReason]int32{
+		v1alpha1.DenialReasonNotAnApprover:  http.StatusForbidden,
+		v1alpha1.DenialReasonForeignChange:  http.StatusForbidden,
+		v1alpha1.DenialReasonPolicyDenied:   http.StatusForbidden,
+		v1alpha1.DenialReasonAlreadyDecided: http.StatusConflict,
+		v1alpha1.DenialReasonFinalState:     http.StatusConflict,
+		v1alpha1.DenialReasonInvalidInput:   http.StatusUnprocessableEntity,
+	}
+	for reason, code := range tests {
+		response := deny(reason, "denied %s", "at-1")
+		assert.False(t, response.Allowed, reason)
+		assert.Equal(t, reason, response.Result.Reason)
+		assert.Equal(t, code, response.Result.Code, reason)
+		assert.Equal(t, "denied at-1", response.Result.Message)
+		assert.Equal(t, string(reason), denialReason(response))
+	}
+	assert.Len(t, denialCodes, len(tests))
+}
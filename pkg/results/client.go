@@ -0,0 +1,182 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package results publishes ApprovalRecords to Tekton Results, the long-term
+// store of the history of PipelineRuns, and reads them back.
+package results
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// ServiceName is the gRPC service of the Results API
+	ServiceName = "tekton.results.v1alpha2.Results"
+
+	// RecordType is the type of the Records holding ApprovalRecords
+	RecordType = "openshift-pipelines.org/v1alpha1.ApprovalRecord"
+)
+
+// ResultName returns the name of the Result id of namespace. The Results
+// watcher names the Result of a PipelineRun after its UID.
+func ResultName(namespace, id string) string {
+	return namespace + "/results/" + id
+}
+
+// RecordName returns the name of the Record id of the Result parent.
+func RecordName(parent, id string) string {
+	return parent + "/records/" + id
+}
+
+// Options configure the connection to the Results API.
+type Options struct {
+	// Address of the Results API, e.g.
+	// tekton-results-api-service.tekton-pipelines.svc.cluster.local:8080
+	Address string
+	// Token returns the bearer token of each call, nil for none
+	Token func() (string, error)
+	// CAFile is the CA bundle of the Results API, the system roots when empty
+	CAFile string
+	// Insecure disables TLS, for tests and port-forwards only
+	Insecure bool
+}
+
+// Client calls the Results API.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// NewClient returns a Client of the Results API.
+func NewClient(opts Options) (*Client, error) {
+	dialOpts := []grpc.DialOption{grpc.WithDefaultCallOptions(grpc.ForceCodec(codec{}))}
+	if opts.Insecure {
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	} else {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if opts.CAFile != "" {
+			pem, err := os.ReadFile(opts.CAFile)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificate found in %s", opts.CAFile)
+			}
+		}
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	}
+	if opts.Token != nil {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(tokenCredentials{token: opts.Token, secure: !opts.Insecure}))
+	}
+
+	conn, err := grpc.NewClient(opts.Address, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// tokenCredentials authenticates the calls with a bearer token, read again
+// for every call since ServiceAccount tokens are rotated.
+type tokenCredentials struct {
+	token  func() (string, error)
+	secure bool
+}
+
+func (t tokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	token, err := t.token()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "Bearer " + strings.TrimSpace(token)}, nil
+}
+
+func (t tokenCredentials) RequireTransportSecurity() bool {
+	return t.secure
+}
+
+// TokenFile returns a Token function reading the token from path, e.g. the
+// token of the ServiceAccount of the pod.
+func TokenFile(path string) func() (string, error) {
+	return func() (string, error) {
+		b, err := os.ReadFile(path)
+		return string(b), err
+	}
+}
+
+func (c *Client) invoke(ctx context.Context, method string, req, resp message) error {
+	return c.conn.Invoke(ctx, "/"+ServiceName+"/"+method, req, resp)
+}
+
+// GetResult returns the Result name.
+func (c *Client) GetResult(ctx context.Context, name string) (*Result, error) {
+	resp := &Result{}
+	return resp, c.invoke(ctx, "GetResult", &GetResultRequest{Name: name}, resp)
+}
+
+// CreateResult creates a Result in the namespace parent.
+func (c *Client) CreateResult(ctx context.Context, parent string, result *Result) (*Result, error) {
+	resp := &Result{}
+	return resp, c.invoke(ctx, "CreateResult", &CreateResultRequest{Parent: parent, Result: result}, resp)
+}
+
+// CreateRecord creates a Record in the Result parent.
+func (c *Client) CreateRecord(ctx context.Context, parent string, record *Record) (*Record, error) {
+	resp := &Record{}
+	return resp, c.invoke(ctx, "CreateRecord", &CreateRecordRequest{Parent: parent, Record: record}, resp)
+}
+
+// ListRecords returns a page of Records.
+func (c *Client) ListRecords(ctx context.Context, req *ListRecordsRequest) (*ListRecordsResponse, error) {
+	resp := &ListRecordsResponse{}
+	return resp, c.invoke(ctx, "ListRecords", req, resp)
+}
+
+// Publish stores data as the Record recordID of the Result resultID of
+// namespace, creating the Result unless the Results watcher already did. It
+// returns the name of the Record, and succeeds when it already exists.
+func (c *Client) Publish(ctx context.Context, namespace, resultID, recordID string, data *Any) (string, error) {
+	parent := ResultName(namespace, resultID)
+	if _, err := c.GetResult(ctx, parent); status.Code(err) == codes.NotFound {
+		if _, err := c.CreateResult(ctx, namespace, &Result{Name: parent}); err != nil && status.Code(err) != codes.AlreadyExists {
+			return "", fmt.Errorf("failed to create Result %s: %w", parent, err)
+		}
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get Result %s: %w", parent, err)
+	}
+
+	name := RecordName(parent, recordID)
+	if _, err := c.CreateRecord(ctx, parent, &Record{Name: name, Data: data}); err != nil && status.Code(err) != codes.AlreadyExists {
+		return "", fmt.Errorf("failed to create Record %s: %w", name, err)
+	}
+	return name, nil
+}
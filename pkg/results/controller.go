@@ -0,0 +1,203 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package results
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	approvaltaskclientset "github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned"
+	approvaltaskclient "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/client"
+	approvalrecordinformer "github.com/openshift-pipelines/manual-approval-gate/pkg/client/injection/informers/approvaltask/v1alpha1/approvalrecord"
+	listersapprovaltask "github.com/openshift-pipelines/manual-approval-gate/pkg/client/listers/approvaltask/v1alpha1"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+const (
+	// RecordAnnotation is set on the ApprovalRecord to the name of its Record
+	// once published, like the Results watcher does on PipelineRuns
+	RecordAnnotation = "results.tekton.dev/record"
+
+	// AddressEnv holds the address of the Results API, read by the
+	// controller and by the history command of the CLI
+	AddressEnv = "RESULTS_API_ADDR"
+
+	caFileEnv    = "RESULTS_CA_FILE"
+	tokenFileEnv = "RESULTS_TOKEN_FILE"
+
+	defaultTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// Reconciler publishes ApprovalRecords to Tekton Results.
+type Reconciler struct {
+	pkgreconciler.LeaderAwareFuncs
+
+	results               *Client
+	approvaltaskClientSet approvaltaskclientset.Interface
+	lister                listersapprovaltask.ApprovalRecordLister
+}
+
+var _ controller.Reconciler = (*Reconciler)(nil)
+var _ pkgreconciler.LeaderAware = (*Reconciler)(nil)
+
+// NewController returns a controller.Impl publishing the ApprovalRecords to
+// the Results API at RESULTS_API_ADDR. Nothing is published when it is unset.
+func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	logger := logging.FromContext(ctx)
+	approvalrecordInformer := approvalrecordinformer.Get(ctx)
+	lister := approvalrecordInformer.Lister()
+
+	r := &Reconciler{
+		LeaderAwareFuncs: pkgreconciler.LeaderAwareFuncs{
+			// Enqueue every ApprovalRecord owned by a bucket when we become its leader.
+			PromoteFunc: func(bkt pkgreconciler.Bucket, enq func(pkgreconciler.Bucket, types.NamespacedName)) error {
+				all, err := lister.List(labels.Everything())
+				if err != nil {
+					return err
+				}
+				for _, record := range all {
+					enq(bkt, types.NamespacedName{Namespace: record.Namespace, Name: record.Name})
+				}
+				return nil
+			},
+		},
+		approvaltaskClientSet: approvaltaskclient.Get(ctx),
+		lister:                lister,
+	}
+	impl := controller.NewContext(ctx, r, controller.ControllerOptions{WorkQueueName: "TektonResults", Logger: logger.Named("TektonResults")})
+
+	address := os.Getenv(AddressEnv)
+	if address == "" {
+		logger.Infof("%s is not set, ApprovalRecords are not published to Tekton Results", AddressEnv)
+		return impl
+	}
+	tokenFile := os.Getenv(tokenFileEnv)
+	if tokenFile == "" {
+		tokenFile = defaultTokenFile
+	}
+	client, err := NewClient(Options{Address: address, CAFile: os.Getenv(caFileEnv), Token: TokenFile(tokenFile)})
+	if err != nil {
+		logger.Errorf("Failed to connect to Tekton Results at %s, ApprovalRecords are not published: %v", address, err)
+		return impl
+	}
+	r.results = client
+
+	if _, err := approvalrecordInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    impl.Enqueue,
+		UpdateFunc: controller.PassNew(impl.Enqueue),
+	}); err != nil {
+		logger.Panicf("couldn't register ApprovalRecord informer event handler: %v", err)
+	}
+	return impl
+}
+
+// Reconcile implements controller.Reconciler
+func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
+	logger := logging.FromContext(ctx)
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		logger.Errorw("Invalid resource key", zap.String("key", key), zap.Error(err))
+		return nil
+	}
+
+	if r.results == nil {
+		return nil
+	}
+	if !r.IsLeaderFor(types.NamespacedName{Namespace: namespace, Name: name}) {
+		return controller.NewSkipKey(key)
+	}
+
+	record, err := r.lister.ApprovalRecords(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if record.Annotations[RecordAnnotation] != "" {
+		return nil
+	}
+	// The Result of a PipelineRun is named after its UID
+	if record.Spec.PipelineRun == nil || record.Spec.PipelineRun.UID == "" {
+		logger.Debugf("ApprovalRecord %s/%s has no PipelineRun, it is not published to Tekton Results", namespace, name)
+		return nil
+	}
+
+	data, err := recordData(record)
+	if err != nil {
+		return err
+	}
+	recordID := record.Spec.ApprovalTask.UID
+	if recordID == "" {
+		recordID = string(record.UID)
+	}
+	recordName, err := r.results.Publish(ctx, namespace, record.Spec.PipelineRun.UID, recordID, data)
+	if err != nil {
+		return err
+	}
+	logger.Infof("ApprovalRecord %s/%s published to Tekton Results as %s", namespace, name, recordName)
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{RecordAnnotation: recordName},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = r.approvaltaskClientSet.OpenshiftpipelinesV1alpha1().ApprovalRecords(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// recordData returns the payload of the Record of an ApprovalRecord.
+func recordData(record *v1alpha1.ApprovalRecord) (*Any, error) {
+	record = record.DeepCopy()
+	record.APIVersion = v1alpha1.SchemeGroupVersion.String()
+	record.Kind = "ApprovalRecord"
+	record.ManagedFields = nil
+	value, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	return &Any{Type: RecordType, Value: value}, nil
+}
+
+// DecodeRecord returns the ApprovalRecord of a Record.
+func DecodeRecord(record *Record) (*v1alpha1.ApprovalRecord, error) {
+	if record.Data == nil || record.Data.Type != RecordType {
+		return nil, nil
+	}
+	var approvalRecord v1alpha1.ApprovalRecord
+	if err := json.Unmarshal(record.Data.Value, &approvalRecord); err != nil {
+		return nil, err
+	}
+	return &approvalRecord, nil
+}
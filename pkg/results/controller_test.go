@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package results
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/apis/approvaltask/v1alpha1"
+	fakeclientset "github.com/openshift-pipelines/manual-approval-gate/pkg/client/clientset/versioned/fake"
+	listersapprovaltask "github.com/openshift-pipelines/manual-approval-gate/pkg/client/listers/approvaltask/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	pkgreconciler "knative.dev/pkg/reconciler"
+)
+
+// recordingConn answers CreateRecord and GetResult without a server.
+type recordingConn struct {
+	created []*CreateRecordRequest
+}
+
+func (c *recordingConn) invoker(_ context.Context, method string, req, reply any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+	switch method {
+	case "/" + ServiceName + "/GetResult":
+		*reply.(*Result) = Result{Name: req.(*GetResultRequest).Name}
+	case "/" + ServiceName + "/CreateRecord":
+		c.created = append(c.created, req.(*CreateRecordRequest))
+		*reply.(*Record) = *req.(*CreateRecordRequest).Record
+	}
+	return nil
+}
+
+func TestReconcilePublishesApprovalRecord(t *testing.T) {
+	ctx := metadata.NewOutgoingContext(context.Background(), nil)
+	record := &v1alpha1.ApprovalRecord{
+		ObjectMeta: metav1.ObjectMeta{Name: "bar-01234567", Namespace: "foo", UID: "record-uid"},
+		Spec: v1alpha1.ApprovalRecordSpec{
+			ApprovalTask: v1alpha1.ApprovalRecordReference{Name: "bar", UID: "approvaltask-uid"},
+			PipelineRun:  &v1alpha1.ApprovalRecordPipelineRun{Name: "release", UID: "pipelinerun-uid"},
+			Decision:     "approved",
+		},
+	}
+	standalone := &v1alpha1.ApprovalRecord{
+		ObjectMeta: metav1.ObjectMeta{Name: "baz-89abcdef", Namespace: "foo"},
+		Spec:       v1alpha1.ApprovalRecordSpec{ApprovalTask: v1alpha1.ApprovalRecordReference{Name: "baz"}, Decision: "rejected"},
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, r := range []*v1alpha1.ApprovalRecord{record, standalone} {
+		if err := indexer.Add(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clientset := fakeclientset.NewSimpleClientset(record, standalone)
+
+	conn := &recordingConn{}
+	cc, err := grpc.NewClient("passthrough:///results", grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, _ grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return conn.invoker(ctx, method, req, reply, cc, opts...)
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+
+	r := &Reconciler{
+		results:               &Client{conn: cc},
+		approvaltaskClientSet: clientset,
+		lister:                listersapprovaltask.NewApprovalRecordLister(indexer),
+	}
+	if err := r.Promote(pkgreconciler.UniversalBucket(), func(pkgreconciler.Bucket, types.NamespacedName) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"foo/bar-01234567", "foo/baz-89abcdef"} {
+		if err := r.Reconcile(ctx, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if assert.Len(t, conn.created, 1) {
+		req := conn.created[0]
+		assert.Equal(t, "foo/results/pipelinerun-uid", req.Parent)
+		assert.Equal(t, "foo/results/pipelinerun-uid/records/approvaltask-uid", req.Record.Name)
+		published, err := DecodeRecord(req.Record)
+		assert.NoError(t, err)
+		assert.Equal(t, "ApprovalRecord", published.Kind)
+		assert.Equal(t, record.Spec, published.Spec)
+	}
+
+	got, err := clientset.OpenshiftpipelinesV1alpha1().ApprovalRecords("foo").Get(ctx, "bar-01234567", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "foo/results/pipelinerun-uid/records/approvaltask-uid", got.Annotations[RecordAnnotation])
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake is an in-memory Results API for tests.
+package fake
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/results"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Server stores Results and Records in memory.
+type Server struct {
+	// Token, when set, is the bearer token every call must carry
+	Token string
+
+	mu      sync.Mutex
+	results map[string]*results.Result
+	records []*results.Record
+	// Filters holds the filter of every ListRecords call
+	Filters []string
+}
+
+// NewServer returns an empty Server.
+func NewServer() *Server {
+	return &Server{results: map[string]*results.Result{}}
+}
+
+// Start serves the Results API on a local port until the end of the test and
+// returns its address.
+func (s *Server) Start(t testing.TB) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(results.ServerOption())
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: results.ServiceName,
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "GetResult", Handler: handler(s, s.getResult)},
+			{MethodName: "CreateResult", Handler: handler(s, s.createResult)},
+			{MethodName: "CreateRecord", Handler: handler(s, s.createRecord)},
+			{MethodName: "ListRecords", Handler: handler(s, s.listRecords)},
+		},
+	}, s)
+	go server.Serve(listener) //nolint:errcheck
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+// Records returns the Records stored so far.
+func (s *Server) Records() []*results.Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*results.Record(nil), s.records...)
+}
+
+// AddRecord stores a Record, e.g. one published before the test.
+func (s *Server) AddRecord(record *results.Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+}
+
+func handler[Req any, PReq interface {
+	*Req
+}, Resp any](s *Server, fn func(PReq) (Resp, error)) func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) {
+	return func(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+		if s.Token != "" {
+			md, _ := metadata.FromIncomingContext(ctx)
+			if auth := md.Get("authorization"); len(auth) != 1 || auth[0] != "Bearer "+s.Token {
+				return nil, status.Error(codes.Unauthenticated, "invalid token")
+			}
+		}
+		req := PReq(new(Req))
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return fn(req)
+	}
+}
+
+func (s *Server) getResult(req *results.GetResultRequest) (*results.Result, error) {
+	if result, ok := s.results[req.Name]; ok {
+		return result, nil
+	}
+	return nil, status.Errorf(codes.NotFound, "result %s not found", req.Name)
+}
+
+func (s *Server) createResult(req *results.CreateResultRequest) (*results.Result, error) {
+	if req.Result == nil || !strings.HasPrefix(req.Result.Name, req.Parent+"/results/") {
+		return nil, status.Error(codes.InvalidArgument, "result name must be in the parent namespace")
+	}
+	if _, ok := s.results[req.Result.Name]; ok {
+		return nil, status.Errorf(codes.AlreadyExists, "result %s already exists", req.Result.Name)
+	}
+	s.results[req.Result.Name] = req.Result
+	return req.Result, nil
+}
+
+func (s *Server) createRecord(req *results.CreateRecordRequest) (*results.Record, error) {
+	if _, ok := s.results[req.Parent]; !ok {
+		return nil, status.Errorf(codes.NotFound, "result %s not found", req.Parent)
+	}
+	if req.Record == nil || !strings.HasPrefix(req.Record.Name, req.Parent+"/records/") {
+		return nil, status.Error(codes.InvalidArgument, "record name must be in the parent result")
+	}
+	for _, record := range s.records {
+		if record.Name == req.Record.Name {
+			return nil, status.Errorf(codes.AlreadyExists, "record %s already exists", req.Record.Name)
+		}
+	}
+	s.records = append(s.records, req.Record)
+	return req.Record, nil
+}
+
+// listRecords matches the Records of the parent, - standing for any Result
+// or namespace. Filters are recorded but not evaluated, except for the
+// data_type.
+func (s *Server) listRecords(req *results.ListRecordsRequest) (*results.ListRecordsResponse, error) {
+	s.Filters = append(s.Filters, req.Filter)
+	namespace, result, ok := strings.Cut(req.Parent, "/results/")
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid parent %s", req.Parent)
+	}
+	var matching []*results.Record
+	for _, record := range s.records {
+		if namespace != "-" && !strings.HasPrefix(record.Name, namespace+"/results/") {
+			continue
+		}
+		if result != "-" && !strings.HasPrefix(record.Name, req.Parent+"/records/") {
+			continue
+		}
+		if record.Data != nil && strings.Contains(req.Filter, "data_type") && !strings.Contains(req.Filter, strconv.Quote(record.Data.Type)) {
+			continue
+		}
+		matching = append(matching, record)
+	}
+
+	start, _ := strconv.Atoi(req.PageToken)
+	end := len(matching)
+	if req.PageSize > 0 && start+int(req.PageSize) < end {
+		end = start + int(req.PageSize)
+	}
+	resp := &results.ListRecordsResponse{Records: matching[start:end]}
+	if end < len(matching) {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	return resp, nil
+}
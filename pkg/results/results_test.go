@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package results_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/results"
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/results/fake"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func client(t *testing.T, address, token string) *results.Client {
+	opts := results.Options{Address: address, Insecure: true}
+	if token != "" {
+		opts.Token = func() (string, error) { return token, nil }
+	}
+	c, err := results.NewClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestPublish(t *testing.T) {
+	ctx := context.Background()
+	server := fake.NewServer()
+	server.Token = "s3cr3t"
+	c := client(t, server.Start(t), "s3cr3t")
+
+	data := &results.Any{Type: results.RecordType, Value: []byte(`{"kind":"ApprovalRecord"}`)}
+	name, err := c.Publish(ctx, "foo", "pipelinerun-uid", "approvaltask-uid", data)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo/results/pipelinerun-uid/records/approvaltask-uid", name)
+
+	// Publishing again, e.g. after a failed annotation, is a no-op
+	_, err = c.Publish(ctx, "foo", "pipelinerun-uid", "approvaltask-uid", data)
+	assert.NoError(t, err)
+
+	records := server.Records()
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, name, records[0].Name)
+		assert.Equal(t, data, records[0].Data)
+	}
+
+	resp, err := c.ListRecords(ctx, &results.ListRecordsRequest{
+		Parent: results.ResultName("foo", "-"),
+		Filter: `data_type == "` + results.RecordType + `"`,
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, resp.Records, 1) {
+		assert.Equal(t, data, resp.Records[0].Data)
+	}
+}
+
+func TestPublishUnauthenticated(t *testing.T) {
+	server := fake.NewServer()
+	server.Token = "s3cr3t"
+	c := client(t, server.Start(t), "wrong")
+
+	_, err := c.Publish(context.Background(), "foo", "pipelinerun-uid", "approvaltask-uid", &results.Any{Type: results.RecordType})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Empty(t, server.Records())
+}
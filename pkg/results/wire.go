@@ -0,0 +1,351 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package results
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The messages of the tekton.results.v1alpha2 API used by the integration.
+// Only the fields this package reads or writes are encoded; the field numbers
+// are the ones of the Results protos, and unknown fields are skipped.
+
+// Any is the payload of a Record.
+type Any struct {
+	// Type identifies the payload, e.g. openshift-pipelines.org/v1alpha1.ApprovalRecord
+	Type  string
+	Value []byte
+}
+
+// Result groups the Records of a PipelineRun.
+type Result struct {
+	// Name is <namespace>/results/<id>
+	Name string
+	ID   string
+	UID  string
+}
+
+// Record is a stored object of a Result.
+type Record struct {
+	// Name is <namespace>/results/<result id>/records/<id>
+	Name string
+	ID   string
+	UID  string
+	Data *Any
+	Etag string
+}
+
+// GetResultRequest gets a Result by name.
+type GetResultRequest struct {
+	Name string
+}
+
+// CreateResultRequest creates a Result in the parent namespace.
+type CreateResultRequest struct {
+	Parent string
+	Result *Result
+}
+
+// CreateRecordRequest creates a Record in the parent Result.
+type CreateRecordRequest struct {
+	Parent string
+	Record *Record
+}
+
+// ListRecordsRequest lists the Records of the parent Result, which is - for
+// all the Results of the namespace, matching the CEL filter.
+type ListRecordsRequest struct {
+	Parent    string
+	Filter    string
+	PageSize  int32
+	PageToken string
+	OrderBy   string
+}
+
+// ListRecordsResponse is a page of Records.
+type ListRecordsResponse struct {
+	Records       []*Record
+	NextPageToken string
+}
+
+// message is implemented by the messages above.
+type message interface {
+	marshal(b []byte) []byte
+	unmarshal(b []byte) error
+}
+
+func appendString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+func appendMessage(b []byte, num protowire.Number, m message) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, m.marshal(nil))
+}
+
+// fields calls fn with the number, type and value of every field of b. The
+// value of a length-delimited field is its content.
+func fields(b []byte, fn func(num protowire.Number, typ protowire.Type, v []byte, n uint64) error) error {
+	for len(b) > 0 {
+		num, typ, l := protowire.ConsumeTag(b)
+		if l < 0 {
+			return protowire.ParseError(l)
+		}
+		b = b[l:]
+		var (
+			v []byte
+			n uint64
+		)
+		switch typ {
+		case protowire.BytesType:
+			v, l = protowire.ConsumeBytes(b)
+		case protowire.VarintType:
+			n, l = protowire.ConsumeVarint(b)
+		default:
+			l = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if l < 0 {
+			return protowire.ParseError(l)
+		}
+		b = b[l:]
+		if err := fn(num, typ, v, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Any) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Type)
+	return appendBytes(b, 2, m.Value)
+}
+
+func (m *Any) unmarshal(b []byte) error {
+	return fields(b, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+		switch num {
+		case 1:
+			m.Type = string(v)
+		case 2:
+			m.Value = append([]byte(nil), v...)
+		}
+		return nil
+	})
+}
+
+func (m *Result) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Name)
+	b = appendString(b, 2, m.ID)
+	return appendString(b, 7, m.UID)
+}
+
+func (m *Result) unmarshal(b []byte) error {
+	return fields(b, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+		switch num {
+		case 1:
+			m.Name = string(v)
+		case 2:
+			m.ID = string(v)
+		case 7:
+			m.UID = string(v)
+		}
+		return nil
+	})
+}
+
+func (m *Record) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Name)
+	b = appendString(b, 2, m.ID)
+	if m.Data != nil {
+		b = appendMessage(b, 3, m.Data)
+	}
+	b = appendString(b, 4, m.Etag)
+	return appendString(b, 7, m.UID)
+}
+
+func (m *Record) unmarshal(b []byte) error {
+	return fields(b, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+		switch num {
+		case 1:
+			m.Name = string(v)
+		case 2:
+			m.ID = string(v)
+		case 3:
+			m.Data = &Any{}
+			return m.Data.unmarshal(v)
+		case 4:
+			m.Etag = string(v)
+		case 7:
+			m.UID = string(v)
+		}
+		return nil
+	})
+}
+
+func (m *GetResultRequest) marshal(b []byte) []byte {
+	return appendString(b, 1, m.Name)
+}
+
+func (m *GetResultRequest) unmarshal(b []byte) error {
+	return fields(b, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+		if num == 1 {
+			m.Name = string(v)
+		}
+		return nil
+	})
+}
+
+func (m *CreateResultRequest) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Parent)
+	if m.Result != nil {
+		b = appendMessage(b, 2, m.Result)
+	}
+	return b
+}
+
+func (m *CreateResultRequest) unmarshal(b []byte) error {
+	return fields(b, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+		switch num {
+		case 1:
+			m.Parent = string(v)
+		case 2:
+			m.Result = &Result{}
+			return m.Result.unmarshal(v)
+		}
+		return nil
+	})
+}
+
+func (m *CreateRecordRequest) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Parent)
+	if m.Record != nil {
+		b = appendMessage(b, 2, m.Record)
+	}
+	return b
+}
+
+func (m *CreateRecordRequest) unmarshal(b []byte) error {
+	return fields(b, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+		switch num {
+		case 1:
+			m.Parent = string(v)
+		case 2:
+			m.Record = &Record{}
+			return m.Record.unmarshal(v)
+		}
+		return nil
+	})
+}
+
+func (m *ListRecordsRequest) marshal(b []byte) []byte {
+	b = appendString(b, 1, m.Parent)
+	b = appendString(b, 2, m.Filter)
+	if m.PageSize != 0 {
+		b = protowire.AppendTag(b, 3, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(m.PageSize))
+	}
+	b = appendString(b, 4, m.PageToken)
+	return appendString(b, 5, m.OrderBy)
+}
+
+func (m *ListRecordsRequest) unmarshal(b []byte) error {
+	return fields(b, func(num protowire.Number, _ protowire.Type, v []byte, n uint64) error {
+		switch num {
+		case 1:
+			m.Parent = string(v)
+		case 2:
+			m.Filter = string(v)
+		case 3:
+			m.PageSize = int32(n)
+		case 4:
+			m.PageToken = string(v)
+		case 5:
+			m.OrderBy = string(v)
+		}
+		return nil
+	})
+}
+
+func (m *ListRecordsResponse) marshal(b []byte) []byte {
+	for _, record := range m.Records {
+		b = appendMessage(b, 1, record)
+	}
+	return appendString(b, 2, m.NextPageToken)
+}
+
+func (m *ListRecordsResponse) unmarshal(b []byte) error {
+	return fields(b, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+		switch num {
+		case 1:
+			record := &Record{}
+			if err := record.unmarshal(v); err != nil {
+				return err
+			}
+			m.Records = append(m.Records, record)
+		case 2:
+			m.NextPageToken = string(v)
+		}
+		return nil
+	})
+}
+
+// codec encodes the messages above on the wire as protobuf, so that the
+// default content type of gRPC is used.
+type codec struct{}
+
+var _ encoding.Codec = codec{}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(message)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return m.marshal(nil), nil
+}
+
+func (codec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(message)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	return m.unmarshal(data)
+}
+
+func (codec) Name() string {
+	return "proto"
+}
+
+// ServerOption makes a gRPC server encode the messages of this package, e.g.
+// a fake Results API.
+func ServerOption() grpc.ServerOption {
+	return grpc.ForceServerCodec(codec{})
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package signature signs and verifies the responses of approvers, so that
+// an approval proves the approver held their personal key on top of being
+// allowed to update the ApprovalTask.
+package signature
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-pipelines/manual-approval-gate/pkg/attestation"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Namespace is the SSH signature namespace of the responses, passed as
+	// ssh-keygen -Y sign -n
+	Namespace = "manual-approval-gate"
+
+	// payloadVersion identifies the format of the signed payload
+	payloadVersion = "openshift-pipelines.org/v1alpha1.ApprovalResponse"
+
+	sshSignatureHeader = "-----BEGIN SSH SIGNATURE-----"
+)
+
+// Response is what an approver signs: their input on one ApprovalTask,
+// identified by UID so that the signature cannot be replayed on another one.
+type Response struct {
+	Namespace string
+	Name      string
+	UID       string
+	User      string
+	Input     string
+	Message   string
+}
+
+// Payload returns the bytes signed for the response.
+func (r Response) Payload() []byte {
+	b, _ := json.Marshal(struct {
+		Version   string `json:"version"`
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+		UID       string `json:"uid"`
+		User      string `json:"user"`
+		Input     string `json:"input"`
+		Message   string `json:"message"`
+	}{payloadVersion, r.Namespace, r.Name, r.UID, r.User, r.Input, r.Message})
+	return b
+}
+
+// Keys maps usernames to their public keys, either OpenSSH authorized keys
+// or PEM encoded keys.
+type Keys map[string][]string
+
+// ParseKeys reads the keys of a ConfigMap, each entry of which is a YAML
+// mapping of usernames to lists of public keys.
+func ParseKeys(data map[string]string) (Keys, error) {
+	keys := Keys{}
+	for name, raw := range data {
+		entry := map[string][]string{}
+		if err := yaml.Unmarshal([]byte(raw), &entry); err != nil {
+			return nil, fmt.Errorf("invalid keys in %s: %w", name, err)
+		}
+		for user, userKeys := range entry {
+			keys[user] = append(keys[user], userKeys...)
+		}
+	}
+	return keys, nil
+}
+
+// Verify checks that the signature of the response was made with one of the
+// keys. SSH signatures are armored, other signatures base64 encoded.
+func Verify(response Response, signature string, keys []string) error {
+	if signature == "" {
+		return errors.New("the response is not signed")
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no public key is registered for %s", response.User)
+	}
+	payload := response.Payload()
+	signature = strings.TrimSpace(signature)
+
+	if strings.HasPrefix(signature, sshSignatureHeader) {
+		for _, key := range keys {
+			if !isPEM(key) && verifySSH(key, payload, signature) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("the signature of %s does not match any of their SSH keys", response.User)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	for _, key := range keys {
+		if isPEM(key) && attestation.VerifyMessage([]byte(key), payload, sig) == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("the signature of %s does not match any of their keys", response.User)
+}
+
+func isPEM(key string) bool {
+	return strings.HasPrefix(strings.TrimSpace(key), "-----BEGIN ")
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signature
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var response = Response{Namespace: "foo", Name: "bar", UID: "uid", User: "alice", Input: "approve", Message: "LGTM"}
+
+func sshKey(t *testing.T, keyType string) (string, string) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not installed")
+	}
+	path := filepath.Join(t.TempDir(), "id_"+keyType)
+	if out, err := exec.Command("ssh-keygen", "-q", "-t", keyType, "-N", "", "-C", "alice@laptop", "-f", path).CombinedOutput(); err != nil {
+		t.Fatalf("ssh-keygen: %v: %s", err, out)
+	}
+	pub, err := os.ReadFile(path + ".pub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, string(pub)
+}
+
+func TestSSHSignature(t *testing.T) {
+	for _, keyType := range []string{"ed25519", "ecdsa", "rsa"} {
+		t.Run(keyType, func(t *testing.T) {
+			path, pub := sshKey(t, keyType)
+			_, otherPub := sshKey(t, keyType)
+
+			signature, err := Sign(path, response)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.NoError(t, Verify(response, signature, []string{otherPub, pub}))
+
+			assert.ErrorContains(t, Verify(response, signature, []string{otherPub}), "does not match any of their SSH keys")
+
+			rejected := response
+			rejected.Input = "reject"
+			assert.Error(t, Verify(rejected, signature, []string{pub}))
+
+			replayed := response
+			replayed.UID = "other-uid"
+			assert.Error(t, Verify(replayed, signature, []string{pub}))
+		})
+	}
+}
+
+func TestSSHSignatureOfAnotherNamespace(t *testing.T) {
+	path, pub := sshKey(t, "ed25519")
+	cmd := exec.Command("ssh-keygen", "-Y", "sign", "-q", "-f", path, "-n", "git")
+	cmd.Stdin = bytes.NewReader(response.Payload())
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.ErrorContains(t, verifySSH(pub, response.Payload(), string(out)), `SSH signature of namespace "git"`)
+}
+
+func TestPEMSignature(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cosign.key")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+
+	signature, err := Sign(path, response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, Verify(response, signature, []string{"ssh-ed25519 AAAA alice@laptop", pub}))
+
+	changed := response
+	changed.Message = "not what was signed"
+	assert.ErrorContains(t, Verify(changed, signature, []string{pub}), "does not match any of their keys")
+}
+
+func TestVerifyErrors(t *testing.T) {
+	assert.EqualError(t, Verify(response, "", []string{"key"}), "the response is not signed")
+	assert.EqualError(t, Verify(response, "c2ln", nil), "no public key is registered for alice")
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys(map[string]string{
+		"team-a.yaml": "alice:\n- ssh-ed25519 AAAA alice@laptop\n",
+		"team-b.yaml": "alice:\n- ssh-ed25519 BBBB alice@desktop\nbob@example.com:\n- |\n  -----BEGIN PUBLIC KEY-----\n  MFkw\n  -----END PUBLIC KEY-----\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.ElementsMatch(t, []string{"ssh-ed25519 AAAA alice@laptop", "ssh-ed25519 BBBB alice@desktop"}, keys["alice"])
+	assert.Equal(t, []string{"-----BEGIN PUBLIC KEY-----\nMFkw\n-----END PUBLIC KEY-----\n"}, keys["bob@example.com"])
+
+	_, err = ParseKeys(map[string]string{"broken": "alice: ["})
+	assert.ErrorContains(t, err, "invalid keys in broken")
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"strings"
+)
+
+// The SSH signatures are made by ssh-keygen -Y sign, in the format of
+// https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig
+
+const sshsigMagic = "SSHSIG"
+
+// reader reads the SSH wire encoding of RFC 4251.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) uint32() uint32 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) < 4 {
+		r.err = errors.New("truncated data")
+		return 0
+	}
+	v := binary.BigEndian.Uint32(r.data)
+	r.data = r.data[4:]
+	return v
+}
+
+func (r *reader) string() []byte {
+	n := r.uint32()
+	if r.err != nil {
+		return nil
+	}
+	if uint32(len(r.data)) < n {
+		r.err = errors.New("truncated data")
+		return nil
+	}
+	v := r.data[:n]
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *reader) mpint() *big.Int {
+	return new(big.Int).SetBytes(r.string())
+}
+
+func appendString(b []byte, s []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// parseAuthorizedKey returns the wire encoding of an authorized key line,
+// e.g. "ssh-ed25519 AAAA... alice@laptop", and its public key.
+func parseAuthorizedKey(line string) ([]byte, crypto.PublicKey, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil, nil, errors.New("invalid SSH public key")
+	}
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := parsePublicKey(blob)
+	if err != nil {
+		return nil, nil, err
+	}
+	return blob, key, nil
+}
+
+func parsePublicKey(blob []byte) (crypto.PublicKey, error) {
+	r := &reader{data: blob}
+	keyType := string(r.string())
+	var key crypto.PublicKey
+	switch keyType {
+	case "ssh-ed25519":
+		raw := r.string()
+		if r.err == nil && len(raw) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid ed25519 key")
+		}
+		key = ed25519.PublicKey(raw)
+	case "ecdsa-sha2-nistp256", "ecdsa-sha2-nistp384", "ecdsa-sha2-nistp521":
+		r.string() // curve name, implied by the type
+		point := r.string()
+		if r.err != nil {
+			break
+		}
+		k, err := ecdsa.ParseUncompressedPublicKey(curveOf(keyType), point)
+		if err != nil {
+			return nil, err
+		}
+		key = k
+	case "ssh-rsa":
+		e := r.mpint()
+		n := r.mpint()
+		if r.err == nil && !e.IsInt64() {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		key = &rsa.PublicKey{N: n, E: int(e.Int64())}
+	default:
+		return nil, fmt.Errorf("unsupported SSH key type %q", keyType)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return key, nil
+}
+
+func curveOf(keyType string) elliptic.Curve {
+	switch keyType {
+	case "ecdsa-sha2-nistp384":
+		return elliptic.P384()
+	case "ecdsa-sha2-nistp521":
+		return elliptic.P521()
+	}
+	return elliptic.P256()
+}
+
+// verifySSH checks an armored SSH signature of the message against an
+// authorized key.
+func verifySSH(authorizedKey string, message []byte, armored string) error {
+	blob, key, err := parseAuthorizedKey(authorizedKey)
+	if err != nil {
+		return err
+	}
+	block, _ := pem.Decode([]byte(armored))
+	if block == nil || block.Type != "SSH SIGNATURE" {
+		return errors.New("invalid SSH signature")
+	}
+
+	data := block.Bytes
+	if !bytes.HasPrefix(data, []byte(sshsigMagic)) {
+		return errors.New("invalid SSH signature")
+	}
+	r := &reader{data: data[len(sshsigMagic):]}
+	version := r.uint32()
+	signer := r.string()
+	namespace := r.string()
+	reserved := r.string()
+	hashAlgorithm := string(r.string())
+	sig := r.string()
+	if r.err != nil {
+		return r.err
+	}
+	if version != 1 {
+		return fmt.Errorf("unsupported SSH signature version %d", version)
+	}
+	if string(namespace) != Namespace {
+		return fmt.Errorf("SSH signature of namespace %q instead of %q", namespace, Namespace)
+	}
+	if !bytes.Equal(signer, blob) {
+		return errors.New("SSH signature made with another key")
+	}
+
+	var h hash.Hash
+	switch hashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported SSH signature hash %q", hashAlgorithm)
+	}
+	h.Write(message)
+	signed := []byte(sshsigMagic)
+	signed = appendString(signed, namespace)
+	signed = appendString(signed, reserved)
+	signed = appendString(signed, []byte(hashAlgorithm))
+	signed = appendString(signed, h.Sum(nil))
+
+	sr := &reader{data: sig}
+	format := string(sr.string())
+	sigBlob := sr.string()
+	if sr.err != nil {
+		return sr.err
+	}
+	return verifySSHSignature(key, format, signed, sigBlob)
+}
+
+func verifySSHSignature(key crypto.PublicKey, format string, signed, sig []byte) error {
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		if format == "ssh-ed25519" && ed25519.Verify(k, signed, sig) {
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		var h hash.Hash
+		switch k.Curve {
+		case elliptic.P384():
+			h = sha512.New384()
+		case elliptic.P521():
+			h = sha512.New()
+		default:
+			h = sha256.New()
+		}
+		h.Write(signed)
+		r := &reader{data: sig}
+		rr, ss := r.mpint(), r.mpint()
+		if r.err == nil && ecdsa.Verify(k, h.Sum(nil), rr, ss) {
+			return nil
+		}
+	case *rsa.PublicKey:
+		var digest []byte
+		var hashFunc crypto.Hash
+		switch format {
+		case "rsa-sha2-256":
+			sum := sha256.Sum256(signed)
+			digest, hashFunc = sum[:], crypto.SHA256
+		case "rsa-sha2-512":
+			sum := sha512.Sum512(signed)
+			digest, hashFunc = sum[:], crypto.SHA512
+		default:
+			return fmt.Errorf("unsupported RSA signature %q", format)
+		}
+		if rsa.VerifyPKCS1v15(k, hashFunc, digest, sig) == nil {
+			return nil
+		}
+	}
+	return errors.New("invalid SSH signature")
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The OpenShift Pipelines Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing propagates the OpenTelemetry span context of PipelineRuns
+// to the ApprovalTasks they wait on.
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// SpanContextAnnotation carries the span context, a JSON encoded map of
+	// the text map propagator, under which the CustomRun is traced. It is
+	// the annotation Tekton reads on CustomRuns, and is copied to the
+	// ApprovalTask.
+	SpanContextAnnotation = "tekton.dev/customrunSpanContext"
+
+	// PipelineRunSpanContextAnnotation carries the span context of the parent
+	// of a PipelineRun; Tekton propagates it to the CustomRuns of the
+	// PipelineRun.
+	PipelineRunSpanContextAnnotation = "tekton.dev/pipelinerunSpanContext"
+)
+
+// Carrier returns the span context propagated in the annotation key, or nil
+// when there is none.
+func Carrier(annotations map[string]string, key string) propagation.MapCarrier {
+	value := annotations[key]
+	if value == "" {
+		return nil
+	}
+	carrier := propagation.MapCarrier{}
+	if err := json.Unmarshal([]byte(value), &carrier); err != nil || len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Marshal encodes carrier as the value of a span context annotation.
+func Marshal(carrier propagation.MapCarrier) (string, error) {
+	b, err := json.Marshal(carrier)
+	return string(b), err
+}
+
+// Extract returns ctx with the remote span context of carrier.
+func Extract(ctx context.Context, carrier propagation.MapCarrier) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
+
+// SpanContextFromAnnotations returns the span context propagated in the
+// SpanContextAnnotation, which is invalid when there is none.
+func SpanContextFromAnnotations(annotations map[string]string) trace.SpanContext {
+	carrier := Carrier(annotations, SpanContextAnnotation)
+	if carrier == nil {
+		return trace.SpanContext{}
+	}
+	return trace.SpanContextFromContext(Extract(context.Background(), carrier))
+}
@@ -177,3 +177,34 @@ func TestOTelMetricsController(t *testing.T) {
 		})
 	}
 }
+
+// TestOTelMetricsApprovalTasks verifies that the controller exposes the
+// metrics of the ApprovalTasks created by the e2e tests.
+func TestOTelMetricsApprovalTasks(t *testing.T) {
+	ctx := context.Background()
+	kubeClient := magKubeClient(t)
+
+	t.Log("Waiting for approvaltask_created_total to appear on controller")
+	families := waitForMagMetric(ctx, t, kubeClient, "approvaltask_created_total", 2*time.Minute)
+
+	for _, name := range []string{
+		"approvaltask_created_total",
+		"approvaltask_decided_total",
+	} {
+		t.Run(name, func(t *testing.T) {
+			family, ok := families[name]
+			if !ok {
+				t.Fatalf("Expected metric %q, found none", name)
+			}
+			for _, m := range family.GetMetric() {
+				for _, label := range m.GetLabel() {
+					if label.GetName() == "namespace" {
+						return
+					}
+				}
+			}
+			t.Errorf("Expected metric %q to have a namespace label", name)
+		})
+	}
+}
+